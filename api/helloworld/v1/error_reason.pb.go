@@ -24,19 +24,46 @@ const (
 type ErrorReason int32
 
 const (
-	ErrorReason_GREETER_UNSPECIFIED ErrorReason = 0
-	ErrorReason_USER_NOT_FOUND      ErrorReason = 1
+	ErrorReason_GREETER_UNSPECIFIED          ErrorReason = 0
+	ErrorReason_USER_NOT_FOUND               ErrorReason = 1
+	ErrorReason_PERMISSION_DENIED            ErrorReason = 2
+	ErrorReason_IDEMPOTENCY_KEY_INVALID      ErrorReason = 3
+	ErrorReason_IDEMPOTENCY_IN_PROGRESS      ErrorReason = 4
+	ErrorReason_IDEMPOTENCY_KEY_REUSED       ErrorReason = 5
+	ErrorReason_TENANT_REQUIRED              ErrorReason = 6
+	ErrorReason_TENANT_MISMATCH              ErrorReason = 7
+	ErrorReason_IDEMPOTENCY_UNAVAILABLE      ErrorReason = 8
+	ErrorReason_IDEMPOTENCY_RESULT_CORRUPTED ErrorReason = 9
+	ErrorReason_TENANT_FORBIDDEN             ErrorReason = 10
 )
 
 // Enum value maps for ErrorReason.
 var (
 	ErrorReason_name = map[int32]string{
-		0: "GREETER_UNSPECIFIED",
-		1: "USER_NOT_FOUND",
+		0:  "GREETER_UNSPECIFIED",
+		1:  "USER_NOT_FOUND",
+		2:  "PERMISSION_DENIED",
+		3:  "IDEMPOTENCY_KEY_INVALID",
+		4:  "IDEMPOTENCY_IN_PROGRESS",
+		5:  "IDEMPOTENCY_KEY_REUSED",
+		6:  "TENANT_REQUIRED",
+		7:  "TENANT_MISMATCH",
+		8:  "IDEMPOTENCY_UNAVAILABLE",
+		9:  "IDEMPOTENCY_RESULT_CORRUPTED",
+		10: "TENANT_FORBIDDEN",
 	}
 	ErrorReason_value = map[string]int32{
-		"GREETER_UNSPECIFIED": 0,
-		"USER_NOT_FOUND":      1,
+		"GREETER_UNSPECIFIED":          0,
+		"USER_NOT_FOUND":               1,
+		"PERMISSION_DENIED":            2,
+		"IDEMPOTENCY_KEY_INVALID":      3,
+		"IDEMPOTENCY_IN_PROGRESS":      4,
+		"IDEMPOTENCY_KEY_REUSED":       5,
+		"TENANT_REQUIRED":              6,
+		"TENANT_MISMATCH":              7,
+		"IDEMPOTENCY_UNAVAILABLE":      8,
+		"IDEMPOTENCY_RESULT_CORRUPTED": 9,
+		"TENANT_FORBIDDEN":             10,
 	}
 )
 
@@ -71,10 +98,20 @@ var File_helloworld_v1_error_reason_proto protoreflect.FileDescriptor
 
 const file_helloworld_v1_error_reason_proto_rawDesc = "" +
 	"\n" +
-	" helloworld/v1/error_reason.proto\x12\rhelloworld.v1*:\n" +
+	" helloworld/v1/error_reason.proto\x12\rhelloworld.v1*\xa6\x02\n" +
 	"\vErrorReason\x12\x17\n" +
 	"\x13GREETER_UNSPECIFIED\x10\x00\x12\x12\n" +
-	"\x0eUSER_NOT_FOUND\x10\x01B\\\n" +
+	"\x0eUSER_NOT_FOUND\x10\x01\x12\x15\n" +
+	"\x11PERMISSION_DENIED\x10\x02\x12\x1b\n" +
+	"\x17IDEMPOTENCY_KEY_INVALID\x10\x03\x12\x1b\n" +
+	"\x17IDEMPOTENCY_IN_PROGRESS\x10\x04\x12\x1a\n" +
+	"\x16IDEMPOTENCY_KEY_REUSED\x10\x05\x12\x13\n" +
+	"\x0fTENANT_REQUIRED\x10\x06\x12\x13\n" +
+	"\x0fTENANT_MISMATCH\x10\a\x12\x1b\n" +
+	"\x17IDEMPOTENCY_UNAVAILABLE\x10\b\x12 \n" +
+	"\x1cIDEMPOTENCY_RESULT_CORRUPTED\x10\t\x12\x14\n" +
+	"\x10TENANT_FORBIDDEN\x10\n" +
+	"B\\\n" +
 	"\rhelloworld.v1P\x01Z7github.com/go-kratos/kratos-layout/api/helloworld/v1;v1\xa2\x02\x0fAPIHelloworldV1b\x06proto3"
 
 var (
@@ -3,23 +3,31 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/go-kratos/kratos-layout/internal/bootstrap"
+	"github.com/go-kratos/kratos-layout/internal/middleware"
 	"github.com/go-kratos/kratos-layout/internal/server"
 	"github.com/go-kratos/kratos/contrib/registry/etcd/v2"
 
 	"github.com/go-kratos/kratos-layout/internal/conf"
 
+	"github.com/go-kratos/kratos-layout/pkg/feature"
 	zaplog "github.com/go-kratos/kratos-layout/pkg/log"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
 	"github.com/go-kratos/kratos/v2"
 	"github.com/go-kratos/kratos/v2/config"
-	"github.com/go-kratos/kratos/v2/config/file"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/middleware/tracing"
 	"github.com/go-kratos/kratos/v2/transport/grpc"
 	"github.com/go-kratos/kratos/v2/transport/http"
 
 	_ "go.uber.org/automaxprocs"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // go build -ldflags "-X main.Version=x.y.z"
@@ -30,12 +38,27 @@ var (
 	Version string
 	// flagconf is the config flag.
 	flagconf string
+	// flagsets is the config overrides flag.
+	flagsets bootstrap.Sets
+	// flagetcd is the etcd endpoints flag of the remote config.
+	flagetcd string
+	// flagetcdkey is the etcd key flag of the remote config.
+	flagetcdkey string
+	// flagdump is the flag printing the redacted config.
+	flagdump bool
+	// flagencrypt is the flag encrypting a config value.
+	flagencrypt bool
 
 	id, _ = os.Hostname()
 )
 
 func init() {
 	flag.StringVar(&flagconf, "conf", "../../configs", "config path, eg: -conf config.yaml")
+	flag.Var(&flagsets, "set", "config override, repeatable, eg: -set server.http.addr=0.0.0.0:8080")
+	flag.StringVar(&flagetcd, "etcd", "", "etcd endpoints of the remote config, comma separated, eg: -etcd 127.0.0.1:2379")
+	flag.StringVar(&flagetcdkey, "etcd-key", "/configs/"+Name+"/config.yaml", "etcd key of the remote config")
+	flag.BoolVar(&flagdump, "dump", false, "print the merged config with the secrets redacted and exit")
+	flag.BoolVar(&flagencrypt, "encrypt", false, "encrypt the stdin into an enc: value with the key of "+bootstrap.SecretKeyEnv+" and exit")
 }
 
 func newApp(logger log.Logger, gs *grpc.Server, hs *http.Server, ps *server.PprofServer, r *etcd.Registry) *kratos.App {
@@ -57,10 +80,38 @@ func newApp(logger log.Logger, gs *grpc.Server, hs *http.Server, ps *server.Ppro
 func main() {
 	flag.Parse()
 
+	if flagencrypt {
+		// read from stdin, kept out of the shell history
+		plain, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			panic(err)
+		}
+		value, err := bootstrap.Encrypt(strings.TrimRight(string(plain), "\r\n"))
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(value)
+		return
+	}
+
+	var sourceOpts []bootstrap.SourceOption
+	if flagetcd != "" {
+		etcdSource, etcdCleanup, err := bootstrap.NewEtcdSource(strings.Split(flagetcd, ","), flagetcdkey)
+		if err != nil {
+			panic(err)
+		}
+		defer etcdCleanup()
+		sourceOpts = append(sourceOpts, bootstrap.WithRemote("etcd "+flagetcdkey, etcdSource))
+	}
+	changes := make(chan struct{}, 1)
+	sourceOpts = append(sourceOpts, bootstrap.WithChanges(changes))
+	sources, report, err := bootstrap.NewSources(flagconf, flagsets, sourceOpts...)
+	if err != nil {
+		panic(err)
+	}
 	c := config.New(
-		config.WithSource(
-			file.NewSource(flagconf),
-		),
+		config.WithSource(sources...),
+		config.WithResolver(bootstrap.ResolveSecrets),
 	)
 	defer c.Close()
 	if err := c.Load(); err != nil {
@@ -78,9 +129,35 @@ func main() {
 	if err := c.Scan(&bc); err != nil {
 		panic(err)
 	}
+	if flagdump {
+		out, err := bootstrap.Dump(&bc)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Print(string(out))
+		return
+	}
+	if err := bootstrap.Validate(&bc); err != nil {
+		// every violation with its yaml path, instead of a panic deep in the providers
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	loggerProvider, loggerProviderCleanup, err := wireLoggerProvider(ctx, &bc)
+	if err != nil {
+		panic(err)
+	}
+	// flushed after the app cleanup, so the shutdown logs are exported
+	defer loggerProviderCleanup()
 
 	logCfg := bc.GetLog()
-	zapLogger := zaplog.NewZapLogger(bc.GetEnv(), logCfg.GetFilepath(), logCfg.GetMaxSize(), logCfg.GetMaxAge(), logCfg.GetMaxBackups(), logCfg.GetLevel())
+	logLevel := zap.NewAtomicLevel()
+	logOpts := []zaplog.Option{zaplog.WithAtomicLevel(logLevel)}
+	if loggerProvider != nil {
+		logOpts = append(logOpts, zaplog.WithLoggerProvider(loggerProvider))
+	}
+	zapLogger := zaplog.NewZapLogger(bc.GetEnv(), logCfg.GetFilepath(), logCfg.GetMaxSize(), logCfg.GetMaxAge(), logCfg.GetLevel(), logCfg.GetMaxBackups(), logOpts...)
 	logger := log.With(zapLogger,
 		"ts", log.DefaultTimestamp,
 		"caller", log.DefaultCaller,
@@ -89,14 +166,34 @@ func main() {
 		"service.version", Version,
 		"trace.id", tracing.TraceID(),
 		"span.id", tracing.SpanID(),
+		"request.id", metadata.RequestID(),
+		"tenant.id", metadata.Tenant(),
 	)
 
-	app, cleanup, err := wireApp(context.Background(), &bc, logger)
+	report.Log(logger)
+
+	feature.Set(bc.GetFeatures())
+	reloader := bootstrap.NewReloader(&bc, logger)
+	reloader.OnReload("log.level", func(bc *conf.Bootstrap) {
+		// debug is the startup default out of PROD, a reloaded level applies everywhere
+		logLevel.SetLevel(zapcore.Level(bc.GetLog().GetLevel()))
+	})
+	reloader.OnReload("rate_limits", func(bc *conf.Bootstrap) {
+		middleware.ReloadRateLimits(bc.GetRateLimits())
+	})
+	reloader.OnReload("features", func(bc *conf.Bootstrap) {
+		feature.Set(bc.GetFeatures())
+	})
+
+	app, cleanup, err := wireApp(ctx, &bc, reloader, logger)
 	if err != nil {
 		panic(err)
 	}
 	defer cleanup()
 
+	// the changes of the config files and the remote config from now on
+	reloader.Watch(c, changes)
+
 	// start and wait for stop signal
 	if err := app.Run(); err != nil {
 		panic(err)
@@ -9,8 +9,10 @@ import (
 	"context"
 
 	"github.com/go-kratos/kratos-layout/internal/biz"
+	"github.com/go-kratos/kratos-layout/internal/bootstrap"
 	"github.com/go-kratos/kratos-layout/internal/conf"
 	"github.com/go-kratos/kratos-layout/internal/data"
+	"github.com/go-kratos/kratos-layout/internal/middleware"
 	"github.com/go-kratos/kratos-layout/internal/registry"
 	"github.com/go-kratos/kratos-layout/internal/server"
 	"github.com/go-kratos/kratos-layout/internal/service"
@@ -19,13 +21,21 @@ import (
 	"github.com/go-kratos/kratos/v2"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/google/wire"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 )
 
+// wireLoggerProvider init the otel logger provider, before the app so the
+// bootstrap logs are exported too.
+func wireLoggerProvider(context.Context, *conf.Bootstrap) (*sdklog.LoggerProvider, func(), error) {
+	panic(wire.Build(trace.NewResource, trace.NewLoggerProvider))
+}
+
 // wireApp init kratos application.
-func wireApp(context.Context, *conf.Bootstrap, log.Logger) (*kratos.App, func(), error) {
+func wireApp(context.Context, *conf.Bootstrap, *bootstrap.Reloader, log.Logger) (*kratos.App, func(), error) {
 	panic(wire.Build(
 		registry.ProviderSet,
 		trace.ProviderSet,
+		middleware.ProviderSet,
 		server.ProviderSet,
 		data.ProviderSet,
 		biz.ProviderSet,
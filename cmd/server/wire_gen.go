@@ -9,14 +9,17 @@ package main
 import (
 	"context"
 	"github.com/go-kratos/kratos-layout/internal/biz"
+	"github.com/go-kratos/kratos-layout/internal/bootstrap"
 	"github.com/go-kratos/kratos-layout/internal/conf"
 	"github.com/go-kratos/kratos-layout/internal/data"
+	"github.com/go-kratos/kratos-layout/internal/middleware"
 	"github.com/go-kratos/kratos-layout/internal/registry"
 	"github.com/go-kratos/kratos-layout/internal/server"
 	"github.com/go-kratos/kratos-layout/internal/service"
 	"github.com/go-kratos/kratos-layout/internal/trace"
 	"github.com/go-kratos/kratos/v2"
-	"github.com/go-kratos/kratos/v2/log"
+	log2 "github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/sdk/log"
 )
 
 import (
@@ -25,41 +28,119 @@ import (
 
 // Injectors from wire.go:
 
+// wireLoggerProvider init the otel logger provider, before the app so the
+// bootstrap logs are exported too.
+func wireLoggerProvider(contextContext context.Context, bootstrap *conf.Bootstrap) (*log.LoggerProvider, func(), error) {
+	resource, err := trace.NewResource(contextContext, bootstrap)
+	if err != nil {
+		return nil, nil, err
+	}
+	loggerProvider, cleanup, err := trace.NewLoggerProvider(contextContext, bootstrap, resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loggerProvider, func() {
+		cleanup()
+	}, nil
+}
+
 // wireApp init kratos application.
-func wireApp(contextContext context.Context, bootstrap *conf.Bootstrap, logger log.Logger) (*kratos.App, func(), error) {
+func wireApp(contextContext context.Context, confBootstrap *conf.Bootstrap, reloader *bootstrap.Reloader, logger log2.Logger) (*kratos.App, func(), error) {
+	resource, err := trace.NewResource(contextContext, confBootstrap)
+	if err != nil {
+		return nil, nil, err
+	}
 	textMapPropagator := trace.NewTextMapPropagator()
-	tracerProvider, err := trace.NewTracerProvider(contextContext, bootstrap, textMapPropagator)
+	tracerProvider, cleanup, err := trace.NewTracerProvider(contextContext, confBootstrap, resource, textMapPropagator, reloader, logger)
 	if err != nil {
 		return nil, nil, err
 	}
-	meterProvider, err := trace.NewMeterProvider(bootstrap)
+	meterProvider, cleanup2, err := trace.NewMeterProvider(contextContext, confBootstrap, resource, logger)
 	if err != nil {
+		cleanup()
 		return nil, nil, err
 	}
-	dataData, cleanup, err := data.NewData(bootstrap, tracerProvider, meterProvider, textMapPropagator, logger)
+	dataData, cleanup3, err := data.NewData(confBootstrap, tracerProvider, meterProvider, textMapPropagator, logger)
 	if err != nil {
+		cleanup2()
+		cleanup()
 		return nil, nil, err
 	}
 	greeterRepo := data.NewGreeterRepo(dataData, logger)
-	greeterUsecase := biz.NewGreeterUsecase(greeterRepo, logger)
+	meter, err := trace.NewMeter(confBootstrap, meterProvider)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	metrics := trace.NewMetrics(confBootstrap, meter)
+	greeterUsecase := biz.NewGreeterUsecase(greeterRepo, logger, metrics)
 	greeterService := service.NewGreeterService(greeterUsecase)
 	v := server.NewGRPCServiceSet(greeterService)
-	meter, err := trace.NewMeter(bootstrap, meterProvider)
+	apiKeyStore, err := data.NewAPIKeyStore(confBootstrap, dataData)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	nonceStore, err := data.NewNonceStore(confBootstrap, dataData)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	authenticator, err := middleware.NewAuthenticator(confBootstrap, apiKeyStore, nonceStore)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	authorizer, cleanup4, err := middleware.NewAuthorizer(confBootstrap, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	store, err := data.NewIdempotencyStore(confBootstrap, dataData)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	sink, cleanup5, err := data.NewAuditSink(confBootstrap, dataData, meter)
 	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
 		cleanup()
 		return nil, nil, err
 	}
-	grpcServer := server.NewGRPCServer(bootstrap, v, logger, meter, tracerProvider)
+	grpcServer := server.NewGRPCServer(confBootstrap, v, logger, meter, meterProvider, tracerProvider, authenticator, authorizer, store, sink)
 	v2 := server.NewHTTPServiceSet(greeterService)
-	httpServer := server.NewHTTPServer(bootstrap, v2, logger, meter, tracerProvider)
-	pprofServer, err := server.NewPprof(bootstrap, logger)
+	httpServer := server.NewHTTPServer(confBootstrap, v2, logger, meter, meterProvider, tracerProvider, authenticator, authorizer, store, sink)
+	pprofServer, err := server.NewPprof(confBootstrap, logger, tracerProvider)
 	if err != nil {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
 		cleanup()
 		return nil, nil, err
 	}
-	etcdRegistry := registry.NewEtcdRegistry(bootstrap, logger)
+	etcdRegistry := registry.NewEtcdRegistry(confBootstrap, logger)
 	app := newApp(logger, grpcServer, httpServer, pprofServer, etcdRegistry)
 	return app, func() {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
 		cleanup()
 	}, nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/pkg/metrics"
+	"github.com/go-kratos/kratos-layout/pkg/trace"
 
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
@@ -28,19 +30,33 @@ type GreeterRepo interface {
 	ListAll(context.Context) ([]*Greeter, error)
 }
 
+// greeterLabels is the labels of the greeter metrics.
+type greeterLabels struct {
+	Action string `label:"action"`
+}
+
 // GreeterUsecase is a Greeter usecase.
 type GreeterUsecase struct {
-	repo GreeterRepo
-	log  *log.Helper
+	repo     GreeterRepo
+	log      *log.Helper
+	greeters *metrics.Counter[greeterLabels]
 }
 
 // NewGreeterUsecase new a Greeter usecase.
-func NewGreeterUsecase(repo GreeterRepo, logger log.Logger) *GreeterUsecase {
-	return &GreeterUsecase{repo: repo, log: log.NewHelper(logger)}
+func NewGreeterUsecase(repo GreeterRepo, logger log.Logger, m *metrics.Metrics) *GreeterUsecase {
+	greeters, err := metrics.NewCounter[greeterLabels](m, "biz_greeters_total",
+		metrics.WithDescription("The number of greeter changes."))
+	if err != nil {
+		panic(err)
+	}
+	return &GreeterUsecase{repo: repo, log: log.NewHelper(logger), greeters: greeters}
 }
 
 // CreateGreeter creates a Greeter, and returns the new Greeter.
 func (uc *GreeterUsecase) CreateGreeter(ctx context.Context, g *Greeter) (*Greeter, error) {
 	uc.log.WithContext(ctx).Infof("CreateGreeter: %v", g.Hello)
-	return uc.repo.Save(ctx, g)
+	trace.SetAttributes(ctx, "greeter.hello", g.Hello)
+	g, err := uc.repo.Save(ctx, g)
+	uc.greeters.Add(ctx, 1, greeterLabels{Action: "create"}, err)
+	return g, err
 }
@@ -0,0 +1,230 @@
+// Package bootstrap loads the layered configuration of the application.
+//
+// The layers are merged in order, a later layer overrides an earlier one:
+//
+//  1. the config files, eg: configs/config.yaml
+//  2. the overlay files of the environment, eg: configs/config.prod.yaml
+//  3. the remote config, eg: the etcd key /configs/helloworld/config.yaml
+//  4. the environment variables, eg: APP_SERVER_HTTP_ADDR=0.0.0.0:8080
+//  5. the flags, eg: -set server.http.addr=0.0.0.0:8080
+//
+// The values may reference secrets, resolved by ResolveSecrets before Scan:
+// ${env:MYSQL_PASSWORD}, ${file:/run/secrets/redis} or enc: values encrypted
+// with the key of CONFIG_SECRET_KEY, and other config keys with the kratos
+// placeholders, eg: ${server.http.addr:0.0.0.0:8000}.
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/file"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the config.
+const EnvPrefix = "APP_"
+
+// Layer is a loaded configuration layer.
+type Layer struct {
+	Name string
+	// Keys are the overridden config paths of the env and flag layers.
+	Keys []string
+}
+
+func (l Layer) String() string {
+	if len(l.Keys) == 0 {
+		return l.Name
+	}
+	return fmt.Sprintf("%s (%s)", l.Name, strings.Join(l.Keys, ", "))
+}
+
+// Report is the layers of the configuration, lowest precedence first.
+type Report struct {
+	Env    string
+	Layers []Layer
+}
+
+// Log logs the layers, the values are left out since they may be secrets.
+func (r *Report) Log(logger log.Logger) {
+	helper := log.NewHelper(logger, log.WithMessageKey("config"))
+	helper.Infof("[Config] environment %q, %d layers, later ones override", r.Env, len(r.Layers))
+	for i, l := range r.Layers {
+		helper.Infof("[Config] layer %d: %s", i+1, l)
+	}
+}
+
+// Sets is the repeatable -set flag of the config overrides.
+type Sets []string
+
+func (s *Sets) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *Sets) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("expect key=value: %s", v)
+	}
+	*s = append(*s, v)
+	return nil
+}
+
+// SourceOption is a NewSources option.
+type SourceOption func(*sourceOptions)
+
+type sourceOptions struct {
+	remoteName string
+	remote     config.Source
+	changes    chan<- struct{}
+}
+
+// WithRemote adds the remote source above the files.
+func WithRemote(name string, source config.Source) SourceOption {
+	return func(o *sourceOptions) {
+		o.remoteName = name
+		o.remote = source
+	}
+}
+
+// WithChanges signals the changes of the sources, once the config merged
+// them, eg: to Reloader.Watch. The signals are dropped while one is pending.
+func WithChanges(changes chan<- struct{}) SourceOption {
+	return func(o *sourceOptions) {
+		o.changes = changes
+	}
+}
+
+// NewSources returns the sources of the layers at path, a config file or a
+// directory of config files.
+func NewSources(path string, sets []string, opts ...SourceOption) ([]config.Source, *Report, error) {
+	o := &sourceOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base, err := baseFiles(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(base) == 0 {
+		return nil, nil, fmt.Errorf("no config file in %s", path)
+	}
+
+	desc := (&conf.Bootstrap{}).ProtoReflect().Descriptor()
+	envs, envKeys, err := newEnvSource(EnvPrefix, desc)
+	if err != nil {
+		return nil, nil, err
+	}
+	flags, flagKeys, err := newFlagSource(sets, desc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overrides := []config.Source{envs, flags}
+	if o.remote != nil {
+		overrides = []config.Source{o.remote, envs, flags}
+	}
+	env, err := environment(base, overrides...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &Report{Env: env}
+	var sources []config.Source
+	for _, f := range base {
+		sources = append(sources, file.NewSource(f))
+		report.Layers = append(report.Layers, Layer{Name: "file " + f})
+	}
+	if env != "" {
+		for _, f := range base {
+			overlay := overlayFile(f, env)
+			if _, err := os.Stat(overlay); err != nil {
+				continue
+			}
+			sources = append(sources, file.NewSource(overlay))
+			report.Layers = append(report.Layers, Layer{Name: "file " + overlay})
+		}
+	}
+	if o.remote != nil {
+		sources = append(sources, o.remote)
+		report.Layers = append(report.Layers, Layer{Name: o.remoteName})
+	}
+	if len(envKeys) > 0 {
+		sources = append(sources, envs)
+		report.Layers = append(report.Layers, Layer{Name: "env " + EnvPrefix + "*", Keys: envKeys})
+	}
+	if len(flagKeys) > 0 {
+		sources = append(sources, flags)
+		report.Layers = append(report.Layers, Layer{Name: "flag -set", Keys: flagKeys})
+	}
+
+	// a changed source is merged over the others, the layers above it are
+	// merged again to keep the precedence
+	ordered := make([]config.Source, len(sources))
+	for i, source := range sources {
+		ordered[i] = &orderedSource{Source: source, above: sources[i+1:], changes: o.changes}
+	}
+	return ordered, report, nil
+}
+
+// baseFiles returns the config files at path without the overlay files,
+// named with two extensions, eg: config.prod.yaml.
+func baseFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || strings.Count(name, ".") != 1 {
+			continue
+		}
+		files = append(files, filepath.Join(path, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// overlayFile returns the overlay of the file in the environment, eg:
+// config.yaml in PROD is config.prod.yaml.
+func overlayFile(path, env string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + strings.ToLower(env) + ext
+}
+
+// environment returns the env of the flags, the environment variables, the
+// remote config or the base files, in that order.
+func environment(base []string, overrides ...config.Source) (string, error) {
+	sources := make([]config.Source, 0, len(base)+len(overrides))
+	for _, f := range base {
+		sources = append(sources, file.NewSource(f))
+	}
+	sources = append(sources, overrides...)
+	// only env is read, the placeholders are resolved like the load but the
+	// secrets are left to the load of the chosen files, so an unset reference
+	// of an overlay not chosen doesn't fail the probe
+	c := config.New(config.WithSource(sources...), config.WithResolver(resolvePlaceholders))
+	defer c.Close()
+	if err := c.Load(); err != nil {
+		return "", err
+	}
+	env, err := c.Value("env").String()
+	if err != nil {
+		// unset, the overlays are skipped
+		return "", nil
+	}
+	return env, nil
+}
@@ -0,0 +1,114 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/file"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewSources(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.yaml", "env: PROD\nserver:\n  http:\n    addr: base:8000\n  grpc:\n    addr: base:9000\n")
+	writeFile(t, dir, "registry.yaml", "registry:\n  endpoint: [ etcd:2379 ]\n")
+	writeFile(t, dir, "config.prod.yaml", "server:\n  http:\n    addr: prod:8000\n")
+	writeFile(t, dir, "config.dev.yaml", "server:\n  http:\n    addr: dev:8000\n")
+	remote := writeFile(t, t.TempDir(), "remote.yaml", "server:\n  http:\n    addr: remote:8000\n")
+	noEnv := t.TempDir()
+	writeFile(t, noEnv, "config.yaml", "server:\n  http:\n    addr: base:8000\n")
+	writeFile(t, noEnv, "config.prod.yaml", "server:\n  http:\n    addr: prod:8000\n")
+
+	tests := []struct {
+		name   string
+		path   string
+		remote bool
+		env    map[string]string
+		sets   []string
+		want   string
+		layers []string
+	}{
+		{name: "overlay over base", path: dir, want: "prod:8000", layers: []string{
+			"file " + filepath.Join(dir, "config.yaml"),
+			"file " + filepath.Join(dir, "registry.yaml"),
+			"file " + filepath.Join(dir, "config.prod.yaml"),
+		}},
+		{name: "single file", path: filepath.Join(dir, "config.yaml"), want: "prod:8000", layers: []string{
+			"file " + filepath.Join(dir, "config.yaml"),
+			"file " + filepath.Join(dir, "config.prod.yaml"),
+		}},
+		{name: "no env skips overlays", path: noEnv, want: "base:8000", layers: []string{
+			"file " + filepath.Join(noEnv, "config.yaml"),
+		}},
+		{name: "remote over overlay", path: dir, remote: true, want: "remote:8000"},
+		{name: "env over remote", path: dir, remote: true, env: map[string]string{"APP_SERVER_HTTP_ADDR": "env:8000"}, want: "env:8000"},
+		{name: "flag over env", path: dir, remote: true, env: map[string]string{"APP_SERVER_HTTP_ADDR": "env:8000"}, sets: []string{"server.http.addr=flag:8000"}, want: "flag:8000", layers: []string{
+			"file " + filepath.Join(dir, "config.yaml"),
+			"file " + filepath.Join(dir, "registry.yaml"),
+			"file " + filepath.Join(dir, "config.prod.yaml"),
+			"etcd",
+			"env APP_* (server.http.addr)",
+			"flag -set (server.http.addr)",
+		}},
+		{name: "env chooses overlay", path: dir, env: map[string]string{"APP_ENV": "DEV"}, want: "dev:8000"},
+		{name: "flag chooses overlay", path: dir, env: map[string]string{"APP_ENV": "PROD"}, sets: []string{"env=DEV"}, want: "dev:8000"},
+		{name: "env sets unrelated key", path: dir, env: map[string]string{"APP_SERVER_GRPC_ADDR": "env:9000"}, want: "prod:8000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			var opts []SourceOption
+			if tt.remote {
+				opts = append(opts, WithRemote("etcd", file.NewSource(remote)))
+			}
+			sources, report, err := NewSources(tt.path, tt.sets, opts...)
+			if err != nil {
+				t.Fatalf("NewSources() = %v", err)
+			}
+			c := config.New(config.WithSource(sources...), config.WithResolver(ResolveSecrets))
+			defer c.Close()
+			if err = c.Load(); err != nil {
+				t.Fatal(err)
+			}
+			var bc conf.Bootstrap
+			if err = c.Scan(&bc); err != nil {
+				t.Fatal(err)
+			}
+			if got := bc.GetServer().GetHttp().GetAddr(); got != tt.want {
+				t.Errorf("server.http.addr = %q, want %q", got, tt.want)
+			}
+			if tt.layers != nil {
+				layers := make([]string, 0, len(report.Layers))
+				for _, l := range report.Layers {
+					layers = append(layers, l.String())
+				}
+				if !slices.Equal(layers, tt.layers) {
+					t.Errorf("layers = %q, want %q", layers, tt.layers)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSourcesNoFile(t *testing.T) {
+	if _, _, err := NewSources(t.TempDir(), nil); err == nil {
+		t.Error("NewSources() of an empty dir succeeded")
+	}
+	if _, _, err := NewSources(filepath.Join(t.TempDir(), "missing.yaml"), nil); err == nil {
+		t.Error("NewSources() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,26 @@
+package bootstrap
+
+import (
+	"time"
+
+	etcdconfig "github.com/go-kratos/kratos/contrib/config/etcd/v2"
+	"github.com/go-kratos/kratos/v2/config"
+	etcdclient "go.etcd.io/etcd/client/v3"
+)
+
+const etcdDialTimeout = 5 * time.Second
+
+// NewEtcdSource new the source of the etcd key, the format is the extension
+// of the key, eg: /configs/helloworld/config.yaml.
+func NewEtcdSource(endpoints []string, key string) (config.Source, func(), error) {
+	client, err := etcdclient.New(etcdclient.Config{Endpoints: endpoints, DialTimeout: etcdDialTimeout})
+	if err != nil {
+		return nil, nil, err
+	}
+	source, err := etcdconfig.New(client, etcdconfig.WithPath(key))
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+	return source, func() { _ = client.Close() }, nil
+}
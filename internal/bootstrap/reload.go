@@ -0,0 +1,143 @@
+package bootstrap
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Reloader applies the config changes to the subsystems supporting them, the
+// changes of the other fields are rejected until restart.
+type Reloader struct {
+	mu       sync.Mutex
+	loaded   *conf.Bootstrap
+	handlers []reloadHandler
+	// the changed restart only fields already warned of
+	warned map[string]struct{}
+	log    *log.Helper
+}
+
+type reloadHandler struct {
+	path string
+	fn   func(*conf.Bootstrap)
+}
+
+// NewReloader new a Reloader of the loaded config.
+func NewReloader(bc *conf.Bootstrap, logger log.Logger) *Reloader {
+	return &Reloader{
+		loaded: proto.Clone(bc).(*conf.Bootstrap),
+		log:    log.NewHelper(logger, log.WithMessageKey("config")),
+	}
+}
+
+// OnReload calls fn with the new config when a field at the path changed,
+// eg: log.level or otel.trace.sampler.
+func (r *Reloader) OnReload(path string, fn func(*conf.Bootstrap)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, reloadHandler{path: path, fn: fn})
+}
+
+// Watch reloads the config on the changes of the sources created with
+// WithChanges. config.Watch only observes the keys present at startup, the
+// changes signal the sections added later too.
+func (r *Reloader) Watch(c config.Config, changes <-chan struct{}) {
+	go func() {
+		for range changes {
+			r.reload(c)
+		}
+	}()
+}
+
+func (r *Reloader) reload(c config.Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := &conf.Bootstrap{Metadata: r.loaded.GetMetadata()}
+	if err := c.Scan(next); err != nil {
+		r.log.Errorf("[Config] reload failed, keep the current config: %v", err)
+		return
+	}
+	if err := Validate(next); err != nil {
+		r.log.Errorf("[Config] reload rejected, keep the current config: %v", err)
+		return
+	}
+	changed := diff(r.loaded.ProtoReflect(), next.ProtoReflect(), "")
+	if len(changed) == 0 {
+		r.warned = nil
+		return
+	}
+
+	// the restart only fields keep their loaded values, so the handlers see
+	// the config the process runs with
+	loaded := proto.Clone(r.loaded).(*conf.Bootstrap)
+	applied := make([]bool, len(r.handlers))
+	warned := make(map[string]struct{})
+	for _, p := range changed {
+		reloadable := false
+		for i, h := range r.handlers {
+			if p == h.path || strings.HasPrefix(p, h.path+".") {
+				applied[i] = true
+				reloadable = true
+			}
+		}
+		if !reloadable {
+			// the field differs from the loaded one until restart, warn once
+			if _, ok := r.warned[p]; !ok {
+				r.log.Warnf("[Config] %s changed, it requires restart", p)
+			}
+			warned[p] = struct{}{}
+			continue
+		}
+		copyField(loaded.ProtoReflect(), next.ProtoReflect(), p)
+	}
+	r.warned = warned
+	r.loaded = loaded
+	for i, h := range r.handlers {
+		if applied[i] {
+			h.fn(loaded)
+			r.log.Infof("[Config] %s reloaded", h.path)
+		}
+	}
+}
+
+// copyField copies the field at the path of diff from src to dst.
+func copyField(dst, src protoreflect.Message, path string) {
+	name, rest, nested := strings.Cut(path, ".")
+	fd := dst.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if nested {
+		copyField(dst.Mutable(fd).Message(), src.Get(fd).Message(), rest)
+		return
+	}
+	if src.Has(fd) {
+		dst.Set(fd, src.Get(fd))
+		return
+	}
+	dst.Clear(fd)
+}
+
+// diff returns the paths of the fields changed between a and b, the nested
+// messages are walked into, the lists, maps and well known types compared whole.
+func diff(a, b protoreflect.Message, prefix string) []string {
+	var paths []string
+	fields := a.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() && fd.Message().FullName().Parent() != "google.protobuf" {
+			if a.Has(fd) || b.Has(fd) {
+				paths = append(paths, diff(a.Get(fd).Message(), b.Get(fd).Message(), path+".")...)
+			}
+			continue
+		}
+		if !a.Get(fd).Equal(b.Get(fd)) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
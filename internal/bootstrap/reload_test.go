@@ -0,0 +1,186 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/file"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+const reloadConfig = `
+env: DEV
+log:
+  level: -1
+server:
+  http:
+    addr: 0.0.0.0:8000
+registry:
+  endpoint: [ etcd:2379 ]
+`
+
+func loadConfig(t *testing.T, content string) config.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := config.New(config.WithSource(file.NewSource(path)))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestReloader(t *testing.T) {
+	var bc conf.Bootstrap
+	if err := loadConfig(t, reloadConfig).Scan(&bc); err != nil {
+		t.Fatal(err)
+	}
+	r := NewReloader(&bc, log.DefaultLogger)
+	var levels []int32
+	r.OnReload("log.level", func(bc *conf.Bootstrap) {
+		levels = append(levels, bc.GetLog().GetLevel())
+		if bc.GetServer().GetHttp().GetAddr() != "0.0.0.0:8000" {
+			t.Errorf("handler got the restart only addr %s", bc.GetServer().GetHttp().GetAddr())
+		}
+	})
+
+	tests := []struct {
+		name   string
+		config string
+		levels []int32
+		addr   string
+	}{
+		{name: "unchanged", config: reloadConfig, addr: "0.0.0.0:8000"},
+		{name: "restart only", config: reloadConfig + "features:\n  x: true\n", addr: "0.0.0.0:8000"},
+		{name: "level and addr", config: `
+env: PROD
+log:
+  level: 1
+server:
+  http:
+    addr: 0.0.0.0:8080
+registry:
+  endpoint: [ etcd:2379 ]
+`, levels: []int32{1}, addr: "0.0.0.0:8000"},
+		{name: "invalid", config: "log:\n  level: 2\n", levels: []int32{1}, addr: "0.0.0.0:8000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.reload(loadConfig(t, tt.config))
+			if len(levels) != len(tt.levels) {
+				t.Fatalf("log.level reloads = %v, want %v", levels, tt.levels)
+			}
+			for i := range levels {
+				if levels[i] != tt.levels[i] {
+					t.Errorf("log.level reloads = %v, want %v", levels, tt.levels)
+				}
+			}
+			if got := r.loaded.GetServer().GetHttp().GetAddr(); got != tt.addr {
+				t.Errorf("loaded addr = %s, want %s", got, tt.addr)
+			}
+			if r.loaded.GetEnv() != conf.Environment_DEV {
+				t.Errorf("loaded env = %s, want the restart only DEV", r.loaded.GetEnv())
+			}
+		})
+	}
+}
+
+// warnLogger records the warnings.
+type warnLogger struct {
+	mu    sync.Mutex
+	warns []string
+}
+
+func (l *warnLogger) Log(level log.Level, keyvals ...any) error {
+	if level != log.LevelWarn {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns = append(l.warns, fmt.Sprint(keyvals[1:]...))
+	return nil
+}
+
+func TestReloaderWarnOnce(t *testing.T) {
+	var bc conf.Bootstrap
+	if err := loadConfig(t, reloadConfig).Scan(&bc); err != nil {
+		t.Fatal(err)
+	}
+	logger := &warnLogger{}
+	r := NewReloader(&bc, logger)
+	r.OnReload("log.level", func(*conf.Bootstrap) {})
+
+	moved := strings.Replace(reloadConfig, "0.0.0.0:8000", "0.0.0.0:8080", 1)
+	tests := []struct {
+		name   string
+		config string
+		warns  int
+	}{
+		{name: "restart only", config: moved, warns: 1},
+		{name: "same change", config: moved, warns: 1},
+		{name: "other reload", config: strings.Replace(moved, "level: -1", "level: 1", 1), warns: 1},
+		{name: "other restart only", config: strings.Replace(moved, "env: DEV", "env: PROD", 1), warns: 2},
+		{name: "reverted", config: reloadConfig, warns: 2},
+		{name: "changed again", config: moved, warns: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.reload(loadConfig(t, tt.config))
+			if len(logger.warns) != tt.warns {
+				t.Errorf("warnings = %q, want %d", logger.warns, tt.warns)
+			}
+		})
+	}
+}
+
+func TestReloaderWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(reloadConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	changes := make(chan struct{}, 1)
+	sources, _, err := NewSources(path, nil, WithChanges(changes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := config.New(config.WithSource(sources...), config.WithResolver(ResolveSecrets))
+	if err = c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var bc conf.Bootstrap
+	if err = c.Scan(&bc); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewReloader(&bc, log.DefaultLogger)
+	reloaded := make(chan []*conf.RateLimit, 1)
+	r.OnReload("rate_limits", func(bc *conf.Bootstrap) {
+		reloaded <- bc.GetRateLimits()
+	})
+	r.Watch(c, changes)
+
+	// absent at startup
+	content := reloadConfig + "rate_limits:\n  - operation: /helloworld.v1.Greeter/SayHello\n    rate: 10\n"
+	if err = os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case limits := <-reloaded:
+		if len(limits) != 1 || limits[0].GetRate() != 10 {
+			t.Errorf("rate_limits = %v, want the added one", limits)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("rate_limits added to the file weren't reloaded")
+	}
+}
@@ -0,0 +1,306 @@
+package bootstrap
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SecretKeyEnv is the environment variable of the base64 encoded AES-256
+	// key decrypting the enc: values.
+	SecretKeyEnv = "CONFIG_SECRET_KEY"
+	// EncryptedPrefix is the prefix of the encrypted values, eg: enc:base64(nonce|ciphertext).
+	EncryptedPrefix = "enc:"
+
+	redactedValue = "[REDACTED]"
+)
+
+// reference is a secret reference in a value, eg:
+// root:${env:MYSQL_PASSWORD}@tcp(mysql:3306)/db or ${file:/run/secrets/redis}.
+var reference = regexp.MustCompile(`\$\{(env|file):([^}]+)\}`)
+
+// placeholder is a kratos placeholder of another config key, eg:
+// ${server.http.addr:0.0.0.0:8000}.
+var placeholder = regexp.MustCompile(`\$\{(.*?)\}`)
+
+// ResolveSecrets is the config resolver of the secret references and the
+// encrypted values, then of the ${key:default} placeholders, which see the
+// resolved secrets. It replaces the kratos resolver, unexported, so the
+// placeholders are resolved the kratos way here.
+func ResolveSecrets(input map[string]any) error {
+	if err := resolveSecrets(input, ""); err != nil {
+		return err
+	}
+	return resolvePlaceholders(input)
+}
+
+func resolveSecrets(values map[string]any, prefix string) error {
+	for k, v := range values {
+		resolved, err := resolveValue(v, prefix+k)
+		if err != nil {
+			return err
+		}
+		values[k] = resolved
+	}
+	return nil
+}
+
+func resolveValue(v any, path string) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return resolveString(v, path)
+	case map[string]any:
+		return v, resolveSecrets(v, path+".")
+	case []any:
+		for i, item := range v {
+			resolved, err := resolveValue(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	}
+	return v, nil
+}
+
+// resolveString resolves the references and decrypts the value, the errors
+// carry the config path but never the values.
+func resolveString(s, path string) (string, error) {
+	if strings.HasPrefix(s, EncryptedPrefix) {
+		plain, err := Decrypt(s)
+		if err != nil {
+			return "", fmt.Errorf("config %s: %w", path, err)
+		}
+		return plain, nil
+	}
+	var rerr error
+	s = reference.ReplaceAllStringFunc(s, func(ref string) string {
+		m := reference.FindStringSubmatch(ref)
+		switch m[1] {
+		case "env":
+			v, ok := os.LookupEnv(m[2])
+			if !ok && rerr == nil {
+				rerr = fmt.Errorf("config %s: env %s is not set", path, m[2])
+			}
+			return v
+		default:
+			b, err := os.ReadFile(m[2])
+			if err != nil && rerr == nil {
+				rerr = fmt.Errorf("config %s: %w", path, err)
+			}
+			// the secret files usually end with a newline
+			return strings.TrimRight(string(b), "\r\n")
+		}
+	})
+	return s, rerr
+}
+
+// resolvePlaceholders replaces the ${key:default} placeholders with the
+// values of the config keys, or the defaults, the secret references are left
+// as is.
+func resolvePlaceholders(input map[string]any) error {
+	var resolve func(values map[string]any)
+	resolve = func(values map[string]any) {
+		for k, v := range values {
+			switch v := v.(type) {
+			case string:
+				values[k] = expandPlaceholders(input, v)
+			case map[string]any:
+				resolve(v)
+			case []any:
+				for i, item := range v {
+					switch item := item.(type) {
+					case string:
+						v[i] = expandPlaceholders(input, item)
+					case map[string]any:
+						resolve(item)
+					}
+				}
+			}
+		}
+	}
+	resolve(input)
+	return nil
+}
+
+func expandPlaceholders(input map[string]any, s string) string {
+	return placeholder.ReplaceAllStringFunc(s, func(p string) string {
+		if reference.MatchString(p) {
+			return p
+		}
+		key, def, _ := strings.Cut(strings.TrimSpace(p[2:len(p)-1]), ":")
+		if v, ok := lookupValue(input, key); ok {
+			return v
+		}
+		return def
+	})
+}
+
+// lookupValue returns the scalar at the dotted path of the config, the maps
+// and the lists are empty, like the kratos values.
+func lookupValue(values map[string]any, path string) (string, bool) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := values[key].(map[string]any)
+		if !ok {
+			return "", false
+		}
+		values = next
+	}
+	v, ok := values[keys[len(keys)-1]]
+	if !ok {
+		return "", false
+	}
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), true
+	case []byte:
+		return string(v), true
+	case fmt.Stringer:
+		return v.String(), true
+	}
+	return "", true
+}
+
+// Encrypt encrypts the value with the key of SecretKeyEnv into an enc: value.
+func Encrypt(plain string) (string, error) {
+	aead, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts an enc: value with the key of SecretKeyEnv.
+func Decrypt(value string) (string, error) {
+	aead, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value: too short")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt with %s: %w", SecretKeyEnv, err)
+	}
+	return string(plain), nil
+}
+
+// secretCipher is AES-256-GCM with the key of SecretKeyEnv.
+func secretCipher() (cipher.AEAD, error) {
+	encoded, ok := os.LookupEnv(SecretKeyEnv)
+	if !ok {
+		return nil, fmt.Errorf("%s is not set", SecretKeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must be a base64 encoded 32 bytes key", SecretKeyEnv)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Redact returns a copy of the config with the (kratos.api.secret) fields
+// redacted, the one to log or dump.
+func Redact(bc *conf.Bootstrap) *conf.Bootstrap {
+	redacted := proto.Clone(bc).(*conf.Bootstrap)
+	redact(redacted.ProtoReflect())
+	return redacted
+}
+
+func redact(m protoreflect.Message) {
+	var secrets []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if isSecret(fd) {
+			// the message is not mutated while ranging
+			secrets = append(secrets, fd)
+			return true
+		}
+		switch {
+		case fd.IsList():
+			if fd.Kind() == protoreflect.MessageKind {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					redact(list.Get(i).Message())
+				}
+			}
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redact(mv.Message())
+					return true
+				})
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			redact(v.Message())
+		}
+		return true
+	})
+	for _, fd := range secrets {
+		switch {
+		case fd.IsMap() && fd.MapValue().Kind() == protoreflect.StringKind:
+			// the keys are kept, eg: the header names
+			values := m.Mutable(fd).Map()
+			values.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				values.Set(k, protoreflect.ValueOfString(redactedValue))
+				return true
+			})
+		case fd.Kind() == protoreflect.StringKind && !fd.IsList():
+			m.Set(fd, protoreflect.ValueOfString(redactedValue))
+		default:
+			m.Clear(fd)
+		}
+	}
+}
+
+func isSecret(fd protoreflect.FieldDescriptor) bool {
+	opts := fd.Options()
+	if opts == nil {
+		return false
+	}
+	secret, ok := proto.GetExtension(opts, conf.E_Secret).(bool)
+	return ok && secret
+}
+
+// Dump returns the redacted config as yaml, with the field names of the
+// config files.
+func Dump(bc *conf.Bootstrap) ([]byte, error) {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(Redact(bc))
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]any
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(values)
+}
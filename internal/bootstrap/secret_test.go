@@ -0,0 +1,236 @@
+package bootstrap
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/file"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestResolveString(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(SecretKeyEnv, base64.StdEncoding.EncodeToString(key))
+	t.Setenv("TEST_DB_PASSWORD", "p@ss")
+	t.Setenv("TEST_DB_USER", "root")
+	secretFile := filepath.Join(t.TempDir(), "redis")
+	if err := os.WriteFile(secretFile, []byte("r3dis\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := Encrypt("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+		err   bool
+	}{
+		{name: "plain", value: "redis:6379", want: "redis:6379"},
+		{name: "kratos placeholder", value: "${server.http.addr:0.0.0.0:8000}", want: "${server.http.addr:0.0.0.0:8000}"},
+		{name: "env", value: "${env:TEST_DB_PASSWORD}", want: "p@ss"},
+		{name: "env in dsn", value: "${env:TEST_DB_USER}:${env:TEST_DB_PASSWORD}@tcp(mysql:3306)/db", want: "root:p@ss@tcp(mysql:3306)/db"},
+		{name: "env unset", value: "${env:TEST_UNSET}", err: true},
+		{name: "file", value: "${file:" + secretFile + "}", want: "r3dis"},
+		{name: "file missing", value: "${file:" + secretFile + ".missing}", err: true},
+		{name: "encrypted", value: encrypted, want: "s3cret"},
+		{name: "encrypted malformed", value: EncryptedPrefix + "!!", err: true},
+		{name: "encrypted tampered", value: encrypted[:len(encrypted)-4] + "AAAA", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveString(tt.value, "data.redis.helloworld.password")
+			if (err != nil) != tt.err {
+				t.Fatalf("resolveString() error = %v, want error %v", err, tt.err)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "data.redis.helloworld.password") {
+					t.Errorf("error %q misses the config path", err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("resolveString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("TEST_DB_PASSWORD", "p@ss")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+app:
+  host: example.com
+  port: 8000
+  debug: true
+  ratio: 0.5
+  tags: [ a, b ]
+  password: ${env:TEST_DB_PASSWORD}
+values:
+  addr: ${app.host}:${app.port}
+  default: ${app.missing:fallback}
+  default_colon: ${app.missing:0.0.0.0:8000}
+  missing: ${app.missing}
+  spaces: ${ app.host }
+  bool: ${app.debug}
+  float: ${app.ratio}
+  list: ${app.tags}
+  secret: ${app.password}
+  dsn: root:${env:TEST_DB_PASSWORD}@tcp(${app.host})/db
+  items: [ "${app.host}", { name: "${app.port}" } ]
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := config.New(config.WithSource(file.NewSource(path)), config.WithResolver(ResolveSecrets))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "values.addr", want: "example.com:8000"},
+		{key: "values.default", want: "fallback"},
+		{key: "values.default_colon", want: "0.0.0.0:8000"},
+		{key: "values.missing", want: ""},
+		{key: "values.spaces", want: "example.com"},
+		{key: "values.bool", want: "true"},
+		{key: "values.float", want: "0.5"},
+		{key: "values.list", want: ""},
+		{key: "values.secret", want: "p@ss"},
+		{key: "values.dsn", want: "root:p@ss@tcp(example.com)/db"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := c.Value(tt.key).String()
+			if err != nil || got != tt.want {
+				t.Errorf("%s = %q, %v, want %q", tt.key, got, err, tt.want)
+			}
+		})
+	}
+
+	var items []any
+	if err := c.Value("values.items").Scan(&items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0] != "example.com" || items[1].(map[string]any)["name"] != "8000" {
+		t.Errorf("values.items = %v, want the resolved items", items)
+	}
+}
+
+func TestDecryptWrongKey(t *testing.T) {
+	key := make([]byte, 32)
+	t.Setenv(SecretKeyEnv, base64.StdEncoding.EncodeToString(key))
+	encrypted, err := Encrypt("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key[0] = 1
+	t.Setenv(SecretKeyEnv, base64.StdEncoding.EncodeToString(key))
+	if _, err = Decrypt(encrypted); err == nil {
+		t.Error("Decrypt() with another key succeeded")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	bc := &conf.Bootstrap{
+		Server: &conf.Server{Http: &conf.Server_HTTP{Addr: "0.0.0.0:8000"}},
+		Auth: &conf.Auth{
+			Jwt:     &conf.Auth_JWT{Keys: []*conf.Auth_Key{{Kid: "dev", Alg: "HS256", Secret: "jwt-s3cret"}}},
+			ApiKeys: &conf.Auth_APIKeys{Keys: []*conf.Auth_APIKey{{Id: "batch", Hash: "e218"}}},
+			Hmac:    &conf.Auth_HMAC{Clients: []*conf.Auth_HMACClient{{KeyId: "batch", Secret: "hmac-s3cret"}}},
+		},
+		Otel: &conf.Otel{Trace: &conf.Otel_Trace{Headers: map[string]string{"authorization": "Bearer token"}}},
+		Data: &conf.Data{
+			Database: map[string]*conf.Data_Database{"mysql": {Driver: "mysql", Source: "root:p@ss@tcp(mysql:3306)/db"}},
+			Redis:    map[string]*conf.Data_Redis{"helloworld": {Addr: "redis:6379", Password: "r3dis"}},
+		},
+	}
+	original := proto.Clone(bc)
+	r := Redact(bc)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "jwt secret", got: r.GetAuth().GetJwt().GetKeys()[0].GetSecret(), want: redactedValue},
+		{name: "api key hash", got: r.GetAuth().GetApiKeys().GetKeys()[0].GetHash(), want: redactedValue},
+		{name: "hmac secret", got: r.GetAuth().GetHmac().GetClients()[0].GetSecret(), want: redactedValue},
+		{name: "header", got: r.GetOtel().GetTrace().GetHeaders()["authorization"], want: redactedValue},
+		{name: "database source", got: r.GetData().GetDatabase()["mysql"].GetSource(), want: redactedValue},
+		{name: "redis password", got: r.GetData().GetRedis()["helloworld"].GetPassword(), want: redactedValue},
+		{name: "kid kept", got: r.GetAuth().GetJwt().GetKeys()[0].GetKid(), want: "dev"},
+		{name: "api key id kept", got: r.GetAuth().GetApiKeys().GetKeys()[0].GetId(), want: "batch"},
+		{name: "redis addr kept", got: r.GetData().GetRedis()["helloworld"].GetAddr(), want: "redis:6379"},
+		{name: "http addr kept", got: r.GetServer().GetHttp().GetAddr(), want: "0.0.0.0:8000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+	if !proto.Equal(bc, original) {
+		t.Error("Redact modified the config")
+	}
+
+	dump, err := Dump(bc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"jwt-s3cret", "e218", "hmac-s3cret", "Bearer token", "p@ss", "r3dis"} {
+		if strings.Contains(string(dump), secret) {
+			t.Errorf("Dump leaks %q", secret)
+		}
+	}
+}
+
+func TestEnvironmentSkipsSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "env: PROD\ndata:\n  redis:\n    helloworld:\n      password: ${env:TEST_UNSET}\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env, err := environment([]string{path})
+	if err != nil || env != "PROD" {
+		t.Errorf("environment() = %q, %v, want PROD", env, err)
+	}
+}
+
+func TestEnvironmentPlaceholder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "env: ${deploy.env:PRE}\ndata:\n  redis:\n    helloworld:\n      password: ${env:TEST_UNSET}\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env, err := environment([]string{path})
+	if err != nil || env != "PRE" {
+		t.Errorf("environment() = %q, %v, want the PRE default", env, err)
+	}
+
+	overridden := filepath.Join(t.TempDir(), "deploy.yaml")
+	if err = os.WriteFile(overridden, []byte("deploy:\n  env: PROD\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env, err = environment([]string{path, overridden})
+	if err != nil || env != "PROD" {
+		t.Errorf("environment() = %q, %v, want the PROD of deploy.env", env, err)
+	}
+}
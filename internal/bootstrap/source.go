@@ -0,0 +1,242 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/env"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	_ config.Source = (*staticSource)(nil)
+	_ config.Source = (*orderedSource)(nil)
+)
+
+// orderedSource appends the values of the sources above to the changes, and
+// signals the changes merged by the config.
+type orderedSource struct {
+	config.Source
+	above   []config.Source
+	changes chan<- struct{}
+}
+
+func (s *orderedSource) Watch() (config.Watcher, error) {
+	w, err := s.Source.Watch()
+	if err != nil {
+		return nil, err
+	}
+	return &orderedWatcher{Watcher: w, above: s.above, changes: s.changes}, nil
+}
+
+type orderedWatcher struct {
+	config.Watcher
+	above   []config.Source
+	changes chan<- struct{}
+	merged  bool
+}
+
+func (w *orderedWatcher) Next() ([]*config.KeyValue, error) {
+	// the config merges the changes before it asks for the next ones
+	if w.merged {
+		w.merged = false
+		select {
+		case w.changes <- struct{}{}:
+		default:
+			// a signal is pending already
+		}
+	}
+	kvs, err := w.Watcher.Next()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range w.above {
+		above, err := s.Load()
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, above...)
+	}
+	w.merged = w.changes != nil
+	return kvs, nil
+}
+
+// staticSource is the nested values of the overridden config paths.
+type staticSource struct {
+	name   string
+	values map[string]any
+}
+
+func (s *staticSource) Load() ([]*config.KeyValue, error) {
+	data, err := json.Marshal(s.values)
+	if err != nil {
+		return nil, err
+	}
+	return []*config.KeyValue{{Key: s.name, Value: data, Format: "json"}}, nil
+}
+
+// Watch blocks until stopped, the values never change.
+func (s *staticSource) Watch() (config.Watcher, error) {
+	return env.NewWatcher()
+}
+
+// newEnvSource maps the variables of the kratos env source with the prefix to
+// the config paths, the underscores separate the fields and the map keys, eg:
+// APP_DATA_DATABASE_MYSQL_MAX_OPEN_CONN is data.database.mysql.max_open_conn.
+// The kratos source alone only splits the keys on the dots, which the variable
+// names can't have, and the underscores are also in the field names, so the
+// paths are resolved against the proto descriptor.
+func newEnvSource(prefix string, desc protoreflect.MessageDescriptor) (config.Source, []string, error) {
+	kvs, err := env.NewSource(prefix).Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &staticSource{name: "env", values: make(map[string]any)}
+	var keys []string
+	for _, kv := range kvs {
+		path, fd, ok := resolve(desc, strings.Split(strings.ToLower(kv.Key), "_"), "_")
+		if !ok {
+			// not a config field, eg: APP_NAME of the deployment
+			continue
+		}
+		value, err := convert(fd, string(kv.Value))
+		if err != nil {
+			return nil, nil, fmt.Errorf("env %s%s: %w", prefix, kv.Key, err)
+		}
+		setPath(s.values, path, value)
+		keys = append(keys, strings.Join(path, "."))
+	}
+	sort.Strings(keys)
+	return s, keys, nil
+}
+
+// newFlagSource maps the key=value flags to the config paths, the dots
+// separate the fields and the map keys, eg: data.database.mysql.max_open_conn=20.
+func newFlagSource(sets []string, desc protoreflect.MessageDescriptor) (config.Source, []string, error) {
+	s := &staticSource{name: "flag", values: make(map[string]any)}
+	var keys []string
+	for _, set := range sets {
+		k, v, _ := strings.Cut(set, "=")
+		path, fd, ok := resolve(desc, strings.Split(k, "."), ".")
+		if !ok {
+			return nil, nil, fmt.Errorf("flag -set %s: unknown config path", k)
+		}
+		value, err := convert(fd, v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("flag -set %s: %w", k, err)
+		}
+		setPath(s.values, path, value)
+		keys = append(keys, strings.Join(path, "."))
+	}
+	return s, keys, nil
+}
+
+// resolve maps the tokens to the path of a scalar field, a list or a well
+// known type, a field name or a map key may span several tokens.
+func resolve(md protoreflect.MessageDescriptor, tokens []string, sep string) ([]string, protoreflect.FieldDescriptor, bool) {
+	for n := len(tokens); n > 0; n-- {
+		fd := fieldByName(md, strings.Join(tokens[:n], "_"))
+		if fd == nil {
+			continue
+		}
+		rest := tokens[n:]
+		name := string(fd.Name())
+		if fd.IsMap() {
+			for k := len(rest); k > 0; k-- {
+				key := strings.Join(rest[:k], sep)
+				value := fd.MapValue()
+				if !isMessage(value) {
+					if k == len(rest) {
+						return []string{name, key}, value, true
+					}
+					continue
+				}
+				if path, leaf, ok := resolve(value.Message(), rest[k:], sep); ok {
+					return append([]string{name, key}, path...), leaf, true
+				}
+			}
+			continue
+		}
+		if !isMessage(fd) || fd.IsList() {
+			if len(rest) == 0 {
+				return []string{name}, fd, true
+			}
+			continue
+		}
+		if path, leaf, ok := resolve(fd.Message(), rest, sep); ok {
+			return append([]string{name}, path...), leaf, true
+		}
+	}
+	return nil, nil, false
+}
+
+func fieldByName(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		// the names of MetaData are capitalized
+		if strings.EqualFold(string(fields.Get(i).Name()), name) {
+			return fields.Get(i)
+		}
+	}
+	return nil
+}
+
+// isMessage reports whether fd is a message to walk into, the well known
+// types are set as their json strings, eg: 1.5s.
+func isMessage(fd protoreflect.FieldDescriptor) bool {
+	return fd.Message() != nil && fd.Message().FullName().Parent() != "google.protobuf"
+}
+
+// convert converts the raw value to the json type of the field, the lists
+// are comma separated.
+func convert(fd protoreflect.FieldDescriptor, raw string) (any, error) {
+	if fd.IsList() {
+		if isMessage(fd) {
+			return nil, fmt.Errorf("list of messages can't be overridden")
+		}
+		var values []any
+		for _, item := range strings.Split(raw, ",") {
+			v, err := convertScalar(fd, strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	}
+	return convertScalar(fd, raw)
+}
+
+func convertScalar(fd protoreflect.FieldDescriptor, raw string) (any, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return strconv.ParseBool(raw)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.ParseInt(raw, 10, 64)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.ParseUint(raw, 10, 64)
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return strconv.ParseFloat(raw, 64)
+	default:
+		// strings, enum names and the well known types, eg: 1.5s
+		return raw, nil
+	}
+}
+
+func setPath(values map[string]any, path []string, value any) {
+	for _, p := range path[:len(path)-1] {
+		next, ok := values[p].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			values[p] = next
+		}
+		values = next
+	}
+	values[path[len(path)-1]] = value
+}
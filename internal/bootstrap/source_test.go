@@ -0,0 +1,149 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/config"
+)
+
+// sourceValues returns the json values of the static source.
+func sourceValues(t *testing.T, s config.Source) map[string]any {
+	t.Helper()
+	kvs, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvs) != 1 || kvs[0].Format != "json" {
+		t.Fatalf("Load() = %v, want one json value", kvs)
+	}
+	values := make(map[string]any)
+	if err = json.Unmarshal(kvs[0].Value, &values); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func jsonValues(t *testing.T, s string) map[string]any {
+	t.Helper()
+	values := make(map[string]any)
+	if err := json.Unmarshal([]byte(s), &values); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func TestEnvSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		keys   []string
+		values string
+		err    bool
+	}{
+		{name: "string", env: map[string]string{"APP_SERVER_HTTP_ADDR": "0.0.0.0:8080"},
+			keys: []string{"server.http.addr"}, values: `{"server":{"http":{"addr":"0.0.0.0:8080"}}}`},
+		{name: "field with underscores", env: map[string]string{"APP_DATA_DATABASE_MYSQL_MAX_OPEN_CONN": "20"},
+			keys: []string{"data.database.mysql.max_open_conn"}, values: `{"data":{"database":{"mysql":{"max_open_conn":20}}}}`},
+		{name: "map key with underscores", env: map[string]string{"APP_DATA_DATABASE_USER_DB_DRIVER": "postgres"},
+			keys: []string{"data.database.user_db.driver"}, values: `{"data":{"database":{"user_db":{"driver":"postgres"}}}}`},
+		{name: "list", env: map[string]string{"APP_REGISTRY_ENDPOINT": "etcd-0:2379, etcd-1:2379"},
+			keys: []string{"registry.endpoint"}, values: `{"registry":{"endpoint":["etcd-0:2379","etcd-1:2379"]}}`},
+		{name: "list of ints", env: map[string]string{"APP_DATA_REDIS_HELLOWORLD_SHARDS": "0,1"},
+			keys: []string{"data.redis.helloworld.shards"}, values: `{"data":{"redis":{"helloworld":{"shards":[0,1]}}}}`},
+		{name: "well known type", env: map[string]string{"APP_SERVER_GRPC_TIMEOUT": "1.5s"},
+			keys: []string{"server.grpc.timeout"}, values: `{"server":{"grpc":{"timeout":"1.5s"}}}`},
+		{name: "enum and bool", env: map[string]string{"APP_ENV": "PROD", "APP_SERVER_HTTP_CORS_ENABLE": "true"},
+			keys: []string{"env", "server.http.cors.enable"}, values: `{"env":"PROD","server":{"http":{"cors":{"enable":true}}}}`},
+		{name: "capitalized field", env: map[string]string{"APP_METADATA_NAME": "helloworld"},
+			keys: []string{"metadata.Name"}, values: `{"metadata":{"Name":"helloworld"}}`},
+		{name: "not a config field", env: map[string]string{"APP_NAME": "deploy", "APP_SERVER_HTTP_ADDR_PORT": "8080", "APP_SERVER_HTTP": "x"},
+			values: `{}`},
+		{name: "other prefix", env: map[string]string{"SERVER_HTTP_ADDR": "0.0.0.0:8080"}, values: `{}`},
+		{name: "invalid value", env: map[string]string{"APP_DATA_DATABASE_MYSQL_MAX_OPEN_CONN": "many"}, err: true},
+		{name: "list of messages", env: map[string]string{"APP_RATE_LIMITS": "x"}, err: true},
+	}
+	desc := (&conf.Bootstrap{}).ProtoReflect().Descriptor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			s, keys, err := newEnvSource(EnvPrefix, desc)
+			if (err != nil) != tt.err {
+				t.Fatalf("newEnvSource() error = %v, want error %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if !slices.Equal(keys, tt.keys) {
+				t.Errorf("keys = %q, want %q", keys, tt.keys)
+			}
+			if got, want := sourceValues(t, s), jsonValues(t, tt.values); !reflect.DeepEqual(got, want) {
+				t.Errorf("values = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFlagSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		sets   []string
+		keys   []string
+		values string
+		err    bool
+	}{
+		{name: "string", sets: []string{"server.http.addr=0.0.0.0:8080"},
+			keys: []string{"server.http.addr"}, values: `{"server":{"http":{"addr":"0.0.0.0:8080"}}}`},
+		{name: "value with equal sign", sets: []string{"data.database.mysql.source=root@tcp(mysql)/db?loc=Local"},
+			keys: []string{"data.database.mysql.source"}, values: `{"data":{"database":{"mysql":{"source":"root@tcp(mysql)/db?loc=Local"}}}}`},
+		{name: "map key", sets: []string{"data.database.user_db.max_open_conn=20"},
+			keys: []string{"data.database.user_db.max_open_conn"}, values: `{"data":{"database":{"user_db":{"max_open_conn":20}}}}`},
+		{name: "scalar map", sets: []string{"server.http.route_max_body_size./v1/upload=1048576"},
+			keys: []string{"server.http.route_max_body_size./v1/upload"}, values: `{"server":{"http":{"route_max_body_size":{"/v1/upload":1048576}}}}`},
+		{name: "later wins", sets: []string{"server.http.addr=0.0.0.0:8080", "server.http.addr=0.0.0.0:9090"},
+			keys: []string{"server.http.addr", "server.http.addr"}, values: `{"server":{"http":{"addr":"0.0.0.0:9090"}}}`},
+		{name: "list", sets: []string{"registry.endpoint=etcd-0:2379,etcd-1:2379"},
+			keys: []string{"registry.endpoint"}, values: `{"registry":{"endpoint":["etcd-0:2379","etcd-1:2379"]}}`},
+		{name: "capitalized field", sets: []string{"metadata.name=helloworld"},
+			keys: []string{"metadata.Name"}, values: `{"metadata":{"Name":"helloworld"}}`},
+		{name: "unknown path", sets: []string{"server.http.port=8080"}, err: true},
+		{name: "message", sets: []string{"server.http=x"}, err: true},
+		{name: "underscores are not dots", sets: []string{"server_http_addr=0.0.0.0:8080"}, err: true},
+		{name: "invalid bool", sets: []string{"server.http.cors.enable=maybe"}, err: true},
+	}
+	desc := (&conf.Bootstrap{}).ProtoReflect().Descriptor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, keys, err := newFlagSource(tt.sets, desc)
+			if (err != nil) != tt.err {
+				t.Fatalf("newFlagSource() error = %v, want error %t", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if !slices.Equal(keys, tt.keys) {
+				t.Errorf("keys = %q, want %q", keys, tt.keys)
+			}
+			if got, want := sourceValues(t, s), jsonValues(t, tt.values); !reflect.DeepEqual(got, want) {
+				t.Errorf("values = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSets(t *testing.T) {
+	var sets Sets
+	if err := sets.Set("server.http.addr"); err == nil {
+		t.Error("Set() without = succeeded")
+	}
+	_ = sets.Set("server.http.addr=0.0.0.0:8080")
+	_ = sets.Set("env=DEV")
+	if got := sets.String(); got != "server.http.addr=0.0.0.0:8080,env=DEV" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/bufbuild/protovalidate-go"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+)
+
+// ValidationError is the violations of the validate rules of conf.proto.
+type ValidationError struct {
+	// Violations are the yaml paths with the reasons, eg:
+	// data.database.mysql.driver: value must be in list [mysql, postgresql]
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config, %d violations:\n  %s", len(e.Violations), strings.Join(e.Violations, "\n  "))
+}
+
+// Validate validates the config against the validate rules of conf.proto,
+// every violation is reported, not only the first one.
+func Validate(bc *conf.Bootstrap) error {
+	err := protovalidate.Validate(bc)
+	var verr *protovalidate.ValidationError
+	if !errors.As(err, &verr) {
+		// nil, or the rules failed to compile
+		return err
+	}
+	violations := make([]string, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		path := yamlPath(v.Proto.GetField())
+		if path == "" {
+			// the message rules, eg: the cel expressions
+			path = "<root>"
+		}
+		violations = append(violations, path+": "+v.Proto.GetMessage())
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// yamlPath joins the fields and the map keys with dots and indexes the lists,
+// eg: data.redis.helloworld.shards[1].
+func yamlPath(path *validate.FieldPath) string {
+	var b strings.Builder
+	for _, e := range path.GetElements() {
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(e.GetFieldName())
+		switch s := e.GetSubscript().(type) {
+		case *validate.FieldPathElement_Index:
+			b.WriteString("[" + strconv.FormatUint(s.Index, 10) + "]")
+		case *validate.FieldPathElement_StringKey:
+			b.WriteString("." + s.StringKey)
+		case *validate.FieldPathElement_IntKey:
+			b.WriteString("." + strconv.FormatInt(s.IntKey, 10))
+		case *validate.FieldPathElement_UintKey:
+			b.WriteString("." + strconv.FormatUint(s.UintKey, 10))
+		case *validate.FieldPathElement_BoolKey:
+			b.WriteString("." + strconv.FormatBool(s.BoolKey))
+		}
+	}
+	return b.String()
+}
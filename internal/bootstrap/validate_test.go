@@ -0,0 +1,102 @@
+package bootstrap
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func validConfig() *conf.Bootstrap {
+	return &conf.Bootstrap{
+		Server: &conf.Server{
+			Http: &conf.Server_HTTP{Addr: "0.0.0.0:8000"},
+			Grpc: &conf.Server_GRPC{Addr: "0.0.0.0:9000"},
+		},
+		Registry: &conf.Registry{Endpoint: []string{"etcd:2379"}},
+		Bbr:      &conf.BBR{WindowSize: durationpb.New(10 * time.Second), Bucket: 1000, CpuThreshold: 1000},
+		Data: &conf.Data{
+			Database: map[string]*conf.Data_Database{
+				"mysql": {Driver: "mysql", Source: "root@tcp(mysql:3306)/db", MaxOpenConn: 20, MaxIdleConn: 10},
+			},
+			Redis: map[string]*conf.Data_Redis{
+				"helloworld": {Addr: "redis:6379", Shards: []int32{0, 1}},
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(bc *conf.Bootstrap)
+		violations []string
+	}{
+		{name: "valid", mutate: func(*conf.Bootstrap) {}},
+		{name: "default pool", mutate: func(bc *conf.Bootstrap) {
+			bc.Data.Database["mysql"].MaxOpenConn = 0
+		}},
+		{name: "idle over open", mutate: func(bc *conf.Bootstrap) {
+			bc.Data.Database["mysql"].MaxIdleConn = 30
+		}, violations: []string{"data.database.mysql"}},
+		{name: "negative pool", mutate: func(bc *conf.Bootstrap) {
+			bc.Data.Database["mysql"].MaxOpenConn = -1
+		}, violations: []string{"data.database.mysql", "data.database.mysql.max_open_conn"}},
+		{name: "driver", mutate: func(bc *conf.Bootstrap) {
+			bc.Data.Database["mysql"].Driver = "sqlite"
+		}, violations: []string{"data.database.mysql.driver"}},
+		{name: "empty addrs", mutate: func(bc *conf.Bootstrap) {
+			bc.Server.Http.Addr = ""
+			bc.Server.Grpc.Addr = ""
+		}, violations: []string{"server.grpc.addr", "server.http.addr"}},
+		{name: "no registry", mutate: func(bc *conf.Bootstrap) {
+			bc.Registry = nil
+		}, violations: []string{"registry"}},
+		{name: "empty endpoint", mutate: func(bc *conf.Bootstrap) {
+			bc.Registry.Endpoint = []string{"etcd:2379", ""}
+		}, violations: []string{"registry.endpoint[1]"}},
+		{name: "window size", mutate: func(bc *conf.Bootstrap) {
+			bc.Bbr.WindowSize = durationpb.New(time.Millisecond)
+		}, violations: []string{"bbr.window_size"}},
+		{name: "cors any origin with credentials", mutate: func(bc *conf.Bootstrap) {
+			bc.Server.Http.Cors = &conf.Server_HTTP_CORS{AllowOrigins: []string{"*"}, AllowCredentials: true}
+		}, violations: []string{"server.http.cors"}},
+		{name: "cors origin with credentials", mutate: func(bc *conf.Bootstrap) {
+			bc.Server.Http.Cors = &conf.Server_HTTP_CORS{AllowOrigins: []string{"https://example.com"}, AllowCredentials: true}
+		}},
+		{name: "every violation", mutate: func(bc *conf.Bootstrap) {
+			bc.Data.Redis["helloworld"].Addr = ""
+			bc.Data.Redis["helloworld"].Shards = []int32{0, -1}
+		}, violations: []string{"data.redis.helloworld.addr", "data.redis.helloworld.shards[1]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := validConfig()
+			tt.mutate(bc)
+			err := Validate(bc)
+			if len(tt.violations) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() = %v, want a ValidationError", err)
+			}
+			var paths []string
+			for _, v := range verr.Violations {
+				path, _, _ := strings.Cut(v, ": ")
+				paths = append(paths, path)
+			}
+			slices.Sort(paths)
+			if !slices.Equal(paths, tt.violations) {
+				t.Errorf("violations = %q, want the paths %q", verr.Violations, tt.violations)
+			}
+		})
+	}
+}
@@ -7,8 +7,10 @@
 package conf
 
 import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
 	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	reflect "reflect"
 	sync "sync"
@@ -74,35 +76,2816 @@ func (Environment) EnumDescriptor() ([]byte, []int) {
 	return file_conf_conf_proto_rawDescGZIP(), []int{0}
 }
 
+type Log_OTLP_Protocol int32
+
+const (
+	Log_OTLP_GRPC Log_OTLP_Protocol = 0
+	Log_OTLP_HTTP Log_OTLP_Protocol = 1
+)
+
+// Enum value maps for Log_OTLP_Protocol.
+var (
+	Log_OTLP_Protocol_name = map[int32]string{
+		0: "GRPC",
+		1: "HTTP",
+	}
+	Log_OTLP_Protocol_value = map[string]int32{
+		"GRPC": 0,
+		"HTTP": 1,
+	}
+)
+
+func (x Log_OTLP_Protocol) Enum() *Log_OTLP_Protocol {
+	p := new(Log_OTLP_Protocol)
+	*p = x
+	return p
+}
+
+func (x Log_OTLP_Protocol) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Log_OTLP_Protocol) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[1].Descriptor()
+}
+
+func (Log_OTLP_Protocol) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[1]
+}
+
+func (x Log_OTLP_Protocol) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Log_OTLP_Protocol.Descriptor instead.
+func (Log_OTLP_Protocol) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3, 0, 0}
+}
+
+// Envelope controls which responses are wrapped in the json envelope
+// {code, reason, reason_code, message, metadata, data, trace_id, request_id},
+// code is the http status, reason the kratos error reason and reason_code
+// its ErrorReason enum value
+type Server_HTTP_Envelope int32
+
+const (
+	// kratos default encoders
+	Server_HTTP_NONE Server_HTTP_Envelope = 0
+	// only errors are wrapped
+	Server_HTTP_ERROR Server_HTTP_Envelope = 1
+	// both replies and errors are wrapped
+	Server_HTTP_ALL Server_HTTP_Envelope = 2
+)
+
+// Enum value maps for Server_HTTP_Envelope.
+var (
+	Server_HTTP_Envelope_name = map[int32]string{
+		0: "NONE",
+		1: "ERROR",
+		2: "ALL",
+	}
+	Server_HTTP_Envelope_value = map[string]int32{
+		"NONE":  0,
+		"ERROR": 1,
+		"ALL":   2,
+	}
+)
+
+func (x Server_HTTP_Envelope) Enum() *Server_HTTP_Envelope {
+	p := new(Server_HTTP_Envelope)
+	*p = x
+	return p
+}
+
+func (x Server_HTTP_Envelope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Server_HTTP_Envelope) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[2].Descriptor()
+}
+
+func (Server_HTTP_Envelope) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[2]
+}
+
+func (x Server_HTTP_Envelope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Server_HTTP_Envelope.Descriptor instead.
+func (Server_HTTP_Envelope) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 0, 0}
+}
+
+type Otel_Sampler_Type int32
+
+const (
+	Otel_Sampler_ALWAYS_ON  Otel_Sampler_Type = 0
+	Otel_Sampler_ALWAYS_OFF Otel_Sampler_Type = 1
+	// sample the ratio of the traces by trace id
+	Otel_Sampler_RATIO Otel_Sampler_Type = 2
+	// sample at most rate_limit traces per second
+	Otel_Sampler_RATE_LIMITED Otel_Sampler_Type = 3
+)
+
+// Enum value maps for Otel_Sampler_Type.
+var (
+	Otel_Sampler_Type_name = map[int32]string{
+		0: "ALWAYS_ON",
+		1: "ALWAYS_OFF",
+		2: "RATIO",
+		3: "RATE_LIMITED",
+	}
+	Otel_Sampler_Type_value = map[string]int32{
+		"ALWAYS_ON":    0,
+		"ALWAYS_OFF":   1,
+		"RATIO":        2,
+		"RATE_LIMITED": 3,
+	}
+)
+
+func (x Otel_Sampler_Type) Enum() *Otel_Sampler_Type {
+	p := new(Otel_Sampler_Type)
+	*p = x
+	return p
+}
+
+func (x Otel_Sampler_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Otel_Sampler_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[3].Descriptor()
+}
+
+func (Otel_Sampler_Type) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[3]
+}
+
+func (x Otel_Sampler_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Otel_Sampler_Type.Descriptor instead.
+func (Otel_Sampler_Type) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 0, 0}
+}
+
+type Otel_Trace_Exporter int32
+
+const (
+	Otel_Trace_OTLP_GRPC Otel_Trace_Exporter = 0
+	Otel_Trace_OTLP_HTTP Otel_Trace_Exporter = 1
+	// pretty printed spans on stdout
+	Otel_Trace_STDOUT Otel_Trace_Exporter = 2
+	// json lines into file
+	Otel_Trace_FILE Otel_Trace_Exporter = 3
+	// a no-op tracer provider
+	Otel_Trace_NONE Otel_Trace_Exporter = 4
+)
+
+// Enum value maps for Otel_Trace_Exporter.
+var (
+	Otel_Trace_Exporter_name = map[int32]string{
+		0: "OTLP_GRPC",
+		1: "OTLP_HTTP",
+		2: "STDOUT",
+		3: "FILE",
+		4: "NONE",
+	}
+	Otel_Trace_Exporter_value = map[string]int32{
+		"OTLP_GRPC": 0,
+		"OTLP_HTTP": 1,
+		"STDOUT":    2,
+		"FILE":      3,
+		"NONE":      4,
+	}
+)
+
+func (x Otel_Trace_Exporter) Enum() *Otel_Trace_Exporter {
+	p := new(Otel_Trace_Exporter)
+	*p = x
+	return p
+}
+
+func (x Otel_Trace_Exporter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Otel_Trace_Exporter) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[4].Descriptor()
+}
+
+func (Otel_Trace_Exporter) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[4]
+}
+
+func (x Otel_Trace_Exporter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Otel_Trace_Exporter.Descriptor instead.
+func (Otel_Trace_Exporter) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 1, 0}
+}
+
+type Otel_Metric_Protocol int32
+
+const (
+	Otel_Metric_GRPC Otel_Metric_Protocol = 0
+	Otel_Metric_HTTP Otel_Metric_Protocol = 1
+)
+
+// Enum value maps for Otel_Metric_Protocol.
+var (
+	Otel_Metric_Protocol_name = map[int32]string{
+		0: "GRPC",
+		1: "HTTP",
+	}
+	Otel_Metric_Protocol_value = map[string]int32{
+		"GRPC": 0,
+		"HTTP": 1,
+	}
+)
+
+func (x Otel_Metric_Protocol) Enum() *Otel_Metric_Protocol {
+	p := new(Otel_Metric_Protocol)
+	*p = x
+	return p
+}
+
+func (x Otel_Metric_Protocol) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Otel_Metric_Protocol) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[5].Descriptor()
+}
+
+func (Otel_Metric_Protocol) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[5]
+}
+
+func (x Otel_Metric_Protocol) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Otel_Metric_Protocol.Descriptor instead.
+func (Otel_Metric_Protocol) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 2, 0}
+}
+
+type Otel_Metric_Temporality int32
+
+const (
+	Otel_Metric_CUMULATIVE Otel_Metric_Temporality = 0
+	Otel_Metric_DELTA      Otel_Metric_Temporality = 1
+)
+
+// Enum value maps for Otel_Metric_Temporality.
+var (
+	Otel_Metric_Temporality_name = map[int32]string{
+		0: "CUMULATIVE",
+		1: "DELTA",
+	}
+	Otel_Metric_Temporality_value = map[string]int32{
+		"CUMULATIVE": 0,
+		"DELTA":      1,
+	}
+)
+
+func (x Otel_Metric_Temporality) Enum() *Otel_Metric_Temporality {
+	p := new(Otel_Metric_Temporality)
+	*p = x
+	return p
+}
+
+func (x Otel_Metric_Temporality) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Otel_Metric_Temporality) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[6].Descriptor()
+}
+
+func (Otel_Metric_Temporality) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[6]
+}
+
+func (x Otel_Metric_Temporality) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Otel_Metric_Temporality.Descriptor instead.
+func (Otel_Metric_Temporality) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 2, 1}
+}
+
+// Access is the authentication an operation requires.
+type Auth_Access int32
+
+const (
+	Auth_AUTHENTICATED Auth_Access = 0
+	Auth_PUBLIC        Auth_Access = 1
+)
+
+// Enum value maps for Auth_Access.
+var (
+	Auth_Access_name = map[int32]string{
+		0: "AUTHENTICATED",
+		1: "PUBLIC",
+	}
+	Auth_Access_value = map[string]int32{
+		"AUTHENTICATED": 0,
+		"PUBLIC":        1,
+	}
+)
+
+func (x Auth_Access) Enum() *Auth_Access {
+	p := new(Auth_Access)
+	*p = x
+	return p
+}
+
+func (x Auth_Access) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Auth_Access) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[7].Descriptor()
+}
+
+func (Auth_Access) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[7]
+}
+
+func (x Auth_Access) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Auth_Access.Descriptor instead.
+func (Auth_Access) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type Authz_Effect int32
+
+const (
+	Authz_ALLOW Authz_Effect = 0
+	Authz_DENY  Authz_Effect = 1
+)
+
+// Enum value maps for Authz_Effect.
+var (
+	Authz_Effect_name = map[int32]string{
+		0: "ALLOW",
+		1: "DENY",
+	}
+	Authz_Effect_value = map[string]int32{
+		"ALLOW": 0,
+		"DENY":  1,
+	}
+)
+
+func (x Authz_Effect) Enum() *Authz_Effect {
+	p := new(Authz_Effect)
+	*p = x
+	return p
+}
+
+func (x Authz_Effect) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Authz_Effect) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[8].Descriptor()
+}
+
+func (Authz_Effect) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[8]
+}
+
+func (x Authz_Effect) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Authz_Effect.Descriptor instead.
+func (Authz_Effect) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type Authz_Operator int32
+
+const (
+	Authz_EQ     Authz_Operator = 0
+	Authz_NE     Authz_Operator = 1
+	Authz_IN     Authz_Operator = 2
+	Authz_NOT_IN Authz_Operator = 3
+	Authz_PREFIX Authz_Operator = 4
+	Authz_REGEX  Authz_Operator = 5
+)
+
+// Enum value maps for Authz_Operator.
+var (
+	Authz_Operator_name = map[int32]string{
+		0: "EQ",
+		1: "NE",
+		2: "IN",
+		3: "NOT_IN",
+		4: "PREFIX",
+		5: "REGEX",
+	}
+	Authz_Operator_value = map[string]int32{
+		"EQ":     0,
+		"NE":     1,
+		"IN":     2,
+		"NOT_IN": 3,
+		"PREFIX": 4,
+		"REGEX":  5,
+	}
+)
+
+func (x Authz_Operator) Enum() *Authz_Operator {
+	p := new(Authz_Operator)
+	*p = x
+	return p
+}
+
+func (x Authz_Operator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Authz_Operator) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[9].Descriptor()
+}
+
+func (Authz_Operator) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[9]
+}
+
+func (x Authz_Operator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Authz_Operator.Descriptor instead.
+func (Authz_Operator) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 1}
+}
+
+type Audit_Sink int32
+
+const (
+	// a dedicated json log file
+	Audit_LOG Audit_Sink = 0
+	// a kafka topic through the data producer
+	Audit_KAFKA Audit_Sink = 1
+)
+
+// Enum value maps for Audit_Sink.
+var (
+	Audit_Sink_name = map[int32]string{
+		0: "LOG",
+		1: "KAFKA",
+	}
+	Audit_Sink_value = map[string]int32{
+		"LOG":   0,
+		"KAFKA": 1,
+	}
+)
+
+func (x Audit_Sink) Enum() *Audit_Sink {
+	p := new(Audit_Sink)
+	*p = x
+	return p
+}
+
+func (x Audit_Sink) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Audit_Sink) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[10].Descriptor()
+}
+
+func (Audit_Sink) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[10]
+}
+
+func (x Audit_Sink) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Audit_Sink.Descriptor instead.
+func (Audit_Sink) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{11, 0}
+}
+
 type Bootstrap struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Env           Environment            `protobuf:"varint,1,opt,name=env,proto3,enum=kratos.api.Environment" json:"env,omitempty"`
-	Metadata      *MetaData              `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	Server        *Server                `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
-	Registry      *Registry              `protobuf:"bytes,4,opt,name=registry,proto3" json:"registry,omitempty"`
-	Bbr           *BBR                   `protobuf:"bytes,5,opt,name=bbr,proto3" json:"bbr,omitempty"`
-	Otel          *Otel                  `protobuf:"bytes,6,opt,name=otel,proto3" json:"otel,omitempty"`
-	Log           *Log                   `protobuf:"bytes,7,opt,name=log,proto3" json:"log,omitempty"`
-	Data          *Data                  `protobuf:"bytes,8,opt,name=data,proto3" json:"data,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Env         Environment            `protobuf:"varint,1,opt,name=env,proto3,enum=kratos.api.Environment" json:"env,omitempty"`
+	Metadata    *MetaData              `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Server      *Server                `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
+	Registry    *Registry              `protobuf:"bytes,4,opt,name=registry,proto3" json:"registry,omitempty"`
+	Bbr         *BBR                   `protobuf:"bytes,5,opt,name=bbr,proto3" json:"bbr,omitempty"`
+	Otel        *Otel                  `protobuf:"bytes,6,opt,name=otel,proto3" json:"otel,omitempty"`
+	Log         *Log                   `protobuf:"bytes,7,opt,name=log,proto3" json:"log,omitempty"`
+	Data        *Data                  `protobuf:"bytes,8,opt,name=data,proto3" json:"data,omitempty"`
+	Auth        *Auth                  `protobuf:"bytes,9,opt,name=auth,proto3" json:"auth,omitempty"`
+	Authz       *Authz                 `protobuf:"bytes,10,opt,name=authz,proto3" json:"authz,omitempty"`
+	Idempotency *Idempotency           `protobuf:"bytes,11,opt,name=idempotency,proto3" json:"idempotency,omitempty"`
+	Audit       *Audit                 `protobuf:"bytes,12,opt,name=audit,proto3" json:"audit,omitempty"`
+	Tenant      *Tenant                `protobuf:"bytes,13,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// feature flags, reloaded live
+	Features map[string]bool `protobuf:"bytes,14,rep,name=features,proto3" json:"features,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// rate limits overriding the ones of the services, reloaded live
+	RateLimits    []*RateLimit `protobuf:"bytes,15,rep,name=rate_limits,json=rateLimits,proto3" json:"rate_limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bootstrap) Reset() {
+	*x = Bootstrap{}
+	mi := &file_conf_conf_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bootstrap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bootstrap) ProtoMessage() {}
+
+func (x *Bootstrap) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bootstrap.ProtoReflect.Descriptor instead.
+func (*Bootstrap) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Bootstrap) GetEnv() Environment {
+	if x != nil {
+		return x.Env
+	}
+	return Environment_NONE
+}
+
+func (x *Bootstrap) GetMetadata() *MetaData {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetServer() *Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetRegistry() *Registry {
+	if x != nil {
+		return x.Registry
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetBbr() *BBR {
+	if x != nil {
+		return x.Bbr
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetOtel() *Otel {
+	if x != nil {
+		return x.Otel
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetLog() *Log {
+	if x != nil {
+		return x.Log
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetData() *Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetAuth() *Auth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetAuthz() *Authz {
+	if x != nil {
+		return x.Authz
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetIdempotency() *Idempotency {
+	if x != nil {
+		return x.Idempotency
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetAudit() *Audit {
+	if x != nil {
+		return x.Audit
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetTenant() *Tenant {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetFeatures() map[string]bool {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetRateLimits() []*RateLimit {
+	if x != nil {
+		return x.RateLimits
+	}
+	return nil
+}
+
+type RateLimit struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Operation string                 `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// requests per second
+	Rate float64 `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	// defaults to the burst of the service, or to a second of requests
+	Burst int32 `protobuf:"varint,3,opt,name=burst,proto3" json:"burst,omitempty"`
+	// wait for a token up to the timeout, defaults to the timeout of the service, or to 1s
+	Timeout       *durationpb.Duration `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimit) Reset() {
+	*x = RateLimit{}
+	mi := &file_conf_conf_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimit) ProtoMessage() {}
+
+func (x *RateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimit.ProtoReflect.Descriptor instead.
+func (*RateLimit) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RateLimit) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *RateLimit) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *RateLimit) GetBurst() int32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *RateLimit) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type MetaData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=Version,proto3" json:"Version,omitempty"`
+	ConfPath      string                 `protobuf:"bytes,3,opt,name=ConfPath,proto3" json:"ConfPath,omitempty"`
+	Id            string                 `protobuf:"bytes,4,opt,name=Id,proto3" json:"Id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetaData) Reset() {
+	*x = MetaData{}
+	mi := &file_conf_conf_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetaData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaData) ProtoMessage() {}
+
+func (x *MetaData) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaData.ProtoReflect.Descriptor instead.
+func (*MetaData) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MetaData) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetaData) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *MetaData) GetConfPath() string {
+	if x != nil {
+		return x.ConfPath
+	}
+	return ""
+}
+
+func (x *MetaData) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Log struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Filepath   string                 `protobuf:"bytes,1,opt,name=filepath,proto3" json:"filepath,omitempty"`
+	MaxSize    int32                  `protobuf:"varint,2,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	MaxBackups int32                  `protobuf:"varint,3,opt,name=max_backups,json=maxBackups,proto3" json:"max_backups,omitempty"`
+	// debug -1, info 0, warn 1, error 2, dPanic 3, panic 4, fatal 5
+	Level         int32     `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`
+	MaxAge        int32     `protobuf:"varint,5,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	Otlp          *Log_OTLP `protobuf:"bytes,6,opt,name=otlp,proto3" json:"otlp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Log) Reset() {
+	*x = Log{}
+	mi := &file_conf_conf_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Log) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Log) ProtoMessage() {}
+
+func (x *Log) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Log.ProtoReflect.Descriptor instead.
+func (*Log) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Log) GetFilepath() string {
+	if x != nil {
+		return x.Filepath
+	}
+	return ""
+}
+
+func (x *Log) GetMaxSize() int32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *Log) GetMaxBackups() int32 {
+	if x != nil {
+		return x.MaxBackups
+	}
+	return 0
+}
+
+func (x *Log) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *Log) GetMaxAge() int32 {
+	if x != nil {
+		return x.MaxAge
+	}
+	return 0
+}
+
+func (x *Log) GetOtlp() *Log_OTLP {
+	if x != nil {
+		return x.Otlp
+	}
+	return nil
+}
+
+type Server struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Http          *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
+	Grpc          *Server_GRPC           `protobuf:"bytes,2,opt,name=grpc,proto3" json:"grpc,omitempty"`
+	Pprof         *Server_Pprof          `protobuf:"bytes,3,opt,name=pprof,proto3" json:"pprof,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server) Reset() {
+	*x = Server{}
+	mi := &file_conf_conf_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server) ProtoMessage() {}
+
+func (x *Server) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server.ProtoReflect.Descriptor instead.
+func (*Server) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Server) GetHttp() *Server_HTTP {
+	if x != nil {
+		return x.Http
+	}
+	return nil
+}
+
+func (x *Server) GetGrpc() *Server_GRPC {
+	if x != nil {
+		return x.Grpc
+	}
+	return nil
+}
+
+func (x *Server) GetPprof() *Server_Pprof {
+	if x != nil {
+		return x.Pprof
+	}
+	return nil
+}
+
+type Registry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Endpoint      []string               `protobuf:"bytes,1,rep,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Registry) Reset() {
+	*x = Registry{}
+	mi := &file_conf_conf_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Registry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Registry) ProtoMessage() {}
+
+func (x *Registry) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Registry.ProtoReflect.Descriptor instead.
+func (*Registry) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Registry) GetEndpoint() []string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type BBR struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	WindowSize *durationpb.Duration   `protobuf:"bytes,1,opt,name=window_size,json=windowSize,proto3" json:"window_size,omitempty"`
+	Bucket     int32                  `protobuf:"varint,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	// cpu usage in per mille
+	CpuThreshold  int64 `protobuf:"varint,3,opt,name=cpu_threshold,json=cpuThreshold,proto3" json:"cpu_threshold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BBR) Reset() {
+	*x = BBR{}
+	mi := &file_conf_conf_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BBR) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BBR) ProtoMessage() {}
+
+func (x *BBR) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BBR.ProtoReflect.Descriptor instead.
+func (*BBR) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BBR) GetWindowSize() *durationpb.Duration {
+	if x != nil {
+		return x.WindowSize
+	}
+	return nil
+}
+
+func (x *BBR) GetBucket() int32 {
+	if x != nil {
+		return x.Bucket
+	}
+	return 0
+}
+
+func (x *BBR) GetCpuThreshold() int64 {
+	if x != nil {
+		return x.CpuThreshold
+	}
+	return 0
+}
+
+type Otel struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Trace  *Otel_Trace            `protobuf:"bytes,1,opt,name=trace,proto3" json:"trace,omitempty"`
+	Metric *Otel_Metric           `protobuf:"bytes,2,opt,name=metric,proto3" json:"metric,omitempty"`
+	// extra resource attributes of the traces, metrics and logs, overridden by OTEL_RESOURCE_ATTRIBUTES
+	ResourceAttributes map[string]string `protobuf:"bytes,3,rep,name=resource_attributes,json=resourceAttributes,proto3" json:"resource_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Otel) Reset() {
+	*x = Otel{}
+	mi := &file_conf_conf_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Otel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Otel) ProtoMessage() {}
+
+func (x *Otel) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Otel.ProtoReflect.Descriptor instead.
+func (*Otel) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Otel) GetTrace() *Otel_Trace {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+func (x *Otel) GetMetric() *Otel_Metric {
+	if x != nil {
+		return x.Metric
+	}
+	return nil
+}
+
+func (x *Otel) GetResourceAttributes() map[string]string {
+	if x != nil {
+		return x.ResourceAttributes
+	}
+	return nil
+}
+
+type Auth struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Jwt      *Auth_JWT              `protobuf:"bytes,1,opt,name=jwt,proto3" json:"jwt,omitempty"`
+	Policies []*Auth_Policy         `protobuf:"bytes,2,rep,name=policies,proto3" json:"policies,omitempty"`
+	// access of the operations without a policy
+	DefaultAccess Auth_Access   `protobuf:"varint,3,opt,name=default_access,json=defaultAccess,proto3,enum=kratos.api.Auth_Access" json:"default_access,omitempty"`
+	ApiKeys       *Auth_APIKeys `protobuf:"bytes,4,opt,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	Hmac          *Auth_HMAC    `protobuf:"bytes,5,opt,name=hmac,proto3" json:"hmac,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Auth) Reset() {
+	*x = Auth{}
+	mi := &file_conf_conf_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Auth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Auth) ProtoMessage() {}
+
+func (x *Auth) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Auth.ProtoReflect.Descriptor instead.
+func (*Auth) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Auth) GetJwt() *Auth_JWT {
+	if x != nil {
+		return x.Jwt
+	}
+	return nil
+}
+
+func (x *Auth) GetPolicies() []*Auth_Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+func (x *Auth) GetDefaultAccess() Auth_Access {
+	if x != nil {
+		return x.DefaultAccess
+	}
+	return Auth_AUTHENTICATED
+}
+
+func (x *Auth) GetApiKeys() *Auth_APIKeys {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+func (x *Auth) GetHmac() *Auth_HMAC {
+	if x != nil {
+		return x.Hmac
+	}
+	return nil
+}
+
+type Authz struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// policies are loaded from the file, the etcd key using registry endpoints,
+	// or inline, and reloaded on change, the file and the etcd key hold an
+	// authz document laid out like this config: authz: {default_effect, policies}
+	File    string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	EtcdKey string `protobuf:"bytes,2,opt,name=etcd_key,json=etcdKey,proto3" json:"etcd_key,omitempty"`
+	// effect of the operations without a policy
+	DefaultEffect Authz_Effect    `protobuf:"varint,3,opt,name=default_effect,json=defaultEffect,proto3,enum=kratos.api.Authz_Effect" json:"default_effect,omitempty"`
+	Policies      []*Authz_Policy `protobuf:"bytes,4,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Authz) Reset() {
+	*x = Authz{}
+	mi := &file_conf_conf_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Authz) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Authz) ProtoMessage() {}
+
+func (x *Authz) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Authz.ProtoReflect.Descriptor instead.
+func (*Authz) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Authz) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Authz) GetEtcdKey() string {
+	if x != nil {
+		return x.EtcdKey
+	}
+	return ""
+}
+
+func (x *Authz) GetDefaultEffect() Authz_Effect {
+	if x != nil {
+		return x.DefaultEffect
+	}
+	return Authz_ALLOW
+}
+
+func (x *Authz) GetPolicies() []*Authz_Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+type Idempotency struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	RedisAlias string                 `protobuf:"bytes,1,opt,name=redis_alias,json=redisAlias,proto3" json:"redis_alias,omitempty"`
+	RedisShard int32                  `protobuf:"varint,2,opt,name=redis_shard,json=redisShard,proto3" json:"redis_shard,omitempty"`
+	// kratos operations honoring the idempotency key
+	Operations []string `protobuf:"bytes,3,rep,name=operations,proto3" json:"operations,omitempty"`
+	// defaults to Idempotency-Key
+	Header string `protobuf:"bytes,4,opt,name=header,proto3" json:"header,omitempty"`
+	// how long the results are replayed, defaults to 24h
+	Ttl *durationpb.Duration `protobuf:"bytes,5,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// how long a key stays locked while in progress, should exceed the server timeout, defaults to 30s
+	LockTtl *durationpb.Duration `protobuf:"bytes,6,opt,name=lock_ttl,json=lockTtl,proto3" json:"lock_ttl,omitempty"`
+	// reject the requests of the operations without a key
+	Required      bool `protobuf:"varint,7,opt,name=required,proto3" json:"required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Idempotency) Reset() {
+	*x = Idempotency{}
+	mi := &file_conf_conf_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Idempotency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Idempotency) ProtoMessage() {}
+
+func (x *Idempotency) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Idempotency.ProtoReflect.Descriptor instead.
+func (*Idempotency) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Idempotency) GetRedisAlias() string {
+	if x != nil {
+		return x.RedisAlias
+	}
+	return ""
+}
+
+func (x *Idempotency) GetRedisShard() int32 {
+	if x != nil {
+		return x.RedisShard
+	}
+	return 0
+}
+
+func (x *Idempotency) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *Idempotency) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *Idempotency) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *Idempotency) GetLockTtl() *durationpb.Duration {
+	if x != nil {
+		return x.LockTtl
+	}
+	return nil
+}
+
+func (x *Idempotency) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+type Audit struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Enable bool                   `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	// audited operations, a trailing * matches the prefix, empty for all
+	Operations []string `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	// redacted fields besides the (audit.v1.sensitive) ones, a name matches at
+	// any depth, a dotted path from the root message, eg: password, user.token
+	RedactFields []string   `protobuf:"bytes,3,rep,name=redact_fields,json=redactFields,proto3" json:"redact_fields,omitempty"`
+	SkipRequest  bool       `protobuf:"varint,4,opt,name=skip_request,json=skipRequest,proto3" json:"skip_request,omitempty"`
+	SkipResponse bool       `protobuf:"varint,5,opt,name=skip_response,json=skipResponse,proto3" json:"skip_response,omitempty"`
+	Sink         Audit_Sink `protobuf:"varint,6,opt,name=sink,proto3,enum=kratos.api.Audit_Sink" json:"sink,omitempty"`
+	// the LOG sink
+	Log *Log `protobuf:"bytes,7,opt,name=log,proto3" json:"log,omitempty"`
+	// the KAFKA sink, the records are dropped and counted by
+	// audit.records.dropped when the producer is full
+	KafkaTopic    string `protobuf:"bytes,8,opt,name=kafka_topic,json=kafkaTopic,proto3" json:"kafka_topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Audit) Reset() {
+	*x = Audit{}
+	mi := &file_conf_conf_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Audit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Audit) ProtoMessage() {}
+
+func (x *Audit) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Audit.ProtoReflect.Descriptor instead.
+func (*Audit) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Audit) GetEnable() bool {
+	if x != nil {
+		return x.Enable
+	}
+	return false
+}
+
+func (x *Audit) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *Audit) GetRedactFields() []string {
+	if x != nil {
+		return x.RedactFields
+	}
+	return nil
+}
+
+func (x *Audit) GetSkipRequest() bool {
+	if x != nil {
+		return x.SkipRequest
+	}
+	return false
+}
+
+func (x *Audit) GetSkipResponse() bool {
+	if x != nil {
+		return x.SkipResponse
+	}
+	return false
+}
+
+func (x *Audit) GetSink() Audit_Sink {
+	if x != nil {
+		return x.Sink
+	}
+	return Audit_LOG
+}
+
+func (x *Audit) GetLog() *Log {
+	if x != nil {
+		return x.Log
+	}
+	return nil
+}
+
+func (x *Audit) GetKafkaTopic() string {
+	if x != nil {
+		return x.KafkaTopic
+	}
+	return ""
+}
+
+type Tenant struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Enable bool                   `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	// the tenant is resolved from the tenant_id claim of the access token, then
+	// the header, the propagated metadata and the subdomain of the host
+	// defaults to X-Tenant-Id
+	Header string `protobuf:"bytes,2,opt,name=header,proto3" json:"header,omitempty"`
+	// base domain of the subdomains, eg: example.com resolves acme.example.com to acme
+	Domain string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	// reject the requests without a tenant
+	Required bool `protobuf:"varint,4,opt,name=required,proto3" json:"required,omitempty"`
+	// operations not bound to a tenant, a trailing * matches the prefix
+	SkipOperations []string `protobuf:"bytes,5,rep,name=skip_operations,json=skipOperations,proto3" json:"skip_operations,omitempty"`
+	// scopes of the callers allowed to pick the tenant, eg: the internal services,
+	// the other callers are bound to their tenant_id claim, the anonymous ones
+	// to the subdomain, and the header and the metadata are rejected
+	SwitchScopes []string `protobuf:"bytes,6,rep,name=switch_scopes,json=switchScopes,proto3" json:"switch_scopes,omitempty"`
+	// trust the propagated x-md-global-tenant-id metadata of the callers not
+	// bound to a tenant, for the internal hops behind a gateway that strips it,
+	// the downstream services see the tenant resolved by the edge service
+	TrustMetadata bool `protobuf:"varint,7,opt,name=trust_metadata,json=trustMetadata,proto3" json:"trust_metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_conf_conf_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Tenant) GetEnable() bool {
+	if x != nil {
+		return x.Enable
+	}
+	return false
+}
+
+func (x *Tenant) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *Tenant) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Tenant) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *Tenant) GetSkipOperations() []string {
+	if x != nil {
+		return x.SkipOperations
+	}
+	return nil
+}
+
+func (x *Tenant) GetSwitchScopes() []string {
+	if x != nil {
+		return x.SwitchScopes
+	}
+	return nil
+}
+
+func (x *Tenant) GetTrustMetadata() bool {
+	if x != nil {
+		return x.TrustMetadata
+	}
+	return false
+}
+
+type Data struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Database      map[string]*Data_Database `protobuf:"bytes,1,rep,name=database,proto3" json:"database,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Redis         map[string]*Data_Redis    `protobuf:"bytes,2,rep,name=redis,proto3" json:"redis,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Kafka         *Data_Kafka               `protobuf:"bytes,3,opt,name=kafka,proto3" json:"kafka,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data) Reset() {
+	*x = Data{}
+	mi := &file_conf_conf_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data) ProtoMessage() {}
+
+func (x *Data) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data.ProtoReflect.Descriptor instead.
+func (*Data) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Data) GetDatabase() map[string]*Data_Database {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
+
+func (x *Data) GetRedis() map[string]*Data_Redis {
+	if x != nil {
+		return x.Redis
+	}
+	return nil
+}
+
+func (x *Data) GetKafka() *Data_Kafka {
+	if x != nil {
+		return x.Kafka
+	}
+	return nil
+}
+
+// OTLP emits every record as an OTel LogRecord besides the files
+type Log_OTLP struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Enable   bool                   `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	Protocol Log_OTLP_Protocol      `protobuf:"varint,2,opt,name=protocol,proto3,enum=kratos.api.Log_OTLP_Protocol" json:"protocol,omitempty"`
+	Endpoint string                 `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Insecure bool                   `protobuf:"varint,4,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	Headers  map[string]string      `protobuf:"bytes,5,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// gzip or none
+	Compression string `protobuf:"bytes,6,opt,name=compression,proto3" json:"compression,omitempty"`
+	// HTTP url path, defaults to /v1/logs
+	UrlPath string               `protobuf:"bytes,7,opt,name=url_path,json=urlPath,proto3" json:"url_path,omitempty"`
+	Timeout *durationpb.Duration `protobuf:"bytes,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// the records beyond the queue are dropped while the collector is unreachable, defaults to 2048
+	MaxQueueSize int32 `protobuf:"varint,9,opt,name=max_queue_size,json=maxQueueSize,proto3" json:"max_queue_size,omitempty"`
+	// defaults to 1s
+	ExportInterval *durationpb.Duration `protobuf:"bytes,10,opt,name=export_interval,json=exportInterval,proto3" json:"export_interval,omitempty"`
+	// defaults to 512
+	ExportMaxBatchSize int32 `protobuf:"varint,11,opt,name=export_max_batch_size,json=exportMaxBatchSize,proto3" json:"export_max_batch_size,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Log_OTLP) Reset() {
+	*x = Log_OTLP{}
+	mi := &file_conf_conf_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Log_OTLP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Log_OTLP) ProtoMessage() {}
+
+func (x *Log_OTLP) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Log_OTLP.ProtoReflect.Descriptor instead.
+func (*Log_OTLP) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *Log_OTLP) GetEnable() bool {
+	if x != nil {
+		return x.Enable
+	}
+	return false
+}
+
+func (x *Log_OTLP) GetProtocol() Log_OTLP_Protocol {
+	if x != nil {
+		return x.Protocol
+	}
+	return Log_OTLP_GRPC
+}
+
+func (x *Log_OTLP) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *Log_OTLP) GetInsecure() bool {
+	if x != nil {
+		return x.Insecure
+	}
+	return false
+}
+
+func (x *Log_OTLP) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Log_OTLP) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *Log_OTLP) GetUrlPath() string {
+	if x != nil {
+		return x.UrlPath
+	}
+	return ""
+}
+
+func (x *Log_OTLP) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Log_OTLP) GetMaxQueueSize() int32 {
+	if x != nil {
+		return x.MaxQueueSize
+	}
+	return 0
+}
+
+func (x *Log_OTLP) GetExportInterval() *durationpb.Duration {
+	if x != nil {
+		return x.ExportInterval
+	}
+	return nil
+}
+
+func (x *Log_OTLP) GetExportMaxBatchSize() int32 {
+	if x != nil {
+		return x.ExportMaxBatchSize
+	}
+	return 0
+}
+
+type Server_HTTP struct {
+	state           protoimpl.MessageState       `protogen:"open.v1"`
+	Network         string                       `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Addr            string                       `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Timeout         *durationpb.Duration         `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Envelope        Server_HTTP_Envelope         `protobuf:"varint,4,opt,name=envelope,proto3,enum=kratos.api.Server_HTTP_Envelope" json:"envelope,omitempty"`
+	Cors            *Server_HTTP_CORS            `protobuf:"bytes,5,opt,name=cors,proto3" json:"cors,omitempty"`
+	SecurityHeaders *Server_HTTP_SecurityHeaders `protobuf:"bytes,6,opt,name=security_headers,json=securityHeaders,proto3" json:"security_headers,omitempty"`
+	// max request body size in bytes, 0 means unlimited
+	MaxBodySize int64 `protobuf:"varint,7,opt,name=max_body_size,json=maxBodySize,proto3" json:"max_body_size,omitempty"`
+	// max request body size per route, keyed by path prefix matched on the
+	// segment boundary, the longest prefix wins
+	RouteMaxBodySize map[string]int64 `protobuf:"bytes,8,rep,name=route_max_body_size,json=routeMaxBodySize,proto3" json:"route_max_body_size,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Server_HTTP) Reset() {
+	*x = Server_HTTP{}
+	mi := &file_conf_conf_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_HTTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_HTTP) ProtoMessage() {}
+
+func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_HTTP.ProtoReflect.Descriptor instead.
+func (*Server_HTTP) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *Server_HTTP) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Server_HTTP) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *Server_HTTP) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Server_HTTP) GetEnvelope() Server_HTTP_Envelope {
+	if x != nil {
+		return x.Envelope
+	}
+	return Server_HTTP_NONE
+}
+
+func (x *Server_HTTP) GetCors() *Server_HTTP_CORS {
+	if x != nil {
+		return x.Cors
+	}
+	return nil
+}
+
+func (x *Server_HTTP) GetSecurityHeaders() *Server_HTTP_SecurityHeaders {
+	if x != nil {
+		return x.SecurityHeaders
+	}
+	return nil
+}
+
+func (x *Server_HTTP) GetMaxBodySize() int64 {
+	if x != nil {
+		return x.MaxBodySize
+	}
+	return 0
+}
+
+func (x *Server_HTTP) GetRouteMaxBodySize() map[string]int64 {
+	if x != nil {
+		return x.RouteMaxBodySize
+	}
+	return nil
+}
+
+type Server_GRPC struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_GRPC) Reset() {
+	*x = Server_GRPC{}
+	mi := &file_conf_conf_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_GRPC) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_GRPC) ProtoMessage() {}
+
+func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_GRPC.ProtoReflect.Descriptor instead.
+func (*Server_GRPC) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 1}
+}
+
+func (x *Server_GRPC) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Server_GRPC) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *Server_GRPC) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type Server_Pprof struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Addr          string                 `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	Watchdog      *Server_Pprof_Watchdog `protobuf:"bytes,2,opt,name=watchdog,proto3" json:"watchdog,omitempty"`
+	Push          *Server_Pprof_Push     `protobuf:"bytes,3,opt,name=push,proto3" json:"push,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_Pprof) Reset() {
+	*x = Server_Pprof{}
+	mi := &file_conf_conf_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Pprof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Pprof) ProtoMessage() {}
+
+func (x *Server_Pprof) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Pprof.ProtoReflect.Descriptor instead.
+func (*Server_Pprof) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 2}
+}
+
+func (x *Server_Pprof) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *Server_Pprof) GetWatchdog() *Server_Pprof_Watchdog {
+	if x != nil {
+		return x.Watchdog
+	}
+	return nil
+}
+
+func (x *Server_Pprof) GetPush() *Server_Pprof_Push {
+	if x != nil {
+		return x.Push
+	}
+	return nil
+}
+
+type Server_HTTP_CORS struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Enable bool                   `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	// exact origins, "*" or wildcard subdomains like "https://*.example.com"
+	AllowOrigins []string `protobuf:"bytes,2,rep,name=allow_origins,json=allowOrigins,proto3" json:"allow_origins,omitempty"`
+	// defaults to GET, POST, PUT, PATCH, DELETE, HEAD
+	AllowMethods []string `protobuf:"bytes,3,rep,name=allow_methods,json=allowMethods,proto3" json:"allow_methods,omitempty"`
+	// defaults to the headers requested by the preflight
+	AllowHeaders []string `protobuf:"bytes,4,rep,name=allow_headers,json=allowHeaders,proto3" json:"allow_headers,omitempty"`
+	// defaults to the trace headers of otel.trace.response and X-Request-Id
+	ExposeHeaders    []string             `protobuf:"bytes,5,rep,name=expose_headers,json=exposeHeaders,proto3" json:"expose_headers,omitempty"`
+	AllowCredentials bool                 `protobuf:"varint,6,opt,name=allow_credentials,json=allowCredentials,proto3" json:"allow_credentials,omitempty"`
+	MaxAge           *durationpb.Duration `protobuf:"bytes,7,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Server_HTTP_CORS) Reset() {
+	*x = Server_HTTP_CORS{}
+	mi := &file_conf_conf_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_HTTP_CORS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_HTTP_CORS) ProtoMessage() {}
+
+func (x *Server_HTTP_CORS) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_HTTP_CORS.ProtoReflect.Descriptor instead.
+func (*Server_HTTP_CORS) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 0, 0}
+}
+
+func (x *Server_HTTP_CORS) GetEnable() bool {
+	if x != nil {
+		return x.Enable
+	}
+	return false
+}
+
+func (x *Server_HTTP_CORS) GetAllowOrigins() []string {
+	if x != nil {
+		return x.AllowOrigins
+	}
+	return nil
+}
+
+func (x *Server_HTTP_CORS) GetAllowMethods() []string {
+	if x != nil {
+		return x.AllowMethods
+	}
+	return nil
+}
+
+func (x *Server_HTTP_CORS) GetAllowHeaders() []string {
+	if x != nil {
+		return x.AllowHeaders
+	}
+	return nil
+}
+
+func (x *Server_HTTP_CORS) GetExposeHeaders() []string {
+	if x != nil {
+		return x.ExposeHeaders
+	}
+	return nil
+}
+
+func (x *Server_HTTP_CORS) GetAllowCredentials() bool {
+	if x != nil {
+		return x.AllowCredentials
+	}
+	return false
+}
+
+func (x *Server_HTTP_CORS) GetMaxAge() *durationpb.Duration {
+	if x != nil {
+		return x.MaxAge
+	}
+	return nil
+}
+
+type Server_HTTP_SecurityHeaders struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Enable bool                   `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	// Strict-Transport-Security max-age, 0 disables the header
+	HstsMaxAge            *durationpb.Duration `protobuf:"bytes,2,opt,name=hsts_max_age,json=hstsMaxAge,proto3" json:"hsts_max_age,omitempty"`
+	HstsIncludeSubdomains bool                 `protobuf:"varint,3,opt,name=hsts_include_subdomains,json=hstsIncludeSubdomains,proto3" json:"hsts_include_subdomains,omitempty"`
+	HstsPreload           bool                 `protobuf:"varint,4,opt,name=hsts_preload,json=hstsPreload,proto3" json:"hsts_preload,omitempty"`
+	// X-Frame-Options, defaults to DENY
+	FrameOptions          string `protobuf:"bytes,5,opt,name=frame_options,json=frameOptions,proto3" json:"frame_options,omitempty"`
+	ReferrerPolicy        string `protobuf:"bytes,6,opt,name=referrer_policy,json=referrerPolicy,proto3" json:"referrer_policy,omitempty"`
+	ContentSecurityPolicy string `protobuf:"bytes,7,opt,name=content_security_policy,json=contentSecurityPolicy,proto3" json:"content_security_policy,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Server_HTTP_SecurityHeaders) Reset() {
+	*x = Server_HTTP_SecurityHeaders{}
+	mi := &file_conf_conf_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_HTTP_SecurityHeaders) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_HTTP_SecurityHeaders) ProtoMessage() {}
+
+func (x *Server_HTTP_SecurityHeaders) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_HTTP_SecurityHeaders.ProtoReflect.Descriptor instead.
+func (*Server_HTTP_SecurityHeaders) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 0, 1}
+}
+
+func (x *Server_HTTP_SecurityHeaders) GetEnable() bool {
+	if x != nil {
+		return x.Enable
+	}
+	return false
+}
+
+func (x *Server_HTTP_SecurityHeaders) GetHstsMaxAge() *durationpb.Duration {
+	if x != nil {
+		return x.HstsMaxAge
+	}
+	return nil
+}
+
+func (x *Server_HTTP_SecurityHeaders) GetHstsIncludeSubdomains() bool {
+	if x != nil {
+		return x.HstsIncludeSubdomains
+	}
+	return false
+}
+
+func (x *Server_HTTP_SecurityHeaders) GetHstsPreload() bool {
+	if x != nil {
+		return x.HstsPreload
+	}
+	return false
+}
+
+func (x *Server_HTTP_SecurityHeaders) GetFrameOptions() string {
+	if x != nil {
+		return x.FrameOptions
+	}
+	return ""
+}
+
+func (x *Server_HTTP_SecurityHeaders) GetReferrerPolicy() string {
+	if x != nil {
+		return x.ReferrerPolicy
+	}
+	return ""
+}
+
+func (x *Server_HTTP_SecurityHeaders) GetContentSecurityPolicy() string {
+	if x != nil {
+		return x.ContentSecurityPolicy
+	}
+	return ""
+}
+
+type Server_Pprof_Watchdog struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Enable bool                   `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	// sampling interval
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// process cpu usage percent, normalized by GOMAXPROCS, 0 disables the check
+	CpuThreshold float64 `protobuf:"fixed64,3,opt,name=cpu_threshold,json=cpuThreshold,proto3" json:"cpu_threshold,omitempty"`
+	// heap in use bytes, 0 disables the check
+	HeapThreshold uint64 `protobuf:"varint,4,opt,name=heap_threshold,json=heapThreshold,proto3" json:"heap_threshold,omitempty"`
+	// number of goroutines, 0 disables the check
+	GoroutineThreshold int64 `protobuf:"varint,5,opt,name=goroutine_threshold,json=goroutineThreshold,proto3" json:"goroutine_threshold,omitempty"`
+	// directory the profiles are written to, defaults to pprof under the temp dir
+	Dir string `protobuf:"bytes,6,opt,name=dir,proto3" json:"dir,omitempty"`
+	// profiles kept per type
+	MaxFiles int32 `protobuf:"varint,7,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	// minimum time between two captures
+	Cooldown *durationpb.Duration `protobuf:"bytes,8,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	// cpu profile duration
+	CpuDuration *durationpb.Duration `protobuf:"bytes,9,opt,name=cpu_duration,json=cpuDuration,proto3" json:"cpu_duration,omitempty"`
+	// runtime.SetMutexProfileFraction, 0 keeps the runtime default
+	MutexProfileFraction int32 `protobuf:"varint,10,opt,name=mutex_profile_fraction,json=mutexProfileFraction,proto3" json:"mutex_profile_fraction,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Server_Pprof_Watchdog) Reset() {
+	*x = Server_Pprof_Watchdog{}
+	mi := &file_conf_conf_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Pprof_Watchdog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Pprof_Watchdog) ProtoMessage() {}
+
+func (x *Server_Pprof_Watchdog) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Pprof_Watchdog.ProtoReflect.Descriptor instead.
+func (*Server_Pprof_Watchdog) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 2, 0}
+}
+
+func (x *Server_Pprof_Watchdog) GetEnable() bool {
+	if x != nil {
+		return x.Enable
+	}
+	return false
+}
+
+func (x *Server_Pprof_Watchdog) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *Server_Pprof_Watchdog) GetCpuThreshold() float64 {
+	if x != nil {
+		return x.CpuThreshold
+	}
+	return 0
+}
+
+func (x *Server_Pprof_Watchdog) GetHeapThreshold() uint64 {
+	if x != nil {
+		return x.HeapThreshold
+	}
+	return 0
+}
+
+func (x *Server_Pprof_Watchdog) GetGoroutineThreshold() int64 {
+	if x != nil {
+		return x.GoroutineThreshold
+	}
+	return 0
+}
+
+func (x *Server_Pprof_Watchdog) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *Server_Pprof_Watchdog) GetMaxFiles() int32 {
+	if x != nil {
+		return x.MaxFiles
+	}
+	return 0
+}
+
+func (x *Server_Pprof_Watchdog) GetCooldown() *durationpb.Duration {
+	if x != nil {
+		return x.Cooldown
+	}
+	return nil
+}
+
+func (x *Server_Pprof_Watchdog) GetCpuDuration() *durationpb.Duration {
+	if x != nil {
+		return x.CpuDuration
+	}
+	return nil
+}
+
+func (x *Server_Pprof_Watchdog) GetMutexProfileFraction() int32 {
+	if x != nil {
+		return x.MutexProfileFraction
+	}
+	return 0
+}
+
+// Push periodically uploads profiles to a pyroscope compatible ingest endpoint.
+type Server_Pprof_Push struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Enable bool                   `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	// base url of the collector, the profiles are posted to {endpoint}/ingest
+	Endpoint string `protobuf:"bytes,2,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// upload interval
+	Interval *durationpb.Duration `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	Timeout  *durationpb.Duration `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// cpu, heap, goroutine. defaults to all of them
+	Profiles []string `protobuf:"bytes,5,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	// extra labels attached to every profile
+	Labels map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// extra http headers, eg: Authorization, X-Scope-OrgID
+	Headers map[string]string `protobuf:"bytes,7,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// cpu profile duration of every interval, defaults to half of it, the
+	// watchdog and /debug/pprof/profile use the profiler the rest of the time
+	CpuDuration   *durationpb.Duration `protobuf:"bytes,8,opt,name=cpu_duration,json=cpuDuration,proto3" json:"cpu_duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_Pprof_Push) Reset() {
+	*x = Server_Pprof_Push{}
+	mi := &file_conf_conf_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Pprof_Push) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Pprof_Push) ProtoMessage() {}
+
+func (x *Server_Pprof_Push) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Pprof_Push.ProtoReflect.Descriptor instead.
+func (*Server_Pprof_Push) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4, 2, 1}
+}
+
+func (x *Server_Pprof_Push) GetEnable() bool {
+	if x != nil {
+		return x.Enable
+	}
+	return false
+}
+
+func (x *Server_Pprof_Push) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *Server_Pprof_Push) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *Server_Pprof_Push) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Server_Pprof_Push) GetProfiles() []string {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+func (x *Server_Pprof_Push) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Server_Pprof_Push) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Server_Pprof_Push) GetCpuDuration() *durationpb.Duration {
+	if x != nil {
+		return x.CpuDuration
+	}
+	return nil
+}
+
+type Otel_Sampler struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Type      Otel_Sampler_Type      `protobuf:"varint,1,opt,name=type,proto3,enum=kratos.api.Otel_Sampler_Type" json:"type,omitempty"`
+	Ratio     float64                `protobuf:"fixed64,2,opt,name=ratio,proto3" json:"ratio,omitempty"`
+	RateLimit float64                `protobuf:"fixed64,3,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// the sampled flag of the remote parent is followed unless ignored
+	IgnoreParent bool `protobuf:"varint,4,opt,name=ignore_parent,json=ignoreParent,proto3" json:"ignore_parent,omitempty"`
+	// ratio per span name, the kratos operation of the server spans, a trailing * matches the prefix
+	// eg: /grpc.health.v1.Health/*: 0
+	Operations map[string]float64 `protobuf:"bytes,5,rep,name=operations,proto3" json:"operations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	// the unsampled spans are recorded, and exported when they end with an error,
+	// recording every span costs about as much as sampling them all, and the
+	// error spans are exported without their parents, prefer the tail sampling
+	// of the collector in production
+	AlwaysSampleErrors bool `protobuf:"varint,6,opt,name=always_sample_errors,json=alwaysSampleErrors,proto3" json:"always_sample_errors,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Otel_Sampler) Reset() {
+	*x = Otel_Sampler{}
+	mi := &file_conf_conf_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Otel_Sampler) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Otel_Sampler) ProtoMessage() {}
+
+func (x *Otel_Sampler) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Otel_Sampler.ProtoReflect.Descriptor instead.
+func (*Otel_Sampler) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 0}
+}
+
+func (x *Otel_Sampler) GetType() Otel_Sampler_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Otel_Sampler_ALWAYS_ON
+}
+
+func (x *Otel_Sampler) GetRatio() float64 {
+	if x != nil {
+		return x.Ratio
+	}
+	return 0
+}
+
+func (x *Otel_Sampler) GetRateLimit() float64 {
+	if x != nil {
+		return x.RateLimit
+	}
+	return 0
+}
+
+func (x *Otel_Sampler) GetIgnoreParent() bool {
+	if x != nil {
+		return x.IgnoreParent
+	}
+	return false
+}
+
+func (x *Otel_Sampler) GetOperations() map[string]float64 {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *Otel_Sampler) GetAlwaysSampleErrors() bool {
+	if x != nil {
+		return x.AlwaysSampleErrors
+	}
+	return false
+}
+
+type Otel_Trace struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Endpoint string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Insecure bool                   `protobuf:"varint,2,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	Sampler  *Otel_Sampler          `protobuf:"bytes,3,opt,name=sampler,proto3" json:"sampler,omitempty"`
+	// sampler per Environment name, eg: PROD, overriding sampler
+	EnvironmentSamplers map[string]*Otel_Sampler `protobuf:"bytes,4,rep,name=environment_samplers,json=environmentSamplers,proto3" json:"environment_samplers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Exporter            Otel_Trace_Exporter      `protobuf:"varint,5,opt,name=exporter,proto3,enum=kratos.api.Otel_Trace_Exporter" json:"exporter,omitempty"`
+	// OTLP headers, eg: the authorization of the collector
+	Headers map[string]string `protobuf:"bytes,6,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// OTLP compression, gzip or none
+	Compression string `protobuf:"bytes,7,opt,name=compression,proto3" json:"compression,omitempty"`
+	// OTLP_HTTP url path, defaults to /v1/traces
+	UrlPath string `protobuf:"bytes,8,opt,name=url_path,json=urlPath,proto3" json:"url_path,omitempty"`
+	// OTLP export timeout
+	Timeout *durationpb.Duration `protobuf:"bytes,9,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// FILE path
+	File          string               `protobuf:"bytes,10,opt,name=file,proto3" json:"file,omitempty"`
+	Enrich        *Otel_Trace_Enrich   `protobuf:"bytes,11,opt,name=enrich,proto3" json:"enrich,omitempty"`
+	Response      *Otel_Trace_Response `protobuf:"bytes,12,opt,name=response,proto3" json:"response,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Otel_Trace) Reset() {
+	*x = Otel_Trace{}
+	mi := &file_conf_conf_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Otel_Trace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Otel_Trace) ProtoMessage() {}
+
+func (x *Otel_Trace) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Otel_Trace.ProtoReflect.Descriptor instead.
+func (*Otel_Trace) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 1}
+}
+
+func (x *Otel_Trace) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *Otel_Trace) GetInsecure() bool {
+	if x != nil {
+		return x.Insecure
+	}
+	return false
+}
+
+func (x *Otel_Trace) GetSampler() *Otel_Sampler {
+	if x != nil {
+		return x.Sampler
+	}
+	return nil
+}
+
+func (x *Otel_Trace) GetEnvironmentSamplers() map[string]*Otel_Sampler {
+	if x != nil {
+		return x.EnvironmentSamplers
+	}
+	return nil
+}
+
+func (x *Otel_Trace) GetExporter() Otel_Trace_Exporter {
+	if x != nil {
+		return x.Exporter
+	}
+	return Otel_Trace_OTLP_GRPC
+}
+
+func (x *Otel_Trace) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Otel_Trace) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *Otel_Trace) GetUrlPath() string {
+	if x != nil {
+		return x.UrlPath
+	}
+	return ""
+}
+
+func (x *Otel_Trace) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Otel_Trace) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Otel_Trace) GetEnrich() *Otel_Trace_Enrich {
+	if x != nil {
+		return x.Enrich
+	}
+	return nil
+}
+
+func (x *Otel_Trace) GetResponse() *Otel_Trace_Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+type Otel_Metric struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EnableExemplar bool                   `protobuf:"varint,1,opt,name=enable_exemplar,json=enableExemplar,proto3" json:"enable_exemplar,omitempty"`
+	Otlp           *Otel_Metric_OTLP      `protobuf:"bytes,2,opt,name=otlp,proto3" json:"otlp,omitempty"`
+	// stop registering the prometheus reader, when the metrics are only pushed
+	DisablePrometheus bool `protobuf:"varint,3,opt,name=disable_prometheus,json=disablePrometheus,proto3" json:"disable_prometheus,omitempty"`
+	// Go runtime and process metrics
+	Runtime bool `protobuf:"varint,4,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	// label value combinations per business instrument, defaults to 1000
+	CardinalityLimit int32               `protobuf:"varint,5,opt,name=cardinality_limit,json=cardinalityLimit,proto3" json:"cardinality_limit,omitempty"`
+	Server           *Otel_Metric_Server `protobuf:"bytes,6,opt,name=server,proto3" json:"server,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Otel_Metric) Reset() {
+	*x = Otel_Metric{}
+	mi := &file_conf_conf_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Otel_Metric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Otel_Metric) ProtoMessage() {}
+
+func (x *Otel_Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Otel_Metric.ProtoReflect.Descriptor instead.
+func (*Otel_Metric) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 2}
+}
+
+func (x *Otel_Metric) GetEnableExemplar() bool {
+	if x != nil {
+		return x.EnableExemplar
+	}
+	return false
+}
+
+func (x *Otel_Metric) GetOtlp() *Otel_Metric_OTLP {
+	if x != nil {
+		return x.Otlp
+	}
+	return nil
+}
+
+func (x *Otel_Metric) GetDisablePrometheus() bool {
+	if x != nil {
+		return x.DisablePrometheus
+	}
+	return false
+}
+
+func (x *Otel_Metric) GetRuntime() bool {
+	if x != nil {
+		return x.Runtime
+	}
+	return false
+}
+
+func (x *Otel_Metric) GetCardinalityLimit() int32 {
+	if x != nil {
+		return x.CardinalityLimit
+	}
+	return 0
+}
+
+func (x *Otel_Metric) GetServer() *Otel_Metric_Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+// Enrich copies the request metadata and fields onto the server span
+type Otel_Trace_Enrich struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Metadata      []*Otel_Trace_Enrich_Field `protobuf:"bytes,1,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	RequestFields []*Otel_Trace_Enrich_Field `protobuf:"bytes,2,rep,name=request_fields,json=requestFields,proto3" json:"request_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Otel_Trace_Enrich) Reset() {
+	*x = Otel_Trace_Enrich{}
+	mi := &file_conf_conf_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Otel_Trace_Enrich) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Otel_Trace_Enrich) ProtoMessage() {}
+
+func (x *Otel_Trace_Enrich) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Otel_Trace_Enrich.ProtoReflect.Descriptor instead.
+func (*Otel_Trace_Enrich) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 1, 2}
+}
+
+func (x *Otel_Trace_Enrich) GetMetadata() []*Otel_Trace_Enrich_Field {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Otel_Trace_Enrich) GetRequestFields() []*Otel_Trace_Enrich_Field {
+	if x != nil {
+		return x.RequestFields
+	}
+	return nil
+}
+
+// Response is the trace headers of the replies
+type Otel_Trace_Response struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// defaults to X-Trace-Id
+	TraceIdHeader string `protobuf:"bytes,1,opt,name=trace_id_header,json=traceIdHeader,proto3" json:"trace_id_header,omitempty"`
+	// W3C trace context of the server span
+	Traceparent bool `protobuf:"varint,2,opt,name=traceparent,proto3" json:"traceparent,omitempty"`
+	// defaults to traceparent
+	TraceparentHeader string `protobuf:"bytes,3,opt,name=traceparent_header,json=traceparentHeader,proto3" json:"traceparent_header,omitempty"`
+	// duration of the handler, eg: app;dur=12.3
+	ServerTiming bool `protobuf:"varint,4,opt,name=server_timing,json=serverTiming,proto3" json:"server_timing,omitempty"`
+	// defaults to Server-Timing
+	ServerTimingHeader string `protobuf:"bytes,5,opt,name=server_timing_header,json=serverTimingHeader,proto3" json:"server_timing_header,omitempty"`
+	// defaults to app
+	ServerTimingMetric string `protobuf:"bytes,6,opt,name=server_timing_metric,json=serverTimingMetric,proto3" json:"server_timing_metric,omitempty"`
+	// also send the headers as gRPC trailers
+	GrpcTrailer   bool `protobuf:"varint,7,opt,name=grpc_trailer,json=grpcTrailer,proto3" json:"grpc_trailer,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Bootstrap) Reset() {
-	*x = Bootstrap{}
-	mi := &file_conf_conf_proto_msgTypes[0]
+func (x *Otel_Trace_Response) Reset() {
+	*x = Otel_Trace_Response{}
+	mi := &file_conf_conf_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Bootstrap) String() string {
+func (x *Otel_Trace_Response) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Bootstrap) ProtoMessage() {}
+func (*Otel_Trace_Response) ProtoMessage() {}
 
-func (x *Bootstrap) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[0]
+func (x *Otel_Trace_Response) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -113,92 +2896,159 @@ func (x *Bootstrap) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Bootstrap.ProtoReflect.Descriptor instead.
-func (*Bootstrap) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use Otel_Trace_Response.ProtoReflect.Descriptor instead.
+func (*Otel_Trace_Response) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 1, 3}
 }
 
-func (x *Bootstrap) GetEnv() Environment {
+func (x *Otel_Trace_Response) GetTraceIdHeader() string {
 	if x != nil {
-		return x.Env
+		return x.TraceIdHeader
 	}
-	return Environment_NONE
+	return ""
 }
 
-func (x *Bootstrap) GetMetadata() *MetaData {
+func (x *Otel_Trace_Response) GetTraceparent() bool {
 	if x != nil {
-		return x.Metadata
+		return x.Traceparent
 	}
-	return nil
+	return false
 }
 
-func (x *Bootstrap) GetServer() *Server {
+func (x *Otel_Trace_Response) GetTraceparentHeader() string {
 	if x != nil {
-		return x.Server
+		return x.TraceparentHeader
 	}
-	return nil
+	return ""
 }
 
-func (x *Bootstrap) GetRegistry() *Registry {
+func (x *Otel_Trace_Response) GetServerTiming() bool {
 	if x != nil {
-		return x.Registry
+		return x.ServerTiming
 	}
-	return nil
+	return false
 }
 
-func (x *Bootstrap) GetBbr() *BBR {
+func (x *Otel_Trace_Response) GetServerTimingHeader() string {
 	if x != nil {
-		return x.Bbr
+		return x.ServerTimingHeader
 	}
-	return nil
+	return ""
 }
 
-func (x *Bootstrap) GetOtel() *Otel {
+func (x *Otel_Trace_Response) GetServerTimingMetric() string {
 	if x != nil {
-		return x.Otel
+		return x.ServerTimingMetric
 	}
-	return nil
+	return ""
 }
 
-func (x *Bootstrap) GetLog() *Log {
+func (x *Otel_Trace_Response) GetGrpcTrailer() bool {
 	if x != nil {
-		return x.Log
+		return x.GrpcTrailer
 	}
-	return nil
+	return false
 }
 
-func (x *Bootstrap) GetData() *Data {
+type Otel_Trace_Enrich_Field struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// metadata key or request header, eg: x-md-global-user-id, or the dotted request field path, eg: user.name
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// attribute name, defaults to the metadata key without the x-md-global- prefix and with dashes as dots,
+	// or request. and the field path
+	Attribute string `protobuf:"bytes,2,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	// also propagate the value downstream in the baggage
+	Baggage       bool `protobuf:"varint,3,opt,name=baggage,proto3" json:"baggage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Otel_Trace_Enrich_Field) Reset() {
+	*x = Otel_Trace_Enrich_Field{}
+	mi := &file_conf_conf_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Otel_Trace_Enrich_Field) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Otel_Trace_Enrich_Field) ProtoMessage() {}
+
+func (x *Otel_Trace_Enrich_Field) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[36]
 	if x != nil {
-		return x.Data
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type MetaData struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=Version,proto3" json:"Version,omitempty"`
-	ConfPath      string                 `protobuf:"bytes,3,opt,name=ConfPath,proto3" json:"ConfPath,omitempty"`
-	Id            string                 `protobuf:"bytes,4,opt,name=Id,proto3" json:"Id,omitempty"`
+// Deprecated: Use Otel_Trace_Enrich_Field.ProtoReflect.Descriptor instead.
+func (*Otel_Trace_Enrich_Field) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 1, 2, 0}
+}
+
+func (x *Otel_Trace_Enrich_Field) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Otel_Trace_Enrich_Field) GetAttribute() string {
+	if x != nil {
+		return x.Attribute
+	}
+	return ""
+}
+
+func (x *Otel_Trace_Enrich_Field) GetBaggage() bool {
+	if x != nil {
+		return x.Baggage
+	}
+	return false
+}
+
+// OTLP pushes the metrics periodically
+type Otel_Metric_OTLP struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Enable   bool                   `protobuf:"varint,1,opt,name=enable,proto3" json:"enable,omitempty"`
+	Protocol Otel_Metric_Protocol   `protobuf:"varint,2,opt,name=protocol,proto3,enum=kratos.api.Otel_Metric_Protocol" json:"protocol,omitempty"`
+	Endpoint string                 `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Insecure bool                   `protobuf:"varint,4,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	Headers  map[string]string      `protobuf:"bytes,5,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// gzip or none
+	Compression string `protobuf:"bytes,6,opt,name=compression,proto3" json:"compression,omitempty"`
+	// HTTP url path, defaults to /v1/metrics
+	UrlPath string `protobuf:"bytes,7,opt,name=url_path,json=urlPath,proto3" json:"url_path,omitempty"`
+	// defaults to 60s
+	Interval      *durationpb.Duration    `protobuf:"bytes,8,opt,name=interval,proto3" json:"interval,omitempty"`
+	Timeout       *durationpb.Duration    `protobuf:"bytes,9,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Temporality   Otel_Metric_Temporality `protobuf:"varint,10,opt,name=temporality,proto3,enum=kratos.api.Otel_Metric_Temporality" json:"temporality,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MetaData) Reset() {
-	*x = MetaData{}
-	mi := &file_conf_conf_proto_msgTypes[1]
+func (x *Otel_Metric_OTLP) Reset() {
+	*x = Otel_Metric_OTLP{}
+	mi := &file_conf_conf_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MetaData) String() string {
+func (x *Otel_Metric_OTLP) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MetaData) ProtoMessage() {}
+func (*Otel_Metric_OTLP) ProtoMessage() {}
 
-func (x *MetaData) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[1]
+func (x *Otel_Metric_OTLP) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -209,65 +3059,104 @@ func (x *MetaData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MetaData.ProtoReflect.Descriptor instead.
-func (*MetaData) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use Otel_Metric_OTLP.ProtoReflect.Descriptor instead.
+func (*Otel_Metric_OTLP) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 2, 0}
 }
 
-func (x *MetaData) GetName() string {
+func (x *Otel_Metric_OTLP) GetEnable() bool {
 	if x != nil {
-		return x.Name
+		return x.Enable
 	}
-	return ""
+	return false
 }
 
-func (x *MetaData) GetVersion() string {
+func (x *Otel_Metric_OTLP) GetProtocol() Otel_Metric_Protocol {
 	if x != nil {
-		return x.Version
+		return x.Protocol
+	}
+	return Otel_Metric_GRPC
+}
+
+func (x *Otel_Metric_OTLP) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
 	}
 	return ""
 }
 
-func (x *MetaData) GetConfPath() string {
+func (x *Otel_Metric_OTLP) GetInsecure() bool {
 	if x != nil {
-		return x.ConfPath
+		return x.Insecure
+	}
+	return false
+}
+
+func (x *Otel_Metric_OTLP) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Otel_Metric_OTLP) GetCompression() string {
+	if x != nil {
+		return x.Compression
 	}
 	return ""
 }
 
-func (x *MetaData) GetId() string {
+func (x *Otel_Metric_OTLP) GetUrlPath() string {
 	if x != nil {
-		return x.Id
+		return x.UrlPath
 	}
 	return ""
 }
 
-type Log struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Filepath      string                 `protobuf:"bytes,1,opt,name=filepath,proto3" json:"filepath,omitempty"`
-	MaxSize       int32                  `protobuf:"varint,2,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
-	MaxBackups    int32                  `protobuf:"varint,3,opt,name=max_backups,json=maxBackups,proto3" json:"max_backups,omitempty"`
-	Level         int32                  `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`
-	MaxAge        int32                  `protobuf:"varint,5,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+func (x *Otel_Metric_OTLP) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *Otel_Metric_OTLP) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Otel_Metric_OTLP) GetTemporality() Otel_Metric_Temporality {
+	if x != nil {
+		return x.Temporality
+	}
+	return Otel_Metric_CUMULATIVE
+}
+
+type Otel_Metric_Buckets struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// upper bounds in seconds
+	Boundaries    []float64 `protobuf:"fixed64,1,rep,packed,name=boundaries,proto3" json:"boundaries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Log) Reset() {
-	*x = Log{}
-	mi := &file_conf_conf_proto_msgTypes[2]
+func (x *Otel_Metric_Buckets) Reset() {
+	*x = Otel_Metric_Buckets{}
+	mi := &file_conf_conf_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Log) String() string {
+func (x *Otel_Metric_Buckets) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Log) ProtoMessage() {}
+func (*Otel_Metric_Buckets) ProtoMessage() {}
 
-func (x *Log) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[2]
+func (x *Otel_Metric_Buckets) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -278,70 +3167,104 @@ func (x *Log) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Log.ProtoReflect.Descriptor instead.
-func (*Log) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use Otel_Metric_Buckets.ProtoReflect.Descriptor instead.
+func (*Otel_Metric_Buckets) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 2, 1}
 }
 
-func (x *Log) GetFilepath() string {
+func (x *Otel_Metric_Buckets) GetBoundaries() []float64 {
 	if x != nil {
-		return x.Filepath
+		return x.Boundaries
 	}
-	return ""
+	return nil
 }
 
-func (x *Log) GetMaxSize() int32 {
-	if x != nil {
-		return x.MaxSize
-	}
-	return 0
+// Server is the per operation request metrics
+type Otel_Metric_Server struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// duration buckets, defaults to 5ms up to 10s
+	Buckets []float64 `protobuf:"fixed64,1,rep,packed,name=buckets,proto3" json:"buckets,omitempty"`
+	// extra duration boundaries of the SLO thresholds by kratos operation, eg:
+	// /helloworld.v1.Greeter/SayHello, all operations share one histogram
+	// with the union of the boundaries
+	Operations    map[string]*Otel_Metric_Buckets `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Log) GetMaxBackups() int32 {
+func (x *Otel_Metric_Server) Reset() {
+	*x = Otel_Metric_Server{}
+	mi := &file_conf_conf_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Otel_Metric_Server) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Otel_Metric_Server) ProtoMessage() {}
+
+func (x *Otel_Metric_Server) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[39]
 	if x != nil {
-		return x.MaxBackups
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *Log) GetLevel() int32 {
+// Deprecated: Use Otel_Metric_Server.ProtoReflect.Descriptor instead.
+func (*Otel_Metric_Server) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 2, 2}
+}
+
+func (x *Otel_Metric_Server) GetBuckets() []float64 {
 	if x != nil {
-		return x.Level
+		return x.Buckets
 	}
-	return 0
+	return nil
 }
 
-func (x *Log) GetMaxAge() int32 {
+func (x *Otel_Metric_Server) GetOperations() map[string]*Otel_Metric_Buckets {
 	if x != nil {
-		return x.MaxAge
+		return x.Operations
 	}
-	return 0
+	return nil
 }
 
-type Server struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Http          *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
-	Grpc          *Server_GRPC           `protobuf:"bytes,2,opt,name=grpc,proto3" json:"grpc,omitempty"`
-	Pprof         *Server_Pprof          `protobuf:"bytes,3,opt,name=pprof,proto3" json:"pprof,omitempty"`
+type Auth_Key struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// matched against the kid header, optional with a single key
+	Kid string `protobuf:"bytes,1,opt,name=kid,proto3" json:"kid,omitempty"`
+	// HS256, HS384, HS512, RS256, RS384, RS512, ES256, ES384, ES512
+	Alg string `protobuf:"bytes,2,opt,name=alg,proto3" json:"alg,omitempty"`
+	// hmac secret of the HS algorithms
+	Secret string `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	// PEM encoded public key of the RS and ES algorithms
+	PublicKey     string `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Server) Reset() {
-	*x = Server{}
-	mi := &file_conf_conf_proto_msgTypes[3]
+func (x *Auth_Key) Reset() {
+	*x = Auth_Key{}
+	mi := &file_conf_conf_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Server) String() string {
+func (x *Auth_Key) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Server) ProtoMessage() {}
+func (*Auth_Key) ProtoMessage() {}
 
-func (x *Server) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[3]
+func (x *Auth_Key) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -352,54 +3275,66 @@ func (x *Server) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Server.ProtoReflect.Descriptor instead.
-func (*Server) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use Auth_Key.ProtoReflect.Descriptor instead.
+func (*Auth_Key) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 0}
 }
 
-func (x *Server) GetHttp() *Server_HTTP {
+func (x *Auth_Key) GetKid() string {
 	if x != nil {
-		return x.Http
+		return x.Kid
 	}
-	return nil
+	return ""
 }
 
-func (x *Server) GetGrpc() *Server_GRPC {
+func (x *Auth_Key) GetAlg() string {
 	if x != nil {
-		return x.Grpc
+		return x.Alg
 	}
-	return nil
+	return ""
 }
 
-func (x *Server) GetPprof() *Server_Pprof {
+func (x *Auth_Key) GetSecret() string {
 	if x != nil {
-		return x.Pprof
+		return x.Secret
 	}
-	return nil
+	return ""
 }
 
-type Registry struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Endpoint      []string               `protobuf:"bytes,1,rep,name=endpoint,proto3" json:"endpoint,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Auth_Key) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
 }
 
-func (x *Registry) Reset() {
-	*x = Registry{}
-	mi := &file_conf_conf_proto_msgTypes[4]
+type Auth_JWKS struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// either url or file
+	Url  string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	File string `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	// the keys are reloaded after the interval, or on an unknown kid
+	RefreshInterval *durationpb.Duration `protobuf:"bytes,3,opt,name=refresh_interval,json=refreshInterval,proto3" json:"refresh_interval,omitempty"`
+	Timeout         *durationpb.Duration `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Auth_JWKS) Reset() {
+	*x = Auth_JWKS{}
+	mi := &file_conf_conf_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Registry) String() string {
+func (x *Auth_JWKS) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Registry) ProtoMessage() {}
+func (*Auth_JWKS) ProtoMessage() {}
 
-func (x *Registry) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[4]
+func (x *Auth_JWKS) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -410,42 +3345,66 @@ func (x *Registry) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Registry.ProtoReflect.Descriptor instead.
-func (*Registry) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Auth_JWKS.ProtoReflect.Descriptor instead.
+func (*Auth_JWKS) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 1}
 }
 
-func (x *Registry) GetEndpoint() []string {
+func (x *Auth_JWKS) GetUrl() string {
 	if x != nil {
-		return x.Endpoint
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Auth_JWKS) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Auth_JWKS) GetRefreshInterval() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshInterval
 	}
 	return nil
 }
 
-type BBR struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	WindowSize    *durationpb.Duration   `protobuf:"bytes,1,opt,name=window_size,json=windowSize,proto3" json:"window_size,omitempty"`
-	Bucket        int32                  `protobuf:"varint,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
-	CpuThreshold  int64                  `protobuf:"varint,3,opt,name=cpu_threshold,json=cpuThreshold,proto3" json:"cpu_threshold,omitempty"`
+func (x *Auth_JWKS) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type Auth_JWT struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Keys   []*Auth_Key            `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Jwks   *Auth_JWKS             `protobuf:"bytes,2,opt,name=jwks,proto3" json:"jwks,omitempty"`
+	Issuer string                 `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	// the token must contain one of the audiences
+	Audience      []string             `protobuf:"bytes,4,rep,name=audience,proto3" json:"audience,omitempty"`
+	Leeway        *durationpb.Duration `protobuf:"bytes,5,opt,name=leeway,proto3" json:"leeway,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BBR) Reset() {
-	*x = BBR{}
-	mi := &file_conf_conf_proto_msgTypes[5]
+func (x *Auth_JWT) Reset() {
+	*x = Auth_JWT{}
+	mi := &file_conf_conf_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BBR) String() string {
+func (x *Auth_JWT) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BBR) ProtoMessage() {}
+func (*Auth_JWT) ProtoMessage() {}
 
-func (x *BBR) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[5]
+func (x *Auth_JWT) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -456,55 +3415,72 @@ func (x *BBR) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BBR.ProtoReflect.Descriptor instead.
-func (*BBR) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Auth_JWT.ProtoReflect.Descriptor instead.
+func (*Auth_JWT) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 2}
 }
 
-func (x *BBR) GetWindowSize() *durationpb.Duration {
+func (x *Auth_JWT) GetKeys() []*Auth_Key {
 	if x != nil {
-		return x.WindowSize
+		return x.Keys
 	}
 	return nil
 }
 
-func (x *BBR) GetBucket() int32 {
+func (x *Auth_JWT) GetJwks() *Auth_JWKS {
 	if x != nil {
-		return x.Bucket
+		return x.Jwks
 	}
-	return 0
+	return nil
 }
 
-func (x *BBR) GetCpuThreshold() int64 {
+func (x *Auth_JWT) GetIssuer() string {
 	if x != nil {
-		return x.CpuThreshold
+		return x.Issuer
 	}
-	return 0
+	return ""
 }
 
-type Otel struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Trace         *Otel_Trace            `protobuf:"bytes,1,opt,name=trace,proto3" json:"trace,omitempty"`
-	Metric        *Otel_Metric           `protobuf:"bytes,2,opt,name=metric,proto3" json:"metric,omitempty"`
+func (x *Auth_JWT) GetAudience() []string {
+	if x != nil {
+		return x.Audience
+	}
+	return nil
+}
+
+func (x *Auth_JWT) GetLeeway() *durationpb.Duration {
+	if x != nil {
+		return x.Leeway
+	}
+	return nil
+}
+
+type Auth_Policy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// kratos operation, eg: /helloworld.v1.Greeter/SayHello, a trailing * matches a prefix
+	Operation string      `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Access    Auth_Access `protobuf:"varint,2,opt,name=access,proto3,enum=kratos.api.Auth_Access" json:"access,omitempty"`
+	// all scopes are required
+	Scopes        []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Otel) Reset() {
-	*x = Otel{}
-	mi := &file_conf_conf_proto_msgTypes[6]
+func (x *Auth_Policy) Reset() {
+	*x = Auth_Policy{}
+	mi := &file_conf_conf_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Otel) String() string {
+func (x *Auth_Policy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Otel) ProtoMessage() {}
+func (*Auth_Policy) ProtoMessage() {}
 
-func (x *Otel) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[6]
+func (x *Auth_Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -515,49 +3491,58 @@ func (x *Otel) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Otel.ProtoReflect.Descriptor instead.
-func (*Otel) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use Auth_Policy.ProtoReflect.Descriptor instead.
+func (*Auth_Policy) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 3}
 }
 
-func (x *Otel) GetTrace() *Otel_Trace {
+func (x *Auth_Policy) GetOperation() string {
 	if x != nil {
-		return x.Trace
+		return x.Operation
 	}
-	return nil
+	return ""
 }
 
-func (x *Otel) GetMetric() *Otel_Metric {
+func (x *Auth_Policy) GetAccess() Auth_Access {
 	if x != nil {
-		return x.Metric
+		return x.Access
+	}
+	return Auth_AUTHENTICATED
+}
+
+func (x *Auth_Policy) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
 	}
 	return nil
 }
 
-type Data struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Database      map[string]*Data_Database `protobuf:"bytes,1,rep,name=database,proto3" json:"database,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Redis         map[string]*Data_Redis    `protobuf:"bytes,2,rep,name=redis,proto3" json:"redis,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Kafka         *Data_Kafka               `protobuf:"bytes,3,opt,name=kafka,proto3" json:"kafka,omitempty"`
+type Auth_APIKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// identity of the caller, the subject becomes apikey:{id}
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// hex encoded sha256 of the key
+	Hash          string   `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Scopes        []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Data) Reset() {
-	*x = Data{}
-	mi := &file_conf_conf_proto_msgTypes[7]
+func (x *Auth_APIKey) Reset() {
+	*x = Auth_APIKey{}
+	mi := &file_conf_conf_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Data) String() string {
+func (x *Auth_APIKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Data) ProtoMessage() {}
+func (*Auth_APIKey) ProtoMessage() {}
 
-func (x *Data) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[7]
+func (x *Auth_APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -568,56 +3553,60 @@ func (x *Data) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Data.ProtoReflect.Descriptor instead.
-func (*Data) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use Auth_APIKey.ProtoReflect.Descriptor instead.
+func (*Auth_APIKey) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 4}
 }
 
-func (x *Data) GetDatabase() map[string]*Data_Database {
+func (x *Auth_APIKey) GetId() string {
 	if x != nil {
-		return x.Database
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *Data) GetRedis() map[string]*Data_Redis {
+func (x *Auth_APIKey) GetHash() string {
 	if x != nil {
-		return x.Redis
+		return x.Hash
 	}
-	return nil
+	return ""
 }
 
-func (x *Data) GetKafka() *Data_Kafka {
+func (x *Auth_APIKey) GetScopes() []string {
 	if x != nil {
-		return x.Kafka
+		return x.Scopes
 	}
 	return nil
 }
 
-type Server_HTTP struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
-	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
-	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+// APIKeys authenticates the callers with a static key header.
+type Auth_APIKeys struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// defaults to X-Api-Key
+	Header string         `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Keys   []*Auth_APIKey `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	// optional table with the columns id, key_hash, scopes (space separated) and revoked
+	DbAlias       string `protobuf:"bytes,3,opt,name=db_alias,json=dbAlias,proto3" json:"db_alias,omitempty"`
+	DbTable       string `protobuf:"bytes,4,opt,name=db_table,json=dbTable,proto3" json:"db_table,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Server_HTTP) Reset() {
-	*x = Server_HTTP{}
-	mi := &file_conf_conf_proto_msgTypes[8]
+func (x *Auth_APIKeys) Reset() {
+	*x = Auth_APIKeys{}
+	mi := &file_conf_conf_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Server_HTTP) String() string {
+func (x *Auth_APIKeys) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Server_HTTP) ProtoMessage() {}
+func (*Auth_APIKeys) ProtoMessage() {}
 
-func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[8]
+func (x *Auth_APIKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -628,56 +3617,64 @@ func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Server_HTTP.ProtoReflect.Descriptor instead.
-func (*Server_HTTP) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{3, 0}
+// Deprecated: Use Auth_APIKeys.ProtoReflect.Descriptor instead.
+func (*Auth_APIKeys) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 5}
 }
 
-func (x *Server_HTTP) GetNetwork() string {
+func (x *Auth_APIKeys) GetHeader() string {
 	if x != nil {
-		return x.Network
+		return x.Header
 	}
 	return ""
 }
 
-func (x *Server_HTTP) GetAddr() string {
+func (x *Auth_APIKeys) GetKeys() []*Auth_APIKey {
 	if x != nil {
-		return x.Addr
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *Auth_APIKeys) GetDbAlias() string {
+	if x != nil {
+		return x.DbAlias
 	}
 	return ""
 }
 
-func (x *Server_HTTP) GetTimeout() *durationpb.Duration {
+func (x *Auth_APIKeys) GetDbTable() string {
 	if x != nil {
-		return x.Timeout
+		return x.DbTable
 	}
-	return nil
+	return ""
 }
 
-type Server_GRPC struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
-	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
-	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+type Auth_HMACClient struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// identity of the caller, the subject becomes hmac:{key_id}
+	KeyId         string   `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Secret        string   `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	Scopes        []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Server_GRPC) Reset() {
-	*x = Server_GRPC{}
-	mi := &file_conf_conf_proto_msgTypes[9]
+func (x *Auth_HMACClient) Reset() {
+	*x = Auth_HMACClient{}
+	mi := &file_conf_conf_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Server_GRPC) String() string {
+func (x *Auth_HMACClient) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Server_GRPC) ProtoMessage() {}
+func (*Auth_HMACClient) ProtoMessage() {}
 
-func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[9]
+func (x *Auth_HMACClient) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -688,54 +3685,60 @@ func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Server_GRPC.ProtoReflect.Descriptor instead.
-func (*Server_GRPC) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{3, 1}
+// Deprecated: Use Auth_HMACClient.ProtoReflect.Descriptor instead.
+func (*Auth_HMACClient) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 6}
 }
 
-func (x *Server_GRPC) GetNetwork() string {
+func (x *Auth_HMACClient) GetKeyId() string {
 	if x != nil {
-		return x.Network
+		return x.KeyId
 	}
 	return ""
 }
 
-func (x *Server_GRPC) GetAddr() string {
+func (x *Auth_HMACClient) GetSecret() string {
 	if x != nil {
-		return x.Addr
+		return x.Secret
 	}
 	return ""
 }
 
-func (x *Server_GRPC) GetTimeout() *durationpb.Duration {
+func (x *Auth_HMACClient) GetScopes() []string {
 	if x != nil {
-		return x.Timeout
+		return x.Scopes
 	}
 	return nil
 }
 
-type Server_Pprof struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Addr          string                 `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+// HMAC authenticates the http requests signed with pkg/signature.
+type Auth_HMAC struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Clients []*Auth_HMACClient     `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	// max clock skew of the timestamp, defaults to 5m
+	MaxSkew *durationpb.Duration `protobuf:"bytes,2,opt,name=max_skew,json=maxSkew,proto3" json:"max_skew,omitempty"`
+	// redis used to reject the replayed nonces
+	RedisAlias    string `protobuf:"bytes,3,opt,name=redis_alias,json=redisAlias,proto3" json:"redis_alias,omitempty"`
+	RedisShard    int32  `protobuf:"varint,4,opt,name=redis_shard,json=redisShard,proto3" json:"redis_shard,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Server_Pprof) Reset() {
-	*x = Server_Pprof{}
-	mi := &file_conf_conf_proto_msgTypes[10]
+func (x *Auth_HMAC) Reset() {
+	*x = Auth_HMAC{}
+	mi := &file_conf_conf_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Server_Pprof) String() string {
+func (x *Auth_HMAC) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Server_Pprof) ProtoMessage() {}
+func (*Auth_HMAC) ProtoMessage() {}
 
-func (x *Server_Pprof) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[10]
+func (x *Auth_HMAC) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -746,41 +3749,67 @@ func (x *Server_Pprof) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Server_Pprof.ProtoReflect.Descriptor instead.
-func (*Server_Pprof) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{3, 2}
+// Deprecated: Use Auth_HMAC.ProtoReflect.Descriptor instead.
+func (*Auth_HMAC) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8, 7}
 }
 
-func (x *Server_Pprof) GetAddr() string {
+func (x *Auth_HMAC) GetClients() []*Auth_HMACClient {
 	if x != nil {
-		return x.Addr
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *Auth_HMAC) GetMaxSkew() *durationpb.Duration {
+	if x != nil {
+		return x.MaxSkew
+	}
+	return nil
+}
+
+func (x *Auth_HMAC) GetRedisAlias() string {
+	if x != nil {
+		return x.RedisAlias
 	}
 	return ""
 }
 
-type Otel_Trace struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
-	Insecure      bool                   `protobuf:"varint,2,opt,name=insecure,proto3" json:"insecure,omitempty"`
+func (x *Auth_HMAC) GetRedisShard() int32 {
+	if x != nil {
+		return x.RedisShard
+	}
+	return 0
+}
+
+// Condition compares a request field with a value or a claim.
+type Authz_Condition struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// request field path, eg: name, user.id
+	Field  string         `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Op     Authz_Operator `protobuf:"varint,2,opt,name=op,proto3,enum=kratos.api.Authz_Operator" json:"op,omitempty"`
+	Values []string       `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+	// compare with the claim instead of values: sub, iss
+	Claim         string `protobuf:"bytes,4,opt,name=claim,proto3" json:"claim,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Otel_Trace) Reset() {
-	*x = Otel_Trace{}
-	mi := &file_conf_conf_proto_msgTypes[11]
+func (x *Authz_Condition) Reset() {
+	*x = Authz_Condition{}
+	mi := &file_conf_conf_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Otel_Trace) String() string {
+func (x *Authz_Condition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Otel_Trace) ProtoMessage() {}
+func (*Authz_Condition) ProtoMessage() {}
 
-func (x *Otel_Trace) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[11]
+func (x *Authz_Condition) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -791,47 +3820,68 @@ func (x *Otel_Trace) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Otel_Trace.ProtoReflect.Descriptor instead.
-func (*Otel_Trace) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{6, 0}
+// Deprecated: Use Authz_Condition.ProtoReflect.Descriptor instead.
+func (*Authz_Condition) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 0}
 }
 
-func (x *Otel_Trace) GetEndpoint() string {
+func (x *Authz_Condition) GetField() string {
 	if x != nil {
-		return x.Endpoint
+		return x.Field
 	}
 	return ""
 }
 
-func (x *Otel_Trace) GetInsecure() bool {
+func (x *Authz_Condition) GetOp() Authz_Operator {
 	if x != nil {
-		return x.Insecure
+		return x.Op
 	}
-	return false
+	return Authz_EQ
 }
 
-type Otel_Metric struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	EnableExemplar bool                   `protobuf:"varint,1,opt,name=enable_exemplar,json=enableExemplar,proto3" json:"enable_exemplar,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *Authz_Condition) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
 }
 
-func (x *Otel_Metric) Reset() {
-	*x = Otel_Metric{}
-	mi := &file_conf_conf_proto_msgTypes[12]
+func (x *Authz_Condition) GetClaim() string {
+	if x != nil {
+		return x.Claim
+	}
+	return ""
+}
+
+type Authz_Policy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// kratos operation, eg: /helloworld.v1.Greeter/SayHello, a trailing * matches a prefix
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// any of the roles is required
+	Roles []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	// all permissions are required
+	Permissions []string `protobuf:"bytes,3,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	// all conditions must hold
+	Conditions    []*Authz_Condition `protobuf:"bytes,4,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Authz_Policy) Reset() {
+	*x = Authz_Policy{}
+	mi := &file_conf_conf_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Otel_Metric) String() string {
+func (x *Authz_Policy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Otel_Metric) ProtoMessage() {}
+func (*Authz_Policy) ProtoMessage() {}
 
-func (x *Otel_Metric) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[12]
+func (x *Authz_Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -842,32 +3892,62 @@ func (x *Otel_Metric) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Otel_Metric.ProtoReflect.Descriptor instead.
-func (*Otel_Metric) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{6, 1}
+// Deprecated: Use Authz_Policy.ProtoReflect.Descriptor instead.
+func (*Authz_Policy) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 1}
 }
 
-func (x *Otel_Metric) GetEnableExemplar() bool {
+func (x *Authz_Policy) GetOperation() string {
 	if x != nil {
-		return x.EnableExemplar
+		return x.Operation
 	}
-	return false
+	return ""
+}
+
+func (x *Authz_Policy) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *Authz_Policy) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *Authz_Policy) GetConditions() []*Authz_Condition {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
 }
 
 type Data_Database struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Driver          string                 `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
-	Source          string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
-	MaxOpenConn     int32                  `protobuf:"varint,3,opt,name=max_open_conn,json=maxOpenConn,proto3" json:"max_open_conn,omitempty"`
-	MaxIdleConn     int32                  `protobuf:"varint,4,opt,name=max_idle_conn,json=maxIdleConn,proto3" json:"max_idle_conn,omitempty"`
-	ConnMaxLifetime *durationpb.Duration   `protobuf:"bytes,5,opt,name=conn_max_lifetime,json=connMaxLifetime,proto3" json:"conn_max_lifetime,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// mysql or postgresql
+	Driver string `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
+	// the dsn, eg: root:${env:MYSQL_PASSWORD}@tcp(mysql:3306)/db
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// defaults to 20
+	MaxOpenConn int32 `protobuf:"varint,3,opt,name=max_open_conn,json=maxOpenConn,proto3" json:"max_open_conn,omitempty"`
+	// defaults to 10
+	MaxIdleConn     int32                `protobuf:"varint,4,opt,name=max_idle_conn,json=maxIdleConn,proto3" json:"max_idle_conn,omitempty"`
+	ConnMaxLifetime *durationpb.Duration `protobuf:"bytes,5,opt,name=conn_max_lifetime,json=connMaxLifetime,proto3" json:"conn_max_lifetime,omitempty"`
+	// queries slower than the threshold are logged, defaults to 200ms, 0s disables
+	SlowThreshold *durationpb.Duration `protobuf:"bytes,6,opt,name=slow_threshold,json=slowThreshold,proto3" json:"slow_threshold,omitempty"`
+	// query duration buckets in seconds, the histogram is shared by the
+	// aliases with the union of their buckets
+	Buckets       []float64 `protobuf:"fixed64,7,rep,packed,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Data_Database) Reset() {
 	*x = Data_Database{}
-	mi := &file_conf_conf_proto_msgTypes[13]
+	mi := &file_conf_conf_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -879,7 +3959,7 @@ func (x *Data_Database) String() string {
 func (*Data_Database) ProtoMessage() {}
 
 func (x *Data_Database) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[13]
+	mi := &file_conf_conf_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -892,7 +3972,7 @@ func (x *Data_Database) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Data_Database.ProtoReflect.Descriptor instead.
 func (*Data_Database) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{7, 0}
+	return file_conf_conf_proto_rawDescGZIP(), []int{13, 0}
 }
 
 func (x *Data_Database) GetDriver() string {
@@ -930,20 +4010,35 @@ func (x *Data_Database) GetConnMaxLifetime() *durationpb.Duration {
 	return nil
 }
 
+func (x *Data_Database) GetSlowThreshold() *durationpb.Duration {
+	if x != nil {
+		return x.SlowThreshold
+	}
+	return nil
+}
+
+func (x *Data_Database) GetBuckets() []float64 {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
 type Data_Redis struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Addr          string                 `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	ReadTimeout   *durationpb.Duration   `protobuf:"bytes,3,opt,name=read_timeout,json=readTimeout,proto3" json:"read_timeout,omitempty"`
-	WriteTimeout  *durationpb.Duration   `protobuf:"bytes,4,opt,name=write_timeout,json=writeTimeout,proto3" json:"write_timeout,omitempty"`
-	Shards        []int32                `protobuf:"varint,5,rep,packed,name=shards,proto3" json:"shards,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Addr         string                 `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	Password     string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	ReadTimeout  *durationpb.Duration   `protobuf:"bytes,3,opt,name=read_timeout,json=readTimeout,proto3" json:"read_timeout,omitempty"`
+	WriteTimeout *durationpb.Duration   `protobuf:"bytes,4,opt,name=write_timeout,json=writeTimeout,proto3" json:"write_timeout,omitempty"`
+	// the redis databases, one client each
+	Shards        []int32 `protobuf:"varint,5,rep,packed,name=shards,proto3" json:"shards,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Data_Redis) Reset() {
 	*x = Data_Redis{}
-	mi := &file_conf_conf_proto_msgTypes[14]
+	mi := &file_conf_conf_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -955,7 +4050,7 @@ func (x *Data_Redis) String() string {
 func (*Data_Redis) ProtoMessage() {}
 
 func (x *Data_Redis) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[14]
+	mi := &file_conf_conf_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -968,7 +4063,7 @@ func (x *Data_Redis) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Data_Redis.ProtoReflect.Descriptor instead.
 func (*Data_Redis) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{7, 1}
+	return file_conf_conf_proto_rawDescGZIP(), []int{13, 1}
 }
 
 func (x *Data_Redis) GetAddr() string {
@@ -1016,7 +4111,7 @@ type Data_Kafka struct {
 
 func (x *Data_Kafka) Reset() {
 	*x = Data_Kafka{}
-	mi := &file_conf_conf_proto_msgTypes[15]
+	mi := &file_conf_conf_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1028,7 +4123,7 @@ func (x *Data_Kafka) String() string {
 func (*Data_Kafka) ProtoMessage() {}
 
 func (x *Data_Kafka) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[15]
+	mi := &file_conf_conf_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1041,7 +4136,7 @@ func (x *Data_Kafka) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Data_Kafka.ProtoReflect.Descriptor instead.
 func (*Data_Kafka) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{7, 2}
+	return file_conf_conf_proto_rawDescGZIP(), []int{13, 2}
 }
 
 func (x *Data_Kafka) GetBrokerList() []string {
@@ -1058,80 +4153,424 @@ func (x *Data_Kafka) GetGroupId() string {
 	return ""
 }
 
+var file_conf_conf_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50200,
+		Name:          "kratos.api.secret",
+		Tag:           "varint,50200,opt,name=secret",
+		Filename:      "conf/conf.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// Secret fields are redacted wherever the config is logged or dumped, eg:
+	// string password = 2 [(kratos.api.secret) = true];
+	//
+	// optional bool secret = 50200;
+	E_Secret = &file_conf_conf_proto_extTypes[0]
+)
+
 var File_conf_conf_proto protoreflect.FileDescriptor
 
 const file_conf_conf_proto_rawDesc = "" +
 	"\n" +
 	"\x0fconf/conf.proto\x12\n" +
-	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"\xd8\x02\n" +
+	"kratos.api\x1a\x1bbuf/validate/validate.proto\x1a google/protobuf/descriptor.proto\x1a\x1egoogle/protobuf/duration.proto\"\xf5\x05\n" +
 	"\tBootstrap\x12)\n" +
 	"\x03env\x18\x01 \x01(\x0e2\x17.kratos.api.EnvironmentR\x03env\x120\n" +
 	"\bmetadata\x18\x02 \x01(\v2\x14.kratos.api.MetaDataR\bmetadata\x12*\n" +
-	"\x06server\x18\x03 \x01(\v2\x12.kratos.api.ServerR\x06server\x120\n" +
-	"\bregistry\x18\x04 \x01(\v2\x14.kratos.api.RegistryR\bregistry\x12!\n" +
+	"\x06server\x18\x03 \x01(\v2\x12.kratos.api.ServerR\x06server\x128\n" +
+	"\bregistry\x18\x04 \x01(\v2\x14.kratos.api.RegistryB\x06\xbaH\x03\xc8\x01\x01R\bregistry\x12!\n" +
 	"\x03bbr\x18\x05 \x01(\v2\x0f.kratos.api.BBRR\x03bbr\x12$\n" +
 	"\x04otel\x18\x06 \x01(\v2\x10.kratos.api.OtelR\x04otel\x12!\n" +
 	"\x03log\x18\a \x01(\v2\x0f.kratos.api.LogR\x03log\x12$\n" +
-	"\x04data\x18\b \x01(\v2\x10.kratos.api.DataR\x04data\"d\n" +
+	"\x04data\x18\b \x01(\v2\x10.kratos.api.DataR\x04data\x12$\n" +
+	"\x04auth\x18\t \x01(\v2\x10.kratos.api.AuthR\x04auth\x12'\n" +
+	"\x05authz\x18\n" +
+	" \x01(\v2\x11.kratos.api.AuthzR\x05authz\x129\n" +
+	"\vidempotency\x18\v \x01(\v2\x17.kratos.api.IdempotencyR\vidempotency\x12'\n" +
+	"\x05audit\x18\f \x01(\v2\x11.kratos.api.AuditR\x05audit\x12*\n" +
+	"\x06tenant\x18\r \x01(\v2\x12.kratos.api.TenantR\x06tenant\x12?\n" +
+	"\bfeatures\x18\x0e \x03(\v2#.kratos.api.Bootstrap.FeaturesEntryR\bfeatures\x126\n" +
+	"\vrate_limits\x18\x0f \x03(\v2\x15.kratos.api.RateLimitR\n" +
+	"rateLimits\x1a;\n" +
+	"\rFeaturesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01\"\xb8\x01\n" +
+	"\tRateLimit\x12%\n" +
+	"\toperation\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\toperation\x12\"\n" +
+	"\x04rate\x18\x02 \x01(\x01B\x0e\xbaH\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\x04rate\x12\x1d\n" +
+	"\x05burst\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x05burst\x12A\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationB\f\xbaH\t\xaa\x01\x06\"\x02\b<2\x00R\atimeout\"d\n" +
 	"\bMetaData\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x18\n" +
 	"\aVersion\x18\x02 \x01(\tR\aVersion\x12\x1a\n" +
 	"\bConfPath\x18\x03 \x01(\tR\bConfPath\x12\x0e\n" +
-	"\x02Id\x18\x04 \x01(\tR\x02Id\"\x8c\x01\n" +
+	"\x02Id\x18\x04 \x01(\tR\x02Id\"\xa7\x06\n" +
 	"\x03Log\x12\x1a\n" +
-	"\bfilepath\x18\x01 \x01(\tR\bfilepath\x12\x19\n" +
-	"\bmax_size\x18\x02 \x01(\x05R\amaxSize\x12\x1f\n" +
-	"\vmax_backups\x18\x03 \x01(\x05R\n" +
-	"maxBackups\x12\x14\n" +
-	"\x05level\x18\x04 \x01(\x05R\x05level\x12\x17\n" +
-	"\amax_age\x18\x05 \x01(\x05R\x06maxAge\"\x85\x03\n" +
+	"\bfilepath\x18\x01 \x01(\tR\bfilepath\x12\"\n" +
+	"\bmax_size\x18\x02 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\amaxSize\x12(\n" +
+	"\vmax_backups\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\n" +
+	"maxBackups\x12(\n" +
+	"\x05level\x18\x04 \x01(\x05B\x12\xbaH\x0f\x1a\r\x18\x05(\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01R\x05level\x12 \n" +
+	"\amax_age\x18\x05 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x06maxAge\x12(\n" +
+	"\x04otlp\x18\x06 \x01(\v2\x14.kratos.api.Log.OTLPR\x04otlp\x1a\xbf\x04\n" +
+	"\x04OTLP\x12\x16\n" +
+	"\x06enable\x18\x01 \x01(\bR\x06enable\x129\n" +
+	"\bprotocol\x18\x02 \x01(\x0e2\x1d.kratos.api.Log.OTLP.ProtocolR\bprotocol\x12\x1a\n" +
+	"\bendpoint\x18\x03 \x01(\tR\bendpoint\x12\x1a\n" +
+	"\binsecure\x18\x04 \x01(\bR\binsecure\x12A\n" +
+	"\aheaders\x18\x05 \x03(\v2!.kratos.api.Log.OTLP.HeadersEntryB\x04\xc0\xc1\x18\x01R\aheaders\x12 \n" +
+	"\vcompression\x18\x06 \x01(\tR\vcompression\x12\x19\n" +
+	"\burl_path\x18\a \x01(\tR\aurlPath\x123\n" +
+	"\atimeout\x18\b \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12$\n" +
+	"\x0emax_queue_size\x18\t \x01(\x05R\fmaxQueueSize\x12B\n" +
+	"\x0fexport_interval\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\x0eexportInterval\x121\n" +
+	"\x15export_max_batch_size\x18\v \x01(\x05R\x12exportMaxBatchSize\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1e\n" +
+	"\bProtocol\x12\b\n" +
+	"\x04GRPC\x10\x00\x12\b\n" +
+	"\x04HTTP\x10\x01\"\xe4\x15\n" +
 	"\x06Server\x12+\n" +
 	"\x04http\x18\x01 \x01(\v2\x17.kratos.api.Server.HTTPR\x04http\x12+\n" +
 	"\x04grpc\x18\x02 \x01(\v2\x17.kratos.api.Server.GRPCR\x04grpc\x12.\n" +
-	"\x05pprof\x18\x03 \x01(\v2\x18.kratos.api.Server.PprofR\x05pprof\x1ai\n" +
-	"\x04HTTP\x12\x18\n" +
-	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
-	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x1ai\n" +
-	"\x04GRPC\x12\x18\n" +
-	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
-	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x1a\x1b\n" +
+	"\x05pprof\x18\x03 \x01(\v2\x18.kratos.api.Server.PprofR\x05pprof\x1a\xdf\n" +
+	"\n" +
+	"\x04HTTP\x128\n" +
+	"\anetwork\x18\x01 \x01(\tB\x1e\xbaH\x1br\x19R\x00R\x03tcpR\x04tcp4R\x04tcp6R\x04unixR\anetwork\x12\x1b\n" +
+	"\x04addr\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x04addr\x12B\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationB\r\xbaH\n" +
+	"\xaa\x01\a\"\x03\b\xac\x022\x00R\atimeout\x12<\n" +
+	"\benvelope\x18\x04 \x01(\x0e2 .kratos.api.Server.HTTP.EnvelopeR\benvelope\x120\n" +
+	"\x04cors\x18\x05 \x01(\v2\x1c.kratos.api.Server.HTTP.CORSR\x04cors\x12R\n" +
+	"\x10security_headers\x18\x06 \x01(\v2'.kratos.api.Server.HTTP.SecurityHeadersR\x0fsecurityHeaders\x12+\n" +
+	"\rmax_body_size\x18\a \x01(\x03B\a\xbaH\x04\"\x02(\x00R\vmaxBodySize\x12j\n" +
+	"\x13route_max_body_size\x18\b \x03(\v2-.kratos.api.Server.HTTP.RouteMaxBodySizeEntryB\f\xbaH\t\x9a\x01\x06*\x04\"\x02(\x00R\x10routeMaxBodySize\x1a\xa5\x03\n" +
+	"\x04CORS\x12\x16\n" +
+	"\x06enable\x18\x01 \x01(\bR\x06enable\x12#\n" +
+	"\rallow_origins\x18\x02 \x03(\tR\fallowOrigins\x12#\n" +
+	"\rallow_methods\x18\x03 \x03(\tR\fallowMethods\x12#\n" +
+	"\rallow_headers\x18\x04 \x03(\tR\fallowHeaders\x12%\n" +
+	"\x0eexpose_headers\x18\x05 \x03(\tR\rexposeHeaders\x12+\n" +
+	"\x11allow_credentials\x18\x06 \x01(\bR\x10allowCredentials\x122\n" +
+	"\amax_age\x18\a \x01(\v2\x19.google.protobuf.DurationR\x06maxAge:\x8d\x01\xbaH\x89\x01\x1a\x86\x01\n" +
+	"\x10cors_credentials\x129allow_origins \"*\" must not be used with allow_credentials\x1a7!this.allow_credentials || !('*' in this.allow_origins)\x1a\xc7\x02\n" +
+	"\x0fSecurityHeaders\x12\x16\n" +
+	"\x06enable\x18\x01 \x01(\bR\x06enable\x12;\n" +
+	"\fhsts_max_age\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"hstsMaxAge\x126\n" +
+	"\x17hsts_include_subdomains\x18\x03 \x01(\bR\x15hstsIncludeSubdomains\x12!\n" +
+	"\fhsts_preload\x18\x04 \x01(\bR\vhstsPreload\x12#\n" +
+	"\rframe_options\x18\x05 \x01(\tR\fframeOptions\x12'\n" +
+	"\x0freferrer_policy\x18\x06 \x01(\tR\x0ereferrerPolicy\x126\n" +
+	"\x17content_security_policy\x18\a \x01(\tR\x15contentSecurityPolicy\x1aC\n" +
+	"\x15RouteMaxBodySizeEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"(\n" +
+	"\bEnvelope\x12\b\n" +
+	"\x04NONE\x10\x00\x12\t\n" +
+	"\x05ERROR\x10\x01\x12\a\n" +
+	"\x03ALL\x10\x02\x1a\xa1\x01\n" +
+	"\x04GRPC\x128\n" +
+	"\anetwork\x18\x01 \x01(\tB\x1e\xbaH\x1br\x19R\x00R\x03tcpR\x04tcp4R\x04tcp6R\x04unixR\anetwork\x12\x1b\n" +
+	"\x04addr\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x04addr\x12B\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationB\r\xbaH\n" +
+	"\xaa\x01\a\"\x03\b\xac\x022\x00R\atimeout\x1a\xc9\b\n" +
 	"\x05Pprof\x12\x12\n" +
-	"\x04addr\x18\x01 \x01(\tR\x04addr\"&\n" +
-	"\bRegistry\x12\x1a\n" +
-	"\bendpoint\x18\x01 \x03(\tR\bendpoint\"~\n" +
-	"\x03BBR\x12:\n" +
-	"\vwindow_size\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\n" +
-	"windowSize\x12\x16\n" +
-	"\x06bucket\x18\x02 \x01(\x05R\x06bucket\x12#\n" +
-	"\rcpu_threshold\x18\x03 \x01(\x03R\fcpuThreshold\"\xd9\x01\n" +
+	"\x04addr\x18\x01 \x01(\tR\x04addr\x12=\n" +
+	"\bwatchdog\x18\x02 \x01(\v2!.kratos.api.Server.Pprof.WatchdogR\bwatchdog\x121\n" +
+	"\x04push\x18\x03 \x01(\v2\x1d.kratos.api.Server.Pprof.PushR\x04push\x1a\xb0\x03\n" +
+	"\bWatchdog\x12\x16\n" +
+	"\x06enable\x18\x01 \x01(\bR\x06enable\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12#\n" +
+	"\rcpu_threshold\x18\x03 \x01(\x01R\fcpuThreshold\x12%\n" +
+	"\x0eheap_threshold\x18\x04 \x01(\x04R\rheapThreshold\x12/\n" +
+	"\x13goroutine_threshold\x18\x05 \x01(\x03R\x12goroutineThreshold\x12\x10\n" +
+	"\x03dir\x18\x06 \x01(\tR\x03dir\x12\x1b\n" +
+	"\tmax_files\x18\a \x01(\x05R\bmaxFiles\x125\n" +
+	"\bcooldown\x18\b \x01(\v2\x19.google.protobuf.DurationR\bcooldown\x12<\n" +
+	"\fcpu_duration\x18\t \x01(\v2\x19.google.protobuf.DurationR\vcpuDuration\x124\n" +
+	"\x16mutex_profile_fraction\x18\n" +
+	" \x01(\x05R\x14mutexProfileFraction\x1a\x86\x04\n" +
+	"\x04Push\x12\x16\n" +
+	"\x06enable\x18\x01 \x01(\bR\x06enable\x12\x1a\n" +
+	"\bendpoint\x18\x02 \x01(\tR\bendpoint\x125\n" +
+	"\binterval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\binterval\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x1a\n" +
+	"\bprofiles\x18\x05 \x03(\tR\bprofiles\x12A\n" +
+	"\x06labels\x18\x06 \x03(\v2).kratos.api.Server.Pprof.Push.LabelsEntryR\x06labels\x12J\n" +
+	"\aheaders\x18\a \x03(\v2*.kratos.api.Server.Pprof.Push.HeadersEntryB\x04\xc0\xc1\x18\x01R\aheaders\x12<\n" +
+	"\fcpu_duration\x18\b \x01(\v2\x19.google.protobuf.DurationR\vcpuDuration\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"6\n" +
+	"\bRegistry\x12*\n" +
+	"\bendpoint\x18\x01 \x03(\tB\x0e\xbaH\v\x92\x01\b\b\x01\"\x04r\x02\x10\x01R\bendpoint\"\xa9\x01\n" +
+	"\x03BBR\x12P\n" +
+	"\vwindow_size\x18\x01 \x01(\v2\x19.google.protobuf.DurationB\x14\xbaH\x11\xc8\x01\x01\xaa\x01\v\"\x02\b<2\x05\x10\x80\xc2\xd7/R\n" +
+	"windowSize\x12\x1f\n" +
+	"\x06bucket\x18\x02 \x01(\x05B\a\xbaH\x04\x1a\x02 \x00R\x06bucket\x12/\n" +
+	"\rcpu_threshold\x18\x03 \x01(\x03B\n" +
+	"\xbaH\a\"\x05\x18\xe8\a \x00R\fcpuThreshold\"\xc0\x19\n" +
 	"\x04Otel\x12,\n" +
 	"\x05trace\x18\x01 \x01(\v2\x16.kratos.api.Otel.TraceR\x05trace\x12/\n" +
-	"\x06metric\x18\x02 \x01(\v2\x17.kratos.api.Otel.MetricR\x06metric\x1a?\n" +
+	"\x06metric\x18\x02 \x01(\v2\x17.kratos.api.Otel.MetricR\x06metric\x12Y\n" +
+	"\x13resource_attributes\x18\x03 \x03(\v2(.kratos.api.Otel.ResourceAttributesEntryR\x12resourceAttributes\x1a\xe6\x03\n" +
+	"\aSampler\x12;\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1d.kratos.api.Otel.Sampler.TypeB\b\xbaH\x05\x82\x01\x02\x10\x01R\x04type\x12-\n" +
+	"\x05ratio\x18\x02 \x01(\x01B\x17\xbaH\x14\x12\x12\x19\x00\x00\x00\x00\x00\x00\xf0?)\x00\x00\x00\x00\x00\x00\x00\x00R\x05ratio\x12-\n" +
+	"\n" +
+	"rate_limit\x18\x03 \x01(\x01B\x0e\xbaH\v\x12\t)\x00\x00\x00\x00\x00\x00\x00\x00R\trateLimit\x12#\n" +
+	"\rignore_parent\x18\x04 \x01(\bR\fignoreParent\x12f\n" +
+	"\n" +
+	"operations\x18\x05 \x03(\v2(.kratos.api.Otel.Sampler.OperationsEntryB\x1c\xbaH\x19\x9a\x01\x16*\x14\x12\x12\x19\x00\x00\x00\x00\x00\x00\xf0?)\x00\x00\x00\x00\x00\x00\x00\x00R\n" +
+	"operations\x120\n" +
+	"\x14always_sample_errors\x18\x06 \x01(\bR\x12alwaysSampleErrors\x1a=\n" +
+	"\x0fOperationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"B\n" +
+	"\x04Type\x12\r\n" +
+	"\tALWAYS_ON\x10\x00\x12\x0e\n" +
+	"\n" +
+	"ALWAYS_OFF\x10\x01\x12\t\n" +
+	"\x05RATIO\x10\x02\x12\x10\n" +
+	"\fRATE_LIMITED\x10\x03\x1a\xd8\n" +
+	"\n" +
 	"\x05Trace\x12\x1a\n" +
 	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12\x1a\n" +
-	"\binsecure\x18\x02 \x01(\bR\binsecure\x1a1\n" +
+	"\binsecure\x18\x02 \x01(\bR\binsecure\x122\n" +
+	"\asampler\x18\x03 \x01(\v2\x18.kratos.api.Otel.SamplerR\asampler\x12b\n" +
+	"\x14environment_samplers\x18\x04 \x03(\v2/.kratos.api.Otel.Trace.EnvironmentSamplersEntryR\x13environmentSamplers\x12;\n" +
+	"\bexporter\x18\x05 \x01(\x0e2\x1f.kratos.api.Otel.Trace.ExporterR\bexporter\x12C\n" +
+	"\aheaders\x18\x06 \x03(\v2#.kratos.api.Otel.Trace.HeadersEntryB\x04\xc0\xc1\x18\x01R\aheaders\x12 \n" +
+	"\vcompression\x18\a \x01(\tR\vcompression\x12\x19\n" +
+	"\burl_path\x18\b \x01(\tR\aurlPath\x123\n" +
+	"\atimeout\x18\t \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x12\n" +
+	"\x04file\x18\n" +
+	" \x01(\tR\x04file\x125\n" +
+	"\x06enrich\x18\v \x01(\v2\x1d.kratos.api.Otel.Trace.EnrichR\x06enrich\x12;\n" +
+	"\bresponse\x18\f \x01(\v2\x1f.kratos.api.Otel.Trace.ResponseR\bresponse\x1a`\n" +
+	"\x18EnvironmentSamplersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12.\n" +
+	"\x05value\x18\x02 \x01(\v2\x18.kratos.api.Otel.SamplerR\x05value:\x028\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\xe8\x01\n" +
+	"\x06Enrich\x12?\n" +
+	"\bmetadata\x18\x01 \x03(\v2#.kratos.api.Otel.Trace.Enrich.FieldR\bmetadata\x12J\n" +
+	"\x0erequest_fields\x18\x02 \x03(\v2#.kratos.api.Otel.Trace.Enrich.FieldR\rrequestFields\x1aQ\n" +
+	"\x05Field\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tattribute\x18\x02 \x01(\tR\tattribute\x12\x18\n" +
+	"\abaggage\x18\x03 \x01(\bR\abaggage\x1a\xaf\x02\n" +
+	"\bResponse\x12&\n" +
+	"\x0ftrace_id_header\x18\x01 \x01(\tR\rtraceIdHeader\x12 \n" +
+	"\vtraceparent\x18\x02 \x01(\bR\vtraceparent\x12-\n" +
+	"\x12traceparent_header\x18\x03 \x01(\tR\x11traceparentHeader\x12#\n" +
+	"\rserver_timing\x18\x04 \x01(\bR\fserverTiming\x120\n" +
+	"\x14server_timing_header\x18\x05 \x01(\tR\x12serverTimingHeader\x120\n" +
+	"\x14server_timing_metric\x18\x06 \x01(\tR\x12serverTimingMetric\x12!\n" +
+	"\fgrpc_trailer\x18\a \x01(\bR\vgrpcTrailer\"H\n" +
+	"\bExporter\x12\r\n" +
+	"\tOTLP_GRPC\x10\x00\x12\r\n" +
+	"\tOTLP_HTTP\x10\x01\x12\n" +
+	"\n" +
+	"\x06STDOUT\x10\x02\x12\b\n" +
+	"\x04FILE\x10\x03\x12\b\n" +
+	"\x04NONE\x10\x04\x1a\xf2\b\n" +
 	"\x06Metric\x12'\n" +
-	"\x0fenable_exemplar\x18\x01 \x01(\bR\x0eenableExemplar\"\xae\x06\n" +
+	"\x0fenable_exemplar\x18\x01 \x01(\bR\x0eenableExemplar\x120\n" +
+	"\x04otlp\x18\x02 \x01(\v2\x1c.kratos.api.Otel.Metric.OTLPR\x04otlp\x12-\n" +
+	"\x12disable_prometheus\x18\x03 \x01(\bR\x11disablePrometheus\x12\x18\n" +
+	"\aruntime\x18\x04 \x01(\bR\aruntime\x124\n" +
+	"\x11cardinality_limit\x18\x05 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x10cardinalityLimit\x126\n" +
+	"\x06server\x18\x06 \x01(\v2\x1e.kratos.api.Otel.Metric.ServerR\x06server\x1a\x8b\x04\n" +
+	"\x04OTLP\x12\x16\n" +
+	"\x06enable\x18\x01 \x01(\bR\x06enable\x12<\n" +
+	"\bprotocol\x18\x02 \x01(\x0e2 .kratos.api.Otel.Metric.ProtocolR\bprotocol\x12\x1a\n" +
+	"\bendpoint\x18\x03 \x01(\tR\bendpoint\x12\x1a\n" +
+	"\binsecure\x18\x04 \x01(\bR\binsecure\x12I\n" +
+	"\aheaders\x18\x05 \x03(\v2).kratos.api.Otel.Metric.OTLP.HeadersEntryB\x04\xc0\xc1\x18\x01R\aheaders\x12 \n" +
+	"\vcompression\x18\x06 \x01(\tR\vcompression\x12\x19\n" +
+	"\burl_path\x18\a \x01(\tR\aurlPath\x125\n" +
+	"\binterval\x18\b \x01(\v2\x19.google.protobuf.DurationR\binterval\x123\n" +
+	"\atimeout\x18\t \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12E\n" +
+	"\vtemporality\x18\n" +
+	" \x01(\x0e2#.kratos.api.Otel.Metric.TemporalityR\vtemporality\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a)\n" +
+	"\aBuckets\x12\x1e\n" +
+	"\n" +
+	"boundaries\x18\x01 \x03(\x01R\n" +
+	"boundaries\x1a\xd2\x01\n" +
+	"\x06Server\x12\x18\n" +
+	"\abuckets\x18\x01 \x03(\x01R\abuckets\x12N\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\v2..kratos.api.Otel.Metric.Server.OperationsEntryR\n" +
+	"operations\x1a^\n" +
+	"\x0fOperationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x125\n" +
+	"\x05value\x18\x02 \x01(\v2\x1f.kratos.api.Otel.Metric.BucketsR\x05value:\x028\x01\"\x1e\n" +
+	"\bProtocol\x12\b\n" +
+	"\x04GRPC\x10\x00\x12\b\n" +
+	"\x04HTTP\x10\x01\"(\n" +
+	"\vTemporality\x12\x0e\n" +
+	"\n" +
+	"CUMULATIVE\x10\x00\x12\t\n" +
+	"\x05DELTA\x10\x01\x1aE\n" +
+	"\x17ResourceAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd9\n" +
+	"\n" +
+	"\x04Auth\x12&\n" +
+	"\x03jwt\x18\x01 \x01(\v2\x14.kratos.api.Auth.JWTR\x03jwt\x123\n" +
+	"\bpolicies\x18\x02 \x03(\v2\x17.kratos.api.Auth.PolicyR\bpolicies\x12>\n" +
+	"\x0edefault_access\x18\x03 \x01(\x0e2\x17.kratos.api.Auth.AccessR\rdefaultAccess\x123\n" +
+	"\bapi_keys\x18\x04 \x01(\v2\x18.kratos.api.Auth.APIKeysR\aapiKeys\x12)\n" +
+	"\x04hmac\x18\x05 \x01(\v2\x15.kratos.api.Auth.HMACR\x04hmac\x1af\n" +
+	"\x03Key\x12\x10\n" +
+	"\x03kid\x18\x01 \x01(\tR\x03kid\x12\x10\n" +
+	"\x03alg\x18\x02 \x01(\tR\x03alg\x12\x1c\n" +
+	"\x06secret\x18\x03 \x01(\tB\x04\xc0\xc1\x18\x01R\x06secret\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x04 \x01(\tR\tpublicKey\x1a\xa7\x01\n" +
+	"\x04JWKS\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x12\n" +
+	"\x04file\x18\x02 \x01(\tR\x04file\x12D\n" +
+	"\x10refresh_interval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x0frefreshInterval\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x1a\xc1\x01\n" +
+	"\x03JWT\x12(\n" +
+	"\x04keys\x18\x01 \x03(\v2\x14.kratos.api.Auth.KeyR\x04keys\x12)\n" +
+	"\x04jwks\x18\x02 \x01(\v2\x15.kratos.api.Auth.JWKSR\x04jwks\x12\x16\n" +
+	"\x06issuer\x18\x03 \x01(\tR\x06issuer\x12\x1a\n" +
+	"\baudience\x18\x04 \x03(\tR\baudience\x121\n" +
+	"\x06leeway\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x06leeway\x1ao\n" +
+	"\x06Policy\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12/\n" +
+	"\x06access\x18\x02 \x01(\x0e2\x17.kratos.api.Auth.AccessR\x06access\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x1aJ\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\x04hash\x18\x02 \x01(\tB\x04\xc0\xc1\x18\x01R\x04hash\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x1a\x84\x01\n" +
+	"\aAPIKeys\x12\x16\n" +
+	"\x06header\x18\x01 \x01(\tR\x06header\x12+\n" +
+	"\x04keys\x18\x02 \x03(\v2\x17.kratos.api.Auth.APIKeyR\x04keys\x12\x19\n" +
+	"\bdb_alias\x18\x03 \x01(\tR\adbAlias\x12\x19\n" +
+	"\bdb_table\x18\x04 \x01(\tR\adbTable\x1aY\n" +
+	"\n" +
+	"HMACClient\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\x06secret\x18\x02 \x01(\tB\x04\xc0\xc1\x18\x01R\x06secret\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x1a\xb5\x01\n" +
+	"\x04HMAC\x125\n" +
+	"\aclients\x18\x01 \x03(\v2\x1b.kratos.api.Auth.HMACClientR\aclients\x124\n" +
+	"\bmax_skew\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\amaxSkew\x12\x1f\n" +
+	"\vredis_alias\x18\x03 \x01(\tR\n" +
+	"redisAlias\x12\x1f\n" +
+	"\vredis_shard\x18\x04 \x01(\x05R\n" +
+	"redisShard\"'\n" +
+	"\x06Access\x12\x11\n" +
+	"\rAUTHENTICATED\x10\x00\x12\n" +
+	"\n" +
+	"\x06PUBLIC\x10\x01\"\xae\x04\n" +
+	"\x05Authz\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12\x19\n" +
+	"\betcd_key\x18\x02 \x01(\tR\aetcdKey\x12?\n" +
+	"\x0edefault_effect\x18\x03 \x01(\x0e2\x18.kratos.api.Authz.EffectR\rdefaultEffect\x124\n" +
+	"\bpolicies\x18\x04 \x03(\v2\x18.kratos.api.Authz.PolicyR\bpolicies\x1a{\n" +
+	"\tCondition\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12*\n" +
+	"\x02op\x18\x02 \x01(\x0e2\x1a.kratos.api.Authz.OperatorR\x02op\x12\x16\n" +
+	"\x06values\x18\x03 \x03(\tR\x06values\x12\x14\n" +
+	"\x05claim\x18\x04 \x01(\tR\x05claim\x1a\x9b\x01\n" +
+	"\x06Policy\x12\x1c\n" +
+	"\toperation\x18\x01 \x01(\tR\toperation\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12 \n" +
+	"\vpermissions\x18\x03 \x03(\tR\vpermissions\x12;\n" +
+	"\n" +
+	"conditions\x18\x04 \x03(\v2\x1b.kratos.api.Authz.ConditionR\n" +
+	"conditions\"\x1d\n" +
+	"\x06Effect\x12\t\n" +
+	"\x05ALLOW\x10\x00\x12\b\n" +
+	"\x04DENY\x10\x01\"E\n" +
+	"\bOperator\x12\x06\n" +
+	"\x02EQ\x10\x00\x12\x06\n" +
+	"\x02NE\x10\x01\x12\x06\n" +
+	"\x02IN\x10\x02\x12\n" +
+	"\n" +
+	"\x06NOT_IN\x10\x03\x12\n" +
+	"\n" +
+	"\x06PREFIX\x10\x04\x12\t\n" +
+	"\x05REGEX\x10\x05\"\xa5\x02\n" +
+	"\vIdempotency\x12\x1f\n" +
+	"\vredis_alias\x18\x01 \x01(\tR\n" +
+	"redisAlias\x12\x1f\n" +
+	"\vredis_shard\x18\x02 \x01(\x05R\n" +
+	"redisShard\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x03 \x03(\tR\n" +
+	"operations\x12\x16\n" +
+	"\x06header\x18\x04 \x01(\tR\x06header\x12;\n" +
+	"\x03ttl\x18\x05 \x01(\v2\x19.google.protobuf.DurationB\x0e\xbaH\v\xaa\x01\b\"\x04\b\x80\xf5$2\x00R\x03ttl\x12C\n" +
+	"\block_ttl\x18\x06 \x01(\v2\x19.google.protobuf.DurationB\r\xbaH\n" +
+	"\xaa\x01\a\"\x03\b\x90\x1c2\x00R\alockTtl\x12\x1a\n" +
+	"\brequired\x18\a \x01(\bR\brequired\"\xb8\x02\n" +
+	"\x05Audit\x12\x16\n" +
+	"\x06enable\x18\x01 \x01(\bR\x06enable\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\tR\n" +
+	"operations\x12#\n" +
+	"\rredact_fields\x18\x03 \x03(\tR\fredactFields\x12!\n" +
+	"\fskip_request\x18\x04 \x01(\bR\vskipRequest\x12#\n" +
+	"\rskip_response\x18\x05 \x01(\bR\fskipResponse\x12*\n" +
+	"\x04sink\x18\x06 \x01(\x0e2\x16.kratos.api.Audit.SinkR\x04sink\x12!\n" +
+	"\x03log\x18\a \x01(\v2\x0f.kratos.api.LogR\x03log\x12\x1f\n" +
+	"\vkafka_topic\x18\b \x01(\tR\n" +
+	"kafkaTopic\"\x1a\n" +
+	"\x04Sink\x12\a\n" +
+	"\x03LOG\x10\x00\x12\t\n" +
+	"\x05KAFKA\x10\x01\"\xe1\x01\n" +
+	"\x06Tenant\x12\x16\n" +
+	"\x06enable\x18\x01 \x01(\bR\x06enable\x12\x16\n" +
+	"\x06header\x18\x02 \x01(\tR\x06header\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x1a\n" +
+	"\brequired\x18\x04 \x01(\bR\brequired\x12'\n" +
+	"\x0fskip_operations\x18\x05 \x03(\tR\x0eskipOperations\x12#\n" +
+	"\rswitch_scopes\x18\x06 \x03(\tR\fswitchScopes\x12%\n" +
+	"\x0etrust_metadata\x18\a \x01(\bR\rtrustMetadata\"\xca\t\n" +
 	"\x04Data\x12:\n" +
 	"\bdatabase\x18\x01 \x03(\v2\x1e.kratos.api.Data.DatabaseEntryR\bdatabase\x121\n" +
 	"\x05redis\x18\x02 \x03(\v2\x1b.kratos.api.Data.RedisEntryR\x05redis\x12,\n" +
-	"\x05kafka\x18\x03 \x01(\v2\x16.kratos.api.Data.KafkaR\x05kafka\x1a\xc9\x01\n" +
-	"\bDatabase\x12\x16\n" +
-	"\x06driver\x18\x01 \x01(\tR\x06driver\x12\x16\n" +
-	"\x06source\x18\x02 \x01(\tR\x06source\x12\"\n" +
-	"\rmax_open_conn\x18\x03 \x01(\x05R\vmaxOpenConn\x12\"\n" +
-	"\rmax_idle_conn\x18\x04 \x01(\x05R\vmaxIdleConn\x12E\n" +
-	"\x11conn_max_lifetime\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x0fconnMaxLifetime\x1a\xcd\x01\n" +
-	"\x05Redis\x12\x12\n" +
-	"\x04addr\x18\x01 \x01(\tR\x04addr\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\x12<\n" +
-	"\fread_timeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\vreadTimeout\x12>\n" +
-	"\rwrite_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\fwriteTimeout\x12\x16\n" +
-	"\x06shards\x18\x05 \x03(\x05R\x06shards\x1aC\n" +
-	"\x05Kafka\x12\x1f\n" +
-	"\vbroker_list\x18\x01 \x03(\tR\n" +
+	"\x05kafka\x18\x03 \x01(\v2\x16.kratos.api.Data.KafkaR\x05kafka\x1a\x9c\x04\n" +
+	"\bDatabase\x120\n" +
+	"\x06driver\x18\x01 \x01(\tB\x18\xbaH\x15r\x13R\x05mysqlR\n" +
+	"postgresqlR\x06driver\x12#\n" +
+	"\x06source\x18\x02 \x01(\tB\v\xbaH\x04r\x02\x10\x01\xc0\xc1\x18\x01R\x06source\x12+\n" +
+	"\rmax_open_conn\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\vmaxOpenConn\x12+\n" +
+	"\rmax_idle_conn\x18\x04 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\vmaxIdleConn\x12U\n" +
+	"\x11conn_max_lifetime\x18\x05 \x01(\v2\x19.google.protobuf.DurationB\x0e\xbaH\v\xaa\x01\b\"\x04\b\x80\xa3\x052\x00R\x0fconnMaxLifetime\x12N\n" +
+	"\x0eslow_threshold\x18\x06 \x01(\v2\x19.google.protobuf.DurationB\f\xbaH\t\xaa\x01\x06\"\x02\b<2\x00R\rslowThreshold\x12-\n" +
+	"\abuckets\x18\a \x03(\x01B\x13\xbaH\x10\x92\x01\r\"\v\x12\t!\x00\x00\x00\x00\x00\x00\x00\x00R\abuckets:\x88\x01\xbaH\x84\x01\x1a\x81\x01\n" +
+	"\rmax_idle_conn\x12+max_idle_conn must not exceed max_open_conn\x1aCthis.max_open_conn == 0 || this.max_idle_conn <= this.max_open_conn\x1a\x88\x02\n" +
+	"\x05Redis\x12\x1b\n" +
+	"\x04addr\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x04addr\x12 \n" +
+	"\bpassword\x18\x02 \x01(\tB\x04\xc0\xc1\x18\x01R\bpassword\x12J\n" +
+	"\fread_timeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationB\f\xbaH\t\xaa\x01\x06\"\x02\b<2\x00R\vreadTimeout\x12L\n" +
+	"\rwrite_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationB\f\xbaH\t\xaa\x01\x06\"\x02\b<2\x00R\fwriteTimeout\x12&\n" +
+	"\x06shards\x18\x05 \x03(\x05B\x0e\xbaH\v\x92\x01\b\b\x01\"\x04\x1a\x02(\x00R\x06shards\x1aQ\n" +
+	"\x05Kafka\x12-\n" +
+	"\vbroker_list\x18\x01 \x03(\tB\f\xbaH\t\x92\x01\x06\"\x04r\x02\x10\x01R\n" +
 	"brokerList\x12\x19\n" +
 	"\bgroup_id\x18\x02 \x01(\tR\agroupId\x1aV\n" +
 	"\rDatabaseEntry\x12\x10\n" +
@@ -1145,7 +4584,8 @@ const file_conf_conf_proto_rawDesc = "" +
 	"\x04NONE\x10\x00\x12\a\n" +
 	"\x03DEV\x10\x01\x12\a\n" +
 	"\x03PRE\x10\x02\x12\b\n" +
-	"\x04PROD\x10\x03B7Z5github.com/go-kratos/kratos-layout/internal/conf;confb\x06proto3"
+	"\x04PROD\x10\x03:7\n" +
+	"\x06secret\x12\x1d.google.protobuf.FieldOptions\x18\x98\x88\x03 \x01(\bR\x06secretB7Z5github.com/go-kratos/kratos-layout/internal/conf;confb\x06proto3"
 
 var (
 	file_conf_conf_proto_rawDescOnce sync.Once
@@ -1159,60 +4599,185 @@ func file_conf_conf_proto_rawDescGZIP() []byte {
 	return file_conf_conf_proto_rawDescData
 }
 
-var file_conf_conf_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_conf_conf_proto_enumTypes = make([]protoimpl.EnumInfo, 11)
+var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 57)
 var file_conf_conf_proto_goTypes = []any{
-	(Environment)(0),            // 0: kratos.api.Environment
-	(*Bootstrap)(nil),           // 1: kratos.api.Bootstrap
-	(*MetaData)(nil),            // 2: kratos.api.MetaData
-	(*Log)(nil),                 // 3: kratos.api.Log
-	(*Server)(nil),              // 4: kratos.api.Server
-	(*Registry)(nil),            // 5: kratos.api.Registry
-	(*BBR)(nil),                 // 6: kratos.api.BBR
-	(*Otel)(nil),                // 7: kratos.api.Otel
-	(*Data)(nil),                // 8: kratos.api.Data
-	(*Server_HTTP)(nil),         // 9: kratos.api.Server.HTTP
-	(*Server_GRPC)(nil),         // 10: kratos.api.Server.GRPC
-	(*Server_Pprof)(nil),        // 11: kratos.api.Server.Pprof
-	(*Otel_Trace)(nil),          // 12: kratos.api.Otel.Trace
-	(*Otel_Metric)(nil),         // 13: kratos.api.Otel.Metric
-	(*Data_Database)(nil),       // 14: kratos.api.Data.Database
-	(*Data_Redis)(nil),          // 15: kratos.api.Data.Redis
-	(*Data_Kafka)(nil),          // 16: kratos.api.Data.Kafka
-	nil,                         // 17: kratos.api.Data.DatabaseEntry
-	nil,                         // 18: kratos.api.Data.RedisEntry
-	(*durationpb.Duration)(nil), // 19: google.protobuf.Duration
+	(Environment)(0),                    // 0: kratos.api.Environment
+	(Log_OTLP_Protocol)(0),              // 1: kratos.api.Log.OTLP.Protocol
+	(Server_HTTP_Envelope)(0),           // 2: kratos.api.Server.HTTP.Envelope
+	(Otel_Sampler_Type)(0),              // 3: kratos.api.Otel.Sampler.Type
+	(Otel_Trace_Exporter)(0),            // 4: kratos.api.Otel.Trace.Exporter
+	(Otel_Metric_Protocol)(0),           // 5: kratos.api.Otel.Metric.Protocol
+	(Otel_Metric_Temporality)(0),        // 6: kratos.api.Otel.Metric.Temporality
+	(Auth_Access)(0),                    // 7: kratos.api.Auth.Access
+	(Authz_Effect)(0),                   // 8: kratos.api.Authz.Effect
+	(Authz_Operator)(0),                 // 9: kratos.api.Authz.Operator
+	(Audit_Sink)(0),                     // 10: kratos.api.Audit.Sink
+	(*Bootstrap)(nil),                   // 11: kratos.api.Bootstrap
+	(*RateLimit)(nil),                   // 12: kratos.api.RateLimit
+	(*MetaData)(nil),                    // 13: kratos.api.MetaData
+	(*Log)(nil),                         // 14: kratos.api.Log
+	(*Server)(nil),                      // 15: kratos.api.Server
+	(*Registry)(nil),                    // 16: kratos.api.Registry
+	(*BBR)(nil),                         // 17: kratos.api.BBR
+	(*Otel)(nil),                        // 18: kratos.api.Otel
+	(*Auth)(nil),                        // 19: kratos.api.Auth
+	(*Authz)(nil),                       // 20: kratos.api.Authz
+	(*Idempotency)(nil),                 // 21: kratos.api.Idempotency
+	(*Audit)(nil),                       // 22: kratos.api.Audit
+	(*Tenant)(nil),                      // 23: kratos.api.Tenant
+	(*Data)(nil),                        // 24: kratos.api.Data
+	nil,                                 // 25: kratos.api.Bootstrap.FeaturesEntry
+	(*Log_OTLP)(nil),                    // 26: kratos.api.Log.OTLP
+	nil,                                 // 27: kratos.api.Log.OTLP.HeadersEntry
+	(*Server_HTTP)(nil),                 // 28: kratos.api.Server.HTTP
+	(*Server_GRPC)(nil),                 // 29: kratos.api.Server.GRPC
+	(*Server_Pprof)(nil),                // 30: kratos.api.Server.Pprof
+	(*Server_HTTP_CORS)(nil),            // 31: kratos.api.Server.HTTP.CORS
+	(*Server_HTTP_SecurityHeaders)(nil), // 32: kratos.api.Server.HTTP.SecurityHeaders
+	nil,                                 // 33: kratos.api.Server.HTTP.RouteMaxBodySizeEntry
+	(*Server_Pprof_Watchdog)(nil),       // 34: kratos.api.Server.Pprof.Watchdog
+	(*Server_Pprof_Push)(nil),           // 35: kratos.api.Server.Pprof.Push
+	nil,                                 // 36: kratos.api.Server.Pprof.Push.LabelsEntry
+	nil,                                 // 37: kratos.api.Server.Pprof.Push.HeadersEntry
+	(*Otel_Sampler)(nil),                // 38: kratos.api.Otel.Sampler
+	(*Otel_Trace)(nil),                  // 39: kratos.api.Otel.Trace
+	(*Otel_Metric)(nil),                 // 40: kratos.api.Otel.Metric
+	nil,                                 // 41: kratos.api.Otel.ResourceAttributesEntry
+	nil,                                 // 42: kratos.api.Otel.Sampler.OperationsEntry
+	nil,                                 // 43: kratos.api.Otel.Trace.EnvironmentSamplersEntry
+	nil,                                 // 44: kratos.api.Otel.Trace.HeadersEntry
+	(*Otel_Trace_Enrich)(nil),           // 45: kratos.api.Otel.Trace.Enrich
+	(*Otel_Trace_Response)(nil),         // 46: kratos.api.Otel.Trace.Response
+	(*Otel_Trace_Enrich_Field)(nil),     // 47: kratos.api.Otel.Trace.Enrich.Field
+	(*Otel_Metric_OTLP)(nil),            // 48: kratos.api.Otel.Metric.OTLP
+	(*Otel_Metric_Buckets)(nil),         // 49: kratos.api.Otel.Metric.Buckets
+	(*Otel_Metric_Server)(nil),          // 50: kratos.api.Otel.Metric.Server
+	nil,                                 // 51: kratos.api.Otel.Metric.OTLP.HeadersEntry
+	nil,                                 // 52: kratos.api.Otel.Metric.Server.OperationsEntry
+	(*Auth_Key)(nil),                    // 53: kratos.api.Auth.Key
+	(*Auth_JWKS)(nil),                   // 54: kratos.api.Auth.JWKS
+	(*Auth_JWT)(nil),                    // 55: kratos.api.Auth.JWT
+	(*Auth_Policy)(nil),                 // 56: kratos.api.Auth.Policy
+	(*Auth_APIKey)(nil),                 // 57: kratos.api.Auth.APIKey
+	(*Auth_APIKeys)(nil),                // 58: kratos.api.Auth.APIKeys
+	(*Auth_HMACClient)(nil),             // 59: kratos.api.Auth.HMACClient
+	(*Auth_HMAC)(nil),                   // 60: kratos.api.Auth.HMAC
+	(*Authz_Condition)(nil),             // 61: kratos.api.Authz.Condition
+	(*Authz_Policy)(nil),                // 62: kratos.api.Authz.Policy
+	(*Data_Database)(nil),               // 63: kratos.api.Data.Database
+	(*Data_Redis)(nil),                  // 64: kratos.api.Data.Redis
+	(*Data_Kafka)(nil),                  // 65: kratos.api.Data.Kafka
+	nil,                                 // 66: kratos.api.Data.DatabaseEntry
+	nil,                                 // 67: kratos.api.Data.RedisEntry
+	(*durationpb.Duration)(nil),         // 68: google.protobuf.Duration
+	(*descriptorpb.FieldOptions)(nil),   // 69: google.protobuf.FieldOptions
 }
 var file_conf_conf_proto_depIdxs = []int32{
 	0,  // 0: kratos.api.Bootstrap.env:type_name -> kratos.api.Environment
-	2,  // 1: kratos.api.Bootstrap.metadata:type_name -> kratos.api.MetaData
-	4,  // 2: kratos.api.Bootstrap.server:type_name -> kratos.api.Server
-	5,  // 3: kratos.api.Bootstrap.registry:type_name -> kratos.api.Registry
-	6,  // 4: kratos.api.Bootstrap.bbr:type_name -> kratos.api.BBR
-	7,  // 5: kratos.api.Bootstrap.otel:type_name -> kratos.api.Otel
-	3,  // 6: kratos.api.Bootstrap.log:type_name -> kratos.api.Log
-	8,  // 7: kratos.api.Bootstrap.data:type_name -> kratos.api.Data
-	9,  // 8: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
-	10, // 9: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
-	11, // 10: kratos.api.Server.pprof:type_name -> kratos.api.Server.Pprof
-	19, // 11: kratos.api.BBR.window_size:type_name -> google.protobuf.Duration
-	12, // 12: kratos.api.Otel.trace:type_name -> kratos.api.Otel.Trace
-	13, // 13: kratos.api.Otel.metric:type_name -> kratos.api.Otel.Metric
-	17, // 14: kratos.api.Data.database:type_name -> kratos.api.Data.DatabaseEntry
-	18, // 15: kratos.api.Data.redis:type_name -> kratos.api.Data.RedisEntry
-	16, // 16: kratos.api.Data.kafka:type_name -> kratos.api.Data.Kafka
-	19, // 17: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
-	19, // 18: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
-	19, // 19: kratos.api.Data.Database.conn_max_lifetime:type_name -> google.protobuf.Duration
-	19, // 20: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
-	19, // 21: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
-	14, // 22: kratos.api.Data.DatabaseEntry.value:type_name -> kratos.api.Data.Database
-	15, // 23: kratos.api.Data.RedisEntry.value:type_name -> kratos.api.Data.Redis
-	24, // [24:24] is the sub-list for method output_type
-	24, // [24:24] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+	13, // 1: kratos.api.Bootstrap.metadata:type_name -> kratos.api.MetaData
+	15, // 2: kratos.api.Bootstrap.server:type_name -> kratos.api.Server
+	16, // 3: kratos.api.Bootstrap.registry:type_name -> kratos.api.Registry
+	17, // 4: kratos.api.Bootstrap.bbr:type_name -> kratos.api.BBR
+	18, // 5: kratos.api.Bootstrap.otel:type_name -> kratos.api.Otel
+	14, // 6: kratos.api.Bootstrap.log:type_name -> kratos.api.Log
+	24, // 7: kratos.api.Bootstrap.data:type_name -> kratos.api.Data
+	19, // 8: kratos.api.Bootstrap.auth:type_name -> kratos.api.Auth
+	20, // 9: kratos.api.Bootstrap.authz:type_name -> kratos.api.Authz
+	21, // 10: kratos.api.Bootstrap.idempotency:type_name -> kratos.api.Idempotency
+	22, // 11: kratos.api.Bootstrap.audit:type_name -> kratos.api.Audit
+	23, // 12: kratos.api.Bootstrap.tenant:type_name -> kratos.api.Tenant
+	25, // 13: kratos.api.Bootstrap.features:type_name -> kratos.api.Bootstrap.FeaturesEntry
+	12, // 14: kratos.api.Bootstrap.rate_limits:type_name -> kratos.api.RateLimit
+	68, // 15: kratos.api.RateLimit.timeout:type_name -> google.protobuf.Duration
+	26, // 16: kratos.api.Log.otlp:type_name -> kratos.api.Log.OTLP
+	28, // 17: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
+	29, // 18: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
+	30, // 19: kratos.api.Server.pprof:type_name -> kratos.api.Server.Pprof
+	68, // 20: kratos.api.BBR.window_size:type_name -> google.protobuf.Duration
+	39, // 21: kratos.api.Otel.trace:type_name -> kratos.api.Otel.Trace
+	40, // 22: kratos.api.Otel.metric:type_name -> kratos.api.Otel.Metric
+	41, // 23: kratos.api.Otel.resource_attributes:type_name -> kratos.api.Otel.ResourceAttributesEntry
+	55, // 24: kratos.api.Auth.jwt:type_name -> kratos.api.Auth.JWT
+	56, // 25: kratos.api.Auth.policies:type_name -> kratos.api.Auth.Policy
+	7,  // 26: kratos.api.Auth.default_access:type_name -> kratos.api.Auth.Access
+	58, // 27: kratos.api.Auth.api_keys:type_name -> kratos.api.Auth.APIKeys
+	60, // 28: kratos.api.Auth.hmac:type_name -> kratos.api.Auth.HMAC
+	8,  // 29: kratos.api.Authz.default_effect:type_name -> kratos.api.Authz.Effect
+	62, // 30: kratos.api.Authz.policies:type_name -> kratos.api.Authz.Policy
+	68, // 31: kratos.api.Idempotency.ttl:type_name -> google.protobuf.Duration
+	68, // 32: kratos.api.Idempotency.lock_ttl:type_name -> google.protobuf.Duration
+	10, // 33: kratos.api.Audit.sink:type_name -> kratos.api.Audit.Sink
+	14, // 34: kratos.api.Audit.log:type_name -> kratos.api.Log
+	66, // 35: kratos.api.Data.database:type_name -> kratos.api.Data.DatabaseEntry
+	67, // 36: kratos.api.Data.redis:type_name -> kratos.api.Data.RedisEntry
+	65, // 37: kratos.api.Data.kafka:type_name -> kratos.api.Data.Kafka
+	1,  // 38: kratos.api.Log.OTLP.protocol:type_name -> kratos.api.Log.OTLP.Protocol
+	27, // 39: kratos.api.Log.OTLP.headers:type_name -> kratos.api.Log.OTLP.HeadersEntry
+	68, // 40: kratos.api.Log.OTLP.timeout:type_name -> google.protobuf.Duration
+	68, // 41: kratos.api.Log.OTLP.export_interval:type_name -> google.protobuf.Duration
+	68, // 42: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
+	2,  // 43: kratos.api.Server.HTTP.envelope:type_name -> kratos.api.Server.HTTP.Envelope
+	31, // 44: kratos.api.Server.HTTP.cors:type_name -> kratos.api.Server.HTTP.CORS
+	32, // 45: kratos.api.Server.HTTP.security_headers:type_name -> kratos.api.Server.HTTP.SecurityHeaders
+	33, // 46: kratos.api.Server.HTTP.route_max_body_size:type_name -> kratos.api.Server.HTTP.RouteMaxBodySizeEntry
+	68, // 47: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
+	34, // 48: kratos.api.Server.Pprof.watchdog:type_name -> kratos.api.Server.Pprof.Watchdog
+	35, // 49: kratos.api.Server.Pprof.push:type_name -> kratos.api.Server.Pprof.Push
+	68, // 50: kratos.api.Server.HTTP.CORS.max_age:type_name -> google.protobuf.Duration
+	68, // 51: kratos.api.Server.HTTP.SecurityHeaders.hsts_max_age:type_name -> google.protobuf.Duration
+	68, // 52: kratos.api.Server.Pprof.Watchdog.interval:type_name -> google.protobuf.Duration
+	68, // 53: kratos.api.Server.Pprof.Watchdog.cooldown:type_name -> google.protobuf.Duration
+	68, // 54: kratos.api.Server.Pprof.Watchdog.cpu_duration:type_name -> google.protobuf.Duration
+	68, // 55: kratos.api.Server.Pprof.Push.interval:type_name -> google.protobuf.Duration
+	68, // 56: kratos.api.Server.Pprof.Push.timeout:type_name -> google.protobuf.Duration
+	36, // 57: kratos.api.Server.Pprof.Push.labels:type_name -> kratos.api.Server.Pprof.Push.LabelsEntry
+	37, // 58: kratos.api.Server.Pprof.Push.headers:type_name -> kratos.api.Server.Pprof.Push.HeadersEntry
+	68, // 59: kratos.api.Server.Pprof.Push.cpu_duration:type_name -> google.protobuf.Duration
+	3,  // 60: kratos.api.Otel.Sampler.type:type_name -> kratos.api.Otel.Sampler.Type
+	42, // 61: kratos.api.Otel.Sampler.operations:type_name -> kratos.api.Otel.Sampler.OperationsEntry
+	38, // 62: kratos.api.Otel.Trace.sampler:type_name -> kratos.api.Otel.Sampler
+	43, // 63: kratos.api.Otel.Trace.environment_samplers:type_name -> kratos.api.Otel.Trace.EnvironmentSamplersEntry
+	4,  // 64: kratos.api.Otel.Trace.exporter:type_name -> kratos.api.Otel.Trace.Exporter
+	44, // 65: kratos.api.Otel.Trace.headers:type_name -> kratos.api.Otel.Trace.HeadersEntry
+	68, // 66: kratos.api.Otel.Trace.timeout:type_name -> google.protobuf.Duration
+	45, // 67: kratos.api.Otel.Trace.enrich:type_name -> kratos.api.Otel.Trace.Enrich
+	46, // 68: kratos.api.Otel.Trace.response:type_name -> kratos.api.Otel.Trace.Response
+	48, // 69: kratos.api.Otel.Metric.otlp:type_name -> kratos.api.Otel.Metric.OTLP
+	50, // 70: kratos.api.Otel.Metric.server:type_name -> kratos.api.Otel.Metric.Server
+	38, // 71: kratos.api.Otel.Trace.EnvironmentSamplersEntry.value:type_name -> kratos.api.Otel.Sampler
+	47, // 72: kratos.api.Otel.Trace.Enrich.metadata:type_name -> kratos.api.Otel.Trace.Enrich.Field
+	47, // 73: kratos.api.Otel.Trace.Enrich.request_fields:type_name -> kratos.api.Otel.Trace.Enrich.Field
+	5,  // 74: kratos.api.Otel.Metric.OTLP.protocol:type_name -> kratos.api.Otel.Metric.Protocol
+	51, // 75: kratos.api.Otel.Metric.OTLP.headers:type_name -> kratos.api.Otel.Metric.OTLP.HeadersEntry
+	68, // 76: kratos.api.Otel.Metric.OTLP.interval:type_name -> google.protobuf.Duration
+	68, // 77: kratos.api.Otel.Metric.OTLP.timeout:type_name -> google.protobuf.Duration
+	6,  // 78: kratos.api.Otel.Metric.OTLP.temporality:type_name -> kratos.api.Otel.Metric.Temporality
+	52, // 79: kratos.api.Otel.Metric.Server.operations:type_name -> kratos.api.Otel.Metric.Server.OperationsEntry
+	49, // 80: kratos.api.Otel.Metric.Server.OperationsEntry.value:type_name -> kratos.api.Otel.Metric.Buckets
+	68, // 81: kratos.api.Auth.JWKS.refresh_interval:type_name -> google.protobuf.Duration
+	68, // 82: kratos.api.Auth.JWKS.timeout:type_name -> google.protobuf.Duration
+	53, // 83: kratos.api.Auth.JWT.keys:type_name -> kratos.api.Auth.Key
+	54, // 84: kratos.api.Auth.JWT.jwks:type_name -> kratos.api.Auth.JWKS
+	68, // 85: kratos.api.Auth.JWT.leeway:type_name -> google.protobuf.Duration
+	7,  // 86: kratos.api.Auth.Policy.access:type_name -> kratos.api.Auth.Access
+	57, // 87: kratos.api.Auth.APIKeys.keys:type_name -> kratos.api.Auth.APIKey
+	59, // 88: kratos.api.Auth.HMAC.clients:type_name -> kratos.api.Auth.HMACClient
+	68, // 89: kratos.api.Auth.HMAC.max_skew:type_name -> google.protobuf.Duration
+	9,  // 90: kratos.api.Authz.Condition.op:type_name -> kratos.api.Authz.Operator
+	61, // 91: kratos.api.Authz.Policy.conditions:type_name -> kratos.api.Authz.Condition
+	68, // 92: kratos.api.Data.Database.conn_max_lifetime:type_name -> google.protobuf.Duration
+	68, // 93: kratos.api.Data.Database.slow_threshold:type_name -> google.protobuf.Duration
+	68, // 94: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
+	68, // 95: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
+	63, // 96: kratos.api.Data.DatabaseEntry.value:type_name -> kratos.api.Data.Database
+	64, // 97: kratos.api.Data.RedisEntry.value:type_name -> kratos.api.Data.Redis
+	69, // 98: kratos.api.secret:extendee -> google.protobuf.FieldOptions
+	99, // [99:99] is the sub-list for method output_type
+	99, // [99:99] is the sub-list for method input_type
+	99, // [99:99] is the sub-list for extension type_name
+	98, // [98:99] is the sub-list for extension extendee
+	0,  // [0:98] is the sub-list for field type_name
 }
 
 func init() { file_conf_conf_proto_init() }
@@ -1225,15 +4790,16 @@ func file_conf_conf_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_conf_conf_proto_rawDesc), len(file_conf_conf_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   18,
-			NumExtensions: 0,
+			NumEnums:      11,
+			NumMessages:   57,
+			NumExtensions: 1,
 			NumServices:   0,
 		},
 		GoTypes:           file_conf_conf_proto_goTypes,
 		DependencyIndexes: file_conf_conf_proto_depIdxs,
 		EnumInfos:         file_conf_conf_proto_enumTypes,
 		MessageInfos:      file_conf_conf_proto_msgTypes,
+		ExtensionInfos:    file_conf_conf_proto_extTypes,
 	}.Build()
 	File_conf_conf_proto = out.File
 	file_conf_conf_proto_goTypes = nil
@@ -0,0 +1,100 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/IBM/sarama"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/audit"
+	zaplog "github.com/go-kratos/kratos-layout/pkg/log"
+	"github.com/go-kratos/kratos-layout/pkg/trace/kafka"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+const defaultAuditLogPath = "/app/log/audit.log"
+
+type logAuditSink struct {
+	logger *zap.Logger
+}
+
+func (s *logAuditSink) Write(_ context.Context, r *audit.Record) error {
+	s.logger.Info("audit",
+		zap.Time("time", r.Time),
+		zap.String("operation", r.Operation),
+		zap.String("transport", r.Transport),
+		zap.String("subject", r.Subject),
+		zap.String("tenant", r.Tenant),
+		zap.String("request_id", r.RequestID),
+		zap.String("trace_id", r.TraceID),
+		zap.Reflect("request", r.Request),
+		zap.Reflect("response", r.Response),
+		zap.Int32("code", r.Code),
+		zap.String("reason", r.Reason),
+		zap.Float64("latency_ms", r.LatencyMs),
+	)
+	return nil
+}
+
+// ErrAuditDropped is the audit record is dropped as the producer is full.
+var ErrAuditDropped = errors.New("audit record dropped: kafka producer is full")
+
+type kafkaAuditSink struct {
+	producer sarama.AsyncProducer
+	topic    string
+	dropped  metric.Int64Counter
+}
+
+func (s *kafkaAuditSink) Write(ctx context.Context, r *audit.Record) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	message := &sarama.ProducerMessage{
+		Topic: s.topic,
+		Key:   sarama.StringEncoder(r.Operation),
+		Value: sarama.ByteEncoder(value),
+	}
+	kafka.InjectRequestID(ctx, message)
+	// the calls are never blocked by the audit, the records are dropped and
+	// counted when the producer can not keep up
+	select {
+	case s.producer.Input() <- message:
+		return nil
+	default:
+		s.dropped.Add(ctx, 1, metric.WithAttributes(attribute.String("operation", r.Operation)))
+		return ErrAuditDropped
+	}
+}
+
+// NewAuditSink new the configured audit.Sink, nil when audit is disabled.
+func NewAuditSink(c *conf.Bootstrap, data *Data, meter metric.Meter) (audit.Sink, func(), error) {
+	ac := c.GetAudit()
+	if !ac.GetEnable() {
+		return nil, func() {}, nil
+	}
+	switch ac.GetSink() {
+	case conf.Audit_KAFKA:
+		if ac.GetKafkaTopic() == "" {
+			return nil, nil, errors.New("audit kafka sink requires a topic")
+		}
+		dropped, err := meter.Int64Counter("audit.records.dropped",
+			metric.WithDescription("The audit records dropped as the kafka producer is full."),
+			metric.WithUnit("{record}"),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &kafkaAuditSink{producer: data.producer, topic: ac.GetKafkaTopic(), dropped: dropped}, func() {}, nil
+	default:
+		lc := ac.GetLog()
+		if lc.GetFilepath() == "" {
+			lc = &conf.Log{Filepath: defaultAuditLogPath, MaxSize: lc.GetMaxSize(), MaxAge: lc.GetMaxAge(), MaxBackups: lc.GetMaxBackups()}
+		}
+		logger, cleanup := zaplog.NewAuditLogger(lc)
+		return &logAuditSink{logger: logger}, cleanup, nil
+	}
+}
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/go-kratos/kratos-layout/pkg/audit"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// fullProducer is a producer whose input is full once the buffer is.
+type fullProducer struct {
+	sarama.AsyncProducer
+	input chan *sarama.ProducerMessage
+}
+
+func (p *fullProducer) Input() chan<- *sarama.ProducerMessage { return p.input }
+
+func TestKafkaAuditSinkDrop(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	dropped, err := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test").Int64Counter("audit.records.dropped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	producer := &fullProducer{input: make(chan *sarama.ProducerMessage, 1)}
+	sink := &kafkaAuditSink{producer: producer, topic: "audit", dropped: dropped}
+
+	// the canceled context of the call does not drop the record
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = sink.Write(ctx, &audit.Record{Operation: "/helloworld.v1.Greeter/SayHello"}); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	if err = sink.Write(context.Background(), &audit.Record{Operation: "/helloworld.v1.Greeter/SayHello"}); !errors.Is(err, ErrAuditDropped) {
+		t.Fatalf("Write() = %v, want %v", err, ErrAuditDropped)
+	}
+	if m := <-producer.input; m.Topic != "audit" {
+		t.Errorf("topic = %q, want audit", m.Topic)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err = reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+		t.Errorf("dropped = %+v, want 1", sum.DataPoints)
+	}
+}
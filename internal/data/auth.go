@@ -0,0 +1,77 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const nonceKeyPrefix = "auth:nonce:"
+
+type nonceStore struct {
+	rdb *redis.Client
+}
+
+// NewNonceStore new a redis auth.NonceStore, nil when hmac is not configured.
+func NewNonceStore(c *conf.Bootstrap, data *Data) (auth.NonceStore, error) {
+	hc := c.GetAuth().GetHmac()
+	if hc.GetRedisAlias() == "" {
+		return nil, nil
+	}
+	rdb := data.rdb.GetRdbClient(hc.GetRedisAlias(), Shard(hc.GetRedisShard()))
+	if rdb == nil {
+		return nil, fmt.Errorf("auth hmac redis not found: %s/%d", hc.GetRedisAlias(), hc.GetRedisShard())
+	}
+	return &nonceStore{rdb: rdb}, nil
+}
+
+func (s *nonceStore) Remember(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error) {
+	return s.rdb.SetNX(ctx, nonceKeyPrefix+keyID+":"+nonce, 1, ttl).Result()
+}
+
+// apiKeyModel is a row of the api key table.
+type apiKeyModel struct {
+	ID      string
+	KeyHash string
+	Scopes  string
+	Revoked bool
+}
+
+type apiKeyStore struct {
+	db    *gorm.DB
+	table string
+}
+
+// NewAPIKeyStore new a database auth.APIKeyStore, nil when no table is configured.
+func NewAPIKeyStore(c *conf.Bootstrap, data *Data) (auth.APIKeyStore, error) {
+	kc := c.GetAuth().GetApiKeys()
+	if kc.GetDbAlias() == "" || kc.GetDbTable() == "" {
+		return nil, nil
+	}
+	db := data.db.GetDbClient(kc.GetDbAlias())
+	if db == nil {
+		return nil, fmt.Errorf("auth api key database not found: %s", kc.GetDbAlias())
+	}
+	return &apiKeyStore{db: db, table: kc.GetDbTable()}, nil
+}
+
+func (s *apiKeyStore) FindAPIKey(ctx context.Context, hash string) (*auth.APIKey, error) {
+	var m apiKeyModel
+	err := s.db.WithContext(ctx).Table(s.table).
+		Where("key_hash = ? AND revoked = ?", hash, false).
+		Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &auth.APIKey{ID: m.ID, Scopes: strings.Fields(m.Scopes)}, nil
+}
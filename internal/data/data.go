@@ -12,7 +12,7 @@ import (
 )
 
 // ProviderSet is data providers.
-var ProviderSet = wire.NewSet(NewData, NewGreeterRepo)
+var ProviderSet = wire.NewSet(NewData, NewGreeterRepo, NewNonceStore, NewAPIKeyStore, NewIdempotencyStore, NewAuditSink)
 
 // Data .
 type Data struct {
@@ -20,11 +20,12 @@ type Data struct {
 	rdb           RedisClient
 	producer      sarama.AsyncProducer
 	consumerGroup sarama.ConsumerGroup
+	tenancy       bool
 }
 
 // NewData .
 func NewData(c *conf.Bootstrap, provider trace.TracerProvider, meterProvider metric.MeterProvider, textMapPropagator propagation.TextMapPropagator, logger log.Logger) (*Data, func(), error) {
-	dbClient, dbClean, err := newDB(c, provider)
+	dbClient, dbClean, err := newDB(c, provider, meterProvider, logger)
 	if err != nil {
 		panic(err)
 	}
@@ -54,5 +55,6 @@ func NewData(c *conf.Bootstrap, provider trace.TracerProvider, meterProvider met
 		rdb:           redisClient,
 		producer:      producer,
 		consumerGroup: consumerGroup,
+		tenancy:       c.GetTenant().GetEnable(),
 	}, cleanup, nil
 }
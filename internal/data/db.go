@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"gorm.io/driver/mysql"
@@ -16,18 +18,19 @@ type (
 	DbClient map[string]*gorm.DB
 )
 
-func newDB(c *conf.Bootstrap, provider trace.TracerProvider) (DbClient, func(), error) {
+func newDB(c *conf.Bootstrap, provider trace.TracerProvider, meterProvider metric.MeterProvider, logger log.Logger) (DbClient, func(), error) {
 	dbClient := make(map[string]*gorm.DB)
+	buckets := queryBuckets(c.GetData().GetDatabase())
 	for alias, cfg := range c.GetData().GetDatabase() {
 		switch cfg.Driver {
 		case mysql.DefaultDriverName:
-			client, err := newMySQL(cfg, provider)
+			client, err := newMySQL(alias, cfg, buckets, provider, meterProvider, logger)
 			if err != nil {
 				panic(err)
 			}
 			dbClient[alias] = client
 		case PostgreSQLDriverName:
-			client, err := newPostgreSQL(cfg, provider)
+			client, err := newPostgreSQL(alias, cfg, buckets, provider, meterProvider, logger)
 			if err != nil {
 				panic(err)
 			}
@@ -44,7 +47,7 @@ func newDB(c *conf.Bootstrap, provider trace.TracerProvider) (DbClient, func(),
 	}, nil
 }
 
-func newMySQL(cfg *conf.Data_Database, provider trace.TracerProvider) (*gorm.DB, error) {
+func newMySQL(alias string, cfg *conf.Data_Database, buckets []float64, provider trace.TracerProvider, meterProvider metric.MeterProvider, logger log.Logger) (*gorm.DB, error) {
 	conn, err := gorm.Open(mysql.Open(cfg.GetSource()))
 	if err != nil {
 		panic(err)
@@ -63,16 +66,20 @@ func newMySQL(cfg *conf.Data_Database, provider trace.TracerProvider) (*gorm.DB,
 	sqlDb.SetConnMaxLifetime(cfg.GetConnMaxLifetime().AsDuration())
 	sqlDb.SetMaxOpenConns(int(cfg.GetMaxOpenConn()))
 	sqlDb.SetMaxIdleConns(int(cfg.GetMaxIdleConn()))
-	if tcErr := conn.Use(tracing.NewPlugin(tracing.WithTracerProvider(provider))); tcErr != nil {
+	// the pool stats are reported per alias by instrumentDB
+	if tcErr := conn.Use(tracing.NewPlugin(tracing.WithTracerProvider(provider), tracing.WithoutMetrics())); tcErr != nil {
 		return nil, tcErr
 	}
+	if err := instrumentDB(alias, mysql.DefaultDriverName, conn, cfg, buckets, meterProvider, logger); err != nil {
+		return nil, err
+	}
 
 	return conn, nil
 }
 
 const PostgreSQLDriverName = "postgresql"
 
-func newPostgreSQL(cfg *conf.Data_Database, provider trace.TracerProvider) (*gorm.DB, error) {
+func newPostgreSQL(alias string, cfg *conf.Data_Database, buckets []float64, provider trace.TracerProvider, meterProvider metric.MeterProvider, logger log.Logger) (*gorm.DB, error) {
 	conn, err := gorm.Open(postgres.Open(cfg.GetSource()))
 	if err != nil {
 		panic(err)
@@ -91,9 +98,13 @@ func newPostgreSQL(cfg *conf.Data_Database, provider trace.TracerProvider) (*gor
 	sqlDb.SetConnMaxLifetime(cfg.GetConnMaxLifetime().AsDuration())
 	sqlDb.SetMaxOpenConns(int(cfg.GetMaxOpenConn()))
 	sqlDb.SetMaxIdleConns(int(cfg.GetMaxIdleConn()))
-	if tcErr := conn.Use(tracing.NewPlugin(tracing.WithTracerProvider(provider))); tcErr != nil {
+	// the pool stats are reported per alias by instrumentDB
+	if tcErr := conn.Use(tracing.NewPlugin(tracing.WithTracerProvider(provider), tracing.WithoutMetrics())); tcErr != nil {
 		return nil, tcErr
 	}
+	if err := instrumentDB(alias, PostgreSQLDriverName, conn, cfg, buckets, meterProvider, logger); err != nil {
+		return nil, err
+	}
 
 	return conn, nil
 }
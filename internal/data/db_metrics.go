@@ -0,0 +1,214 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gorm.io/gorm"
+)
+
+const (
+	dbMeterName = "gorm.io/plugin/metrics"
+
+	dbStartKey = "metrics:start"
+
+	defaultSlowThreshold = 200 * time.Millisecond
+)
+
+var defaultQueryBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// dbMetrics is the gorm plugin recording the latency and the errors of the
+// queries by table and operation, and logging the slow queries.
+type dbMetrics struct {
+	alias         string
+	slowThreshold time.Duration
+	system        attribute.KeyValue
+	aliasAttr     attribute.KeyValue
+
+	duration metric.Float64Histogram
+	failures metric.Int64Counter
+	log      *log.Helper
+}
+
+// queryBuckets returns the sorted union of the buckets of the aliases, the
+// histogram is shared by the aliases and created with the first buckets only.
+func queryBuckets(databases map[string]*conf.Data_Database) []float64 {
+	var buckets []float64
+	for _, cfg := range databases {
+		b := cfg.GetBuckets()
+		if len(b) == 0 {
+			b = defaultQueryBuckets
+		}
+		buckets = append(buckets, b...)
+	}
+	if len(buckets) == 0 {
+		return defaultQueryBuckets
+	}
+	slices.Sort(buckets)
+	return slices.Compact(buckets)
+}
+
+func newDBMetrics(alias, system string, cfg *conf.Data_Database, buckets []float64, meterProvider metric.MeterProvider, logger log.Logger) (*dbMetrics, error) {
+	meter := meterProvider.Meter(dbMeterName)
+	duration, err := meter.Float64Histogram("db.client.query.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("The duration of the queries by table and operation."),
+		metric.WithExplicitBucketBoundaries(buckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+	failures, err := meter.Int64Counter("db.client.query.errors",
+		metric.WithDescription("The total number of failed queries by table and operation."))
+	if err != nil {
+		return nil, err
+	}
+
+	slowThreshold := defaultSlowThreshold
+	if cfg.GetSlowThreshold().IsValid() {
+		slowThreshold = cfg.GetSlowThreshold().AsDuration()
+	}
+	return &dbMetrics{
+		alias:         alias,
+		slowThreshold: slowThreshold,
+		system:        attribute.String("db.system", system),
+		aliasAttr:     attribute.String("alias", alias),
+		duration:      duration,
+		failures:      failures,
+		log:           log.NewHelper(logger, log.WithMessageKey("db")),
+	}, nil
+}
+
+func (m *dbMetrics) Name() string {
+	return "gorm:metrics"
+}
+
+func (m *dbMetrics) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation string
+		before    func(string, func(*gorm.DB)) error
+		after     func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, h := range hooks {
+		if err := h.before("metrics:before_"+h.operation, m.before); err != nil {
+			return err
+		}
+		if err := h.after("metrics:after_"+h.operation, m.after(h.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *dbMetrics) before(db *gorm.DB) {
+	db.InstanceSet(dbStartKey, time.Now())
+}
+
+func (m *dbMetrics) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(dbStartKey)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(v.(time.Time))
+
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		table := db.Statement.Table
+		attrs := metric.WithAttributes(m.system, m.aliasAttr,
+			attribute.String("table", table),
+			attribute.String("operation", operation),
+		)
+		m.duration.Record(ctx, elapsed.Seconds(), attrs)
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			m.failures.Add(ctx, 1, attrs)
+		}
+
+		if m.slowThreshold > 0 && elapsed > m.slowThreshold {
+			// the vars are left out, they may carry personal data
+			m.log.WithContext(ctx).Warnf("[DB] slow query on %s: %s %s took %s, rows %d: %s",
+				m.alias, operation, table, elapsed, db.RowsAffected, db.Statement.SQL.String())
+		}
+	}
+}
+
+// instrumentDB installs the query metrics and reports the connection pool
+// stats of the alias.
+func instrumentDB(alias, system string, conn *gorm.DB, cfg *conf.Data_Database, buckets []float64, meterProvider metric.MeterProvider, logger log.Logger) error {
+	plugin, err := newDBMetrics(alias, system, cfg, buckets, meterProvider, logger)
+	if err != nil {
+		return err
+	}
+	if err := conn.Use(plugin); err != nil {
+		return err
+	}
+	return reportDBStats(alias, system, conn, meterProvider)
+}
+
+// reportDBStats observes the connection pool of the alias.
+func reportDBStats(alias, system string, db *gorm.DB, meterProvider metric.MeterProvider) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	meter := meterProvider.Meter(dbMeterName)
+	maxOpen, err := meter.Int64ObservableGauge("db.client.connections.max",
+		metric.WithDescription("The maximum number of open connections."))
+	if err != nil {
+		return err
+	}
+	open, err := meter.Int64ObservableGauge("db.client.connections.open",
+		metric.WithDescription("The number of established connections both in use and idle."))
+	if err != nil {
+		return err
+	}
+	inUse, err := meter.Int64ObservableGauge("db.client.connections.in_use",
+		metric.WithDescription("The number of connections currently in use."))
+	if err != nil {
+		return err
+	}
+	idle, err := meter.Int64ObservableGauge("db.client.connections.idle",
+		metric.WithDescription("The number of idle connections."))
+	if err != nil {
+		return err
+	}
+	waitCount, err := meter.Int64ObservableCounter("db.client.connections.wait_count",
+		metric.WithDescription("The total number of connections waited for."))
+	if err != nil {
+		return err
+	}
+	waitDuration, err := meter.Float64ObservableCounter("db.client.connections.wait_duration",
+		metric.WithUnit("s"), metric.WithDescription("The total time blocked waiting for a new connection."))
+	if err != nil {
+		return err
+	}
+
+	attrs := metric.WithAttributes(attribute.String("db.system", system), attribute.String("alias", alias))
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats := sqlDB.Stats()
+		o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), attrs)
+		o.ObserveInt64(open, int64(stats.OpenConnections), attrs)
+		o.ObserveInt64(inUse, int64(stats.InUse), attrs)
+		o.ObserveInt64(idle, int64(stats.Idle), attrs)
+		o.ObserveInt64(waitCount, stats.WaitCount, attrs)
+		o.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds(), attrs)
+		return nil
+	}, maxOpen, open, inUse, idle, waitCount, waitDuration)
+	return err
+}
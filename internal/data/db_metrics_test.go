@@ -0,0 +1,78 @@
+package data
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestQueryBuckets(t *testing.T) {
+	tests := []struct {
+		name      string
+		databases map[string]*conf.Data_Database
+		want      []float64
+	}{
+		{name: "none", want: defaultQueryBuckets},
+		{name: "default", databases: map[string]*conf.Data_Database{"mysql": {}}, want: defaultQueryBuckets},
+		{
+			name: "union",
+			databases: map[string]*conf.Data_Database{
+				"mysql":    {Buckets: []float64{0.5, 0.01}},
+				"postgres": {Buckets: []float64{0.01, 2}},
+			},
+			want: []float64{0.01, 0.5, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryBuckets(tt.databases); !slices.Equal(got, tt.want) {
+				t.Errorf("queryBuckets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDBMetricsBuckets(t *testing.T) {
+	databases := map[string]*conf.Data_Database{
+		"mysql":    {Buckets: []float64{0.1, 1}},
+		"postgres": {Buckets: []float64{0.5}},
+	}
+	buckets := queryBuckets(databases)
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	for alias, cfg := range databases {
+		m, err := newDBMetrics(alias, "mysql", cfg, buckets, provider, log.DefaultLogger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.duration.Record(context.Background(), 0.2, metric.WithAttributes(m.aliasAttr))
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "db.client.query.duration" {
+			continue
+		}
+		points := m.Data.(metricdata.Histogram[float64]).DataPoints
+		if len(points) != len(databases) {
+			t.Fatalf("got %d alias series, want %d", len(points), len(databases))
+		}
+		for _, p := range points {
+			alias, _ := p.Attributes.Value("alias")
+			if !slices.Equal(p.Bounds, []float64{0.1, 0.5, 1}) {
+				t.Errorf("%s bounds = %v, want the union", alias.AsString(), p.Bounds)
+			}
+		}
+		return
+	}
+	t.Fatal("db.client.query.duration not recorded")
+}
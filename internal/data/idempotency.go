@@ -0,0 +1,103 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/idempotency"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	idempotencyKeyPrefix = "idempotency:"
+	// idempotencyPending prefixes the token of the keys in progress, the
+	// results are json objects
+	idempotencyPending = "pending:"
+)
+
+var (
+	errIdempotencyLockLost = errors.New("idempotency lock lost")
+
+	// idempotencySave stores the result only while the key is locked with the token
+	idempotencySave = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+	// idempotencyRelease deletes the key only while it is locked with the token
+	idempotencyRelease = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+)
+
+type idempotencyStore struct {
+	rdb *redis.Client
+}
+
+// NewIdempotencyStore new a redis idempotency.Store, nil when idempotency is not configured.
+func NewIdempotencyStore(c *conf.Bootstrap, data *Data) (idempotency.Store, error) {
+	ic := c.GetIdempotency()
+	if ic.GetRedisAlias() == "" {
+		return nil, nil
+	}
+	rdb := data.rdb.GetRdbClient(ic.GetRedisAlias(), Shard(ic.GetRedisShard()))
+	if rdb == nil {
+		return nil, fmt.Errorf("idempotency redis not found: %s/%d", ic.GetRedisAlias(), ic.GetRedisShard())
+	}
+	return &idempotencyStore{rdb: rdb}, nil
+}
+
+func (s *idempotencyStore) Acquire(ctx context.Context, key string, ttl time.Duration) (string, []byte, error) {
+	key = idempotencyKeyPrefix + key
+	// the token owns the lock, a request outliving the ttl can not save or
+	// release the key of the next one
+	token := uuid.NewString()
+	pending := idempotencyPending + token
+	// the key may expire between SETNX and GET, try once more
+	for i := 0; i < 2; i++ {
+		ok, err := s.rdb.SetNX(ctx, key, pending, ttl).Result()
+		if err != nil {
+			return "", nil, err
+		}
+		if ok {
+			return token, nil, nil
+		}
+		v, err := s.rdb.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if bytes.HasPrefix(v, []byte(idempotencyPending)) {
+			return "", nil, nil
+		}
+		return "", v, nil
+	}
+	return "", nil, nil
+}
+
+func (s *idempotencyStore) Save(ctx context.Context, key, token string, result []byte, ttl time.Duration) error {
+	ok, err := idempotencySave.Run(ctx, s.rdb, []string{idempotencyKeyPrefix + key}, idempotencyPending+token, result, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return errIdempotencyLockLost
+	}
+	return nil
+}
+
+func (s *idempotencyStore) Release(ctx context.Context, key, token string) error {
+	return idempotencyRelease.Run(ctx, s.rdb, []string{idempotencyKeyPrefix + key}, idempotencyPending+token).Err()
+}
@@ -1,11 +1,13 @@
 package data
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/IBM/sarama"
 	"github.com/dnwe/otelsarama"
 	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/trace/kafka"
 	"github.com/go-kratos/kratos/v2/log"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
@@ -46,6 +48,17 @@ func newProducer(bc *conf.Bootstrap, logger log.Logger, textMapPropagator propag
 	}, nil
 }
 
+// Produce sends the message with the request id in ctx to the async producer.
+func (d *Data) Produce(ctx context.Context, message *sarama.ProducerMessage) error {
+	kafka.InjectRequestID(ctx, message)
+	select {
+	case d.producer.Input() <- message:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func newConsumerGroup(bc *conf.Bootstrap) (sarama.ConsumerGroup, func(), error) {
 	kafkaConf := bc.Data.Kafka
 
@@ -0,0 +1,30 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+)
+
+func TestProduceRequestID(t *testing.T) {
+	producer := mocks.NewAsyncProducer(t, mocks.NewTestConfig())
+	defer producer.Close()
+	producer.ExpectInputWithMessageCheckerFunctionAndSucceed(func(m *sarama.ProducerMessage) error {
+		for _, h := range m.Headers {
+			if string(h.Key) == metadata.KeyRequestID && string(h.Value) == "req-1" {
+				return nil
+			}
+		}
+		return fmt.Errorf("headers %v without the request id", m.Headers)
+	})
+
+	d := &Data{producer: producer}
+	ctx := metadata.NewRequestIDContext(context.Background(), "req-1")
+	if err := d.Produce(ctx, &sarama.ProducerMessage{Topic: "greeter"}); err != nil {
+		t.Fatal(err)
+	}
+}
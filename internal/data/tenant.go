@@ -0,0 +1,86 @@
+package data
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const tenantColumn = "tenant_id"
+
+// ErrMissingTenant is a tenant scoped access without a tenant in context.
+var ErrMissingTenant = errors.New("missing tenant in context")
+
+// TenantModel is embedded by the tenant owned models, the tenant is filled
+// from the context on create.
+type TenantModel struct {
+	TenantID string `gorm:"column:tenant_id;index"`
+}
+
+// BeforeCreate implements the gorm hook.
+func (m *TenantModel) BeforeCreate(tx *gorm.DB) error {
+	tenant, ok := metadata.TenantFromContext(tx.Statement.Context)
+	if !ok {
+		return ErrMissingTenant
+	}
+	if m.TenantID != "" && m.TenantID != tenant {
+		return errors.New("tenant mismatch")
+	}
+	m.TenantID = tenant
+	return nil
+}
+
+// TenantScope is a gorm scope limiting the queries, updates and deletes to
+// the tenant in ctx, it fails the statement when ctx carries no tenant.
+func TenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tenant, ok := metadata.TenantFromContext(ctx)
+		if !ok {
+			_ = db.AddError(ErrMissingTenant)
+			return db
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: tenantColumn}, Value: tenant})
+	}
+}
+
+// TenantKey prefixes the redis key with the tenant in ctx.
+func TenantKey(ctx context.Context, key string) (string, error) {
+	tenant, ok := metadata.TenantFromContext(ctx)
+	if !ok {
+		return "", ErrMissingTenant
+	}
+	return "tenant:" + tenant + ":" + key, nil
+}
+
+// DB returns the database of the alias bound to ctx, scoped to the tenant
+// when multi-tenancy is enabled. The repos access the databases through it
+// so they are tenant isolated by default.
+func (d *Data) DB(ctx context.Context, alias string) *gorm.DB {
+	db := d.db.GetDbClient(alias)
+	if db == nil {
+		return nil
+	}
+	db = db.WithContext(ctx)
+	if d.tenancy {
+		db = db.Scopes(TenantScope(ctx))
+	}
+	return db
+}
+
+// Redis returns the redis client of the alias and shard.
+func (d *Data) Redis(alias string, shard Shard) *redis.Client {
+	return d.rdb.GetRdbClient(alias, shard)
+}
+
+// Key returns the redis key, prefixed with the tenant when multi-tenancy is
+// enabled.
+func (d *Data) Key(ctx context.Context, key string) (string, error) {
+	if !d.tenancy {
+		return key, nil
+	}
+	return TenantKey(ctx, key)
+}
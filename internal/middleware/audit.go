@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	auditv1 "github.com/go-kratos/kratos-layout/api/audit/v1"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/audit"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const redactedValue = "[REDACTED]"
+
+type auditRecordKey struct{}
+
+// Audit records who called which operation with which redacted arguments,
+// and the outcome and latency of the call. It is installed before Auth so the
+// rejected calls are recorded too, and AuditCaller fills in the caller.
+func Audit(sink audit.Sink, c *conf.Audit, logger log.Logger) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		if sink == nil || !c.GetEnable() {
+			return handler
+		}
+		exact := make(map[string]struct{})
+		var prefixes []string
+		for _, op := range c.GetOperations() {
+			if prefix, ok := strings.CutSuffix(op, "*"); ok {
+				prefixes = append(prefixes, prefix)
+				continue
+			}
+			exact[op] = struct{}{}
+		}
+		audited := func(operation string) bool {
+			if len(exact) == 0 && len(prefixes) == 0 {
+				return true
+			}
+			if _, ok := exact[operation]; ok {
+				return true
+			}
+			for _, p := range prefixes {
+				if strings.HasPrefix(operation, p) {
+					return true
+				}
+			}
+			return false
+		}
+		r := newRedactor(c.GetRedactFields())
+		helper := log.NewHelper(logger, log.WithMessageKey("audit"))
+
+		return func(ctx context.Context, req any) (reply any, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok || !audited(tr.Operation()) {
+				return handler(ctx, req)
+			}
+
+			start := time.Now()
+			record := &audit.Record{
+				Time:      start,
+				Operation: tr.Operation(),
+				Transport: tr.Kind().String(),
+			}
+			reply, err = handler(context.WithValue(ctx, auditRecordKey{}, record), req)
+			record.LatencyMs = float64(time.Since(start).Microseconds()) / 1e3
+
+			if id, ok := metadata.RequestIDFromContext(ctx); ok {
+				record.RequestID = id
+			}
+			if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+				record.TraceID = sc.TraceID().String()
+			}
+			if !c.GetSkipRequest() {
+				record.Request = r.marshal(req)
+			}
+			record.Code = auditCode(tr.Kind(), err)
+			if err != nil {
+				record.Reason = errors.Reason(err)
+			} else if !c.GetSkipResponse() {
+				record.Response = r.marshal(reply)
+			}
+			if werr := sink.Write(context.WithoutCancel(ctx), record); werr != nil {
+				helper.WithContext(ctx).Errorf("[Audit] write %s: %v", record.Operation, werr)
+			}
+			return reply, err
+		}
+	}
+}
+
+// AuditCaller records the subject and the tenant resolved by Auth and Tenant
+// on the audit record of the call, it is installed after them.
+func AuditCaller() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			if record, ok := ctx.Value(auditRecordKey{}).(*audit.Record); ok {
+				if claims, ok := auth.FromContext(ctx); ok {
+					record.Subject = claims.Subject
+				}
+				if tenant, ok := metadata.TenantFromContext(ctx); ok {
+					record.Tenant = tenant
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// auditCode returns the http status, or the grpc code of the grpc calls.
+func auditCode(kind transport.Kind, err error) int32 {
+	if kind == transport.KindGRPC {
+		if err == nil {
+			return int32(codes.OK)
+		}
+		return int32(errors.FromError(err).GRPCStatus().Code())
+	}
+	if err == nil {
+		return http.StatusOK
+	}
+	return errors.FromError(err).Code
+}
+
+// redactor redacts the sensitive fields of the messages.
+type redactor struct {
+	names map[string]struct{}
+	paths map[string]struct{}
+}
+
+func newRedactor(fields []string) *redactor {
+	r := &redactor{names: make(map[string]struct{}), paths: make(map[string]struct{})}
+	for _, f := range fields {
+		if strings.Contains(f, ".") {
+			r.paths[f] = struct{}{}
+			continue
+		}
+		r.names[f] = struct{}{}
+	}
+	return r
+}
+
+// marshal returns the redacted json of v, nil when v is not a proto message.
+func (r *redactor) marshal(v any) json.RawMessage {
+	m, ok := v.(proto.Message)
+	if !ok || m == nil {
+		return nil
+	}
+	m = proto.Clone(m)
+	r.redact(m.ProtoReflect(), "")
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func (r *redactor) redact(m protoreflect.Message, prefix string) {
+	var redacted []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		path := prefix + string(fd.Name())
+		if r.sensitive(fd, path) {
+			// the message is not mutated while ranging
+			redacted = append(redacted, fd)
+			return true
+		}
+		if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+			return true
+		}
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				r.redact(list.Get(i).Message(), path+".")
+			}
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					r.redact(mv.Message(), path+".")
+					return true
+				})
+			}
+		default:
+			r.redact(v.Message(), path+".")
+		}
+		return true
+	})
+	for _, fd := range redacted {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			m.Set(fd, protoreflect.ValueOfString(redactedValue))
+			continue
+		}
+		m.Clear(fd)
+	}
+}
+
+func (r *redactor) sensitive(fd protoreflect.FieldDescriptor, path string) bool {
+	if _, ok := r.names[string(fd.Name())]; ok {
+		return true
+	}
+	if _, ok := r.paths[path]; ok {
+		return true
+	}
+	if opts := fd.Options(); opts != nil {
+		if v, ok := proto.GetExtension(opts, auditv1.E_Sensitive).(bool); ok && v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	auditv1 "github.com/go-kratos/kratos-layout/api/audit/v1"
+	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/audit"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type memoryAuditSink struct {
+	records []*audit.Record
+}
+
+func (s *memoryAuditSink) Write(_ context.Context, r *audit.Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+// sensitiveMessage returns a message whose password field is marked with
+// (audit.v1.sensitive).
+func sensitiveMessage(t *testing.T) protoreflect.Message {
+	t.Helper()
+	opts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(opts, auditv1.E_Sensitive, true)
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("audit_test.proto"),
+		Package: proto.String("audit.test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Login"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("user"), JsonName: proto.String("user"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("password"), JsonName: proto.String("password"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Options: opts},
+			},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := dynamicpb.NewMessage(fd.Messages().ByName("Login"))
+	m.Set(m.Descriptor().Fields().ByName("user"), protoreflect.ValueOfString("kratos"))
+	m.Set(m.Descriptor().Fields().ByName("password"), protoreflect.ValueOfString("p@ss"))
+	return m
+}
+
+func TestRedactor(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		msg    proto.Message
+		want   string
+	}{
+		{
+			name: "not a message",
+			msg:  nil,
+		},
+		{
+			name:   "name at any depth in lists",
+			fields: []string{"secret"},
+			msg: &conf.Auth{Jwt: &conf.Auth_JWT{Issuer: "kratos", Keys: []*conf.Auth_Key{
+				{Kid: "a", Secret: "s1"}, {Kid: "b", Secret: "s2"},
+			}}},
+			want: `{"jwt":{"keys":[{"kid":"a","secret":"[REDACTED]"},{"kid":"b","secret":"[REDACTED]"}],"issuer":"kratos"}}`,
+		},
+		{
+			name:   "dotted path from the root",
+			fields: []string{"jwt.issuer"},
+			msg:    &conf.Auth{Jwt: &conf.Auth_JWT{Issuer: "kratos", Keys: []*conf.Auth_Key{{Kid: "a"}}}},
+			want:   `{"jwt":{"keys":[{"kid":"a"}],"issuer":"[REDACTED]"}}`,
+		},
+		{
+			name:   "path does not match another depth",
+			fields: []string{"issuer"},
+			msg:    &conf.Auth{Jwt: &conf.Auth_JWT{Audience: []string{"api"}}},
+			want:   `{"jwt":{"audience":["api"]}}`,
+		},
+		{
+			name:   "repeated scalars are cleared",
+			fields: []string{"audience"},
+			msg:    &conf.Auth{Jwt: &conf.Auth_JWT{Issuer: "kratos", Audience: []string{"api"}}},
+			want:   `{"jwt":{"issuer":"kratos"}}`,
+		},
+		{
+			name:   "map values",
+			fields: []string{"source"},
+			msg:    &conf.Data{Database: map[string]*conf.Data_Database{"mysql": {Driver: "mysql", Source: "root:pwd@tcp(mysql:3306)/db"}}},
+			want:   `{"database":{"mysql":{"driver":"mysql","source":"[REDACTED]"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newRedactor(tt.fields).marshal(tt.msg)
+			if !jsonEqual(t, got, tt.want) {
+				t.Errorf("marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("sensitive option", func(t *testing.T) {
+		m := sensitiveMessage(t)
+		got := newRedactor(nil).marshal(m.Interface())
+		if want := `{"user":"kratos","password":"[REDACTED]"}`; !jsonEqual(t, got, want) {
+			t.Errorf("marshal() = %s, want %s", got, want)
+		}
+		// the message of the handler is not mutated
+		if v := m.Get(m.Descriptor().Fields().ByName("password")).String(); v != "p@ss" {
+			t.Errorf("password = %q, the request is mutated", v)
+		}
+	})
+}
+
+func jsonEqual(t *testing.T, got json.RawMessage, want string) bool {
+	t.Helper()
+	if want == "" {
+		return got == nil
+	}
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	return string(gb) == string(wb)
+}
+
+func TestAudit(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *conf.Audit
+		kind    transport.Kind
+		auth    bool
+		handler middleware.Handler
+		record  *audit.Record
+	}{
+		{
+			name:    "http success",
+			conf:    &conf.Audit{Enable: true},
+			auth:    true,
+			handler: func(context.Context, any) (any, error) { return &v1.HelloReply{Message: "Hello kratos"}, nil },
+			record:  &audit.Record{Transport: "http", Subject: "alice", Tenant: "acme", Code: http.StatusOK, Response: json.RawMessage(`{"message":"Hello kratos"}`)},
+		},
+		{
+			name:    "grpc success",
+			conf:    &conf.Audit{Enable: true, SkipResponse: true},
+			kind:    transport.KindGRPC,
+			auth:    true,
+			handler: func(context.Context, any) (any, error) { return &v1.HelloReply{Message: "Hello kratos"}, nil },
+			record:  &audit.Record{Transport: "grpc", Subject: "alice", Tenant: "acme", Code: int32(codes.OK)},
+		},
+		{
+			name:    "grpc error",
+			conf:    &conf.Audit{Enable: true},
+			kind:    transport.KindGRPC,
+			auth:    true,
+			handler: func(context.Context, any) (any, error) { return nil, kerrors.NotFound("USER_NOT_FOUND", "") },
+			record:  &audit.Record{Transport: "grpc", Subject: "alice", Tenant: "acme", Code: int32(codes.NotFound), Reason: "USER_NOT_FOUND"},
+		},
+		{
+			name:   "rejected by auth",
+			conf:   &conf.Audit{Enable: true},
+			record: &audit.Record{Transport: "http", Code: http.StatusUnauthorized, Reason: reasonUnauthorized},
+		},
+		{
+			name:    "operation not audited",
+			conf:    &conf.Audit{Enable: true, Operations: []string{"/helloworld.v1.Greeter/Other*"}},
+			handler: func(context.Context, any) (any, error) { return nil, nil },
+		},
+		{
+			name:    "operation prefix",
+			conf:    &conf.Audit{Enable: true, Operations: []string{"/helloworld.v1.Greeter/*"}, SkipRequest: true},
+			auth:    true,
+			handler: func(context.Context, any) (any, error) { return nil, nil },
+			record:  &audit.Record{Transport: "http", Subject: "alice", Tenant: "acme", Code: http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &memoryAuditSink{}
+			// the chain of the servers: Audit, Auth, Tenant, AuditCaller
+			authenticate := func(handler middleware.Handler) middleware.Handler {
+				return func(ctx context.Context, req any) (any, error) {
+					if !tt.auth {
+						return nil, ErrMissingCredentials
+					}
+					ctx = auth.NewContext(ctx, &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "alice"}})
+					return handler(metadata.NewTenantContext(ctx, "acme"), req)
+				}
+			}
+			h := middleware.Chain(Audit(sink, tt.conf, log.DefaultLogger), authenticate, AuditCaller())(tt.handler)
+
+			tr := &testTransport{kind: tt.kind, operation: sayHello, request: headerCarrier{}, reply: headerCarrier{}}
+			ctx := metadata.NewRequestIDContext(transport.NewServerContext(context.Background(), tr), "req-1")
+			_, _ = h(ctx, &v1.HelloRequest{Name: "kratos"})
+
+			if tt.record == nil {
+				if len(sink.records) != 0 {
+					t.Fatalf("got %d records, want none", len(sink.records))
+				}
+				return
+			}
+			if len(sink.records) != 1 {
+				t.Fatalf("got %d records, want 1", len(sink.records))
+			}
+			got := sink.records[0]
+			if got.Operation != sayHello || got.RequestID != "req-1" || got.Time.IsZero() {
+				t.Errorf("record = %+v, want the operation, the request id and the time", got)
+			}
+			if got.Transport != tt.record.Transport || got.Subject != tt.record.Subject || got.Tenant != tt.record.Tenant ||
+				got.Code != tt.record.Code || got.Reason != tt.record.Reason {
+				t.Errorf("record = %+v, want %+v", got, tt.record)
+			}
+			if wantRequest := !tt.conf.GetSkipRequest(); (got.Request != nil) != wantRequest {
+				t.Errorf("request = %s, want recorded %v", got.Request, wantRequest)
+			}
+			if !jsonEqual(t, got.Response, string(tt.record.Response)) {
+				t.Errorf("response = %s, want %s", got.Response, tt.record.Response)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos-layout/pkg/signature"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	reasonUnauthorized = "UNAUTHORIZED"
+
+	defaultAPIKeyHeader = "X-Api-Key"
+	defaultHMACMaxSkew  = 5 * time.Minute
+)
+
+var (
+	// ErrMissingCredentials is the request carries no access token, api key or signature.
+	ErrMissingCredentials = kerrors.Unauthorized(reasonUnauthorized, "missing credentials")
+	// ErrInvalidToken is the access token is invalid or expired.
+	ErrInvalidToken = kerrors.Unauthorized(reasonUnauthorized, "invalid access token")
+	// ErrInvalidAPIKey is the api key is unknown or revoked.
+	ErrInvalidAPIKey = kerrors.Unauthorized(reasonUnauthorized, "invalid api key")
+	// ErrInvalidSignature is the request signature is invalid or expired.
+	ErrInvalidSignature = kerrors.Unauthorized(reasonUnauthorized, "invalid signature")
+	// ErrReplayedRequest is the signed request has been seen before.
+	ErrReplayedRequest = kerrors.Unauthorized("REPLAYED_REQUEST", "replayed request")
+	// ErrInsufficientScope is the caller is not granted the required scopes.
+	ErrInsufficientScope = kerrors.Forbidden("INSUFFICIENT_SCOPE", "insufficient scope")
+)
+
+// Authenticator verifies the JWT access tokens, api keys and signed requests
+// against the operation policies.
+type Authenticator struct {
+	keys   *keySet
+	parser *jwt.Parser
+
+	audience []string
+
+	apiKeyHeader string
+	apiKeys      map[string]*auth.APIKey
+	apiKeyStore  auth.APIKeyStore
+
+	hmacClients map[string]*conf.Auth_HMACClient
+	hmacMaxSkew time.Duration
+	nonces      auth.NonceStore
+
+	exact    map[string]*conf.Auth_Policy
+	prefixes []*conf.Auth_Policy
+	access   conf.Auth_Access
+}
+
+// NewAuthenticator new an Authenticator, nil when auth is not configured.
+func NewAuthenticator(bc *conf.Bootstrap, apiKeyStore auth.APIKeyStore, nonces auth.NonceStore) (*Authenticator, error) {
+	c := bc.GetAuth()
+	if c == nil {
+		return nil, nil
+	}
+
+	a := &Authenticator{
+		apiKeyHeader: defaultAPIKeyHeader,
+		apiKeys:      make(map[string]*auth.APIKey),
+		apiKeyStore:  apiKeyStore,
+		hmacClients:  make(map[string]*conf.Auth_HMACClient),
+		hmacMaxSkew:  defaultHMACMaxSkew,
+		nonces:       nonces,
+		exact:        make(map[string]*conf.Auth_Policy),
+		access:       c.GetDefaultAccess(),
+	}
+	for _, p := range c.GetPolicies() {
+		if prefix, ok := strings.CutSuffix(p.GetOperation(), "*"); ok {
+			a.prefixes = append(a.prefixes, &conf.Auth_Policy{Operation: prefix, Access: p.GetAccess(), Scopes: p.GetScopes()})
+			continue
+		}
+		a.exact[p.GetOperation()] = p
+	}
+
+	if h := c.GetApiKeys().GetHeader(); h != "" {
+		a.apiKeyHeader = h
+	}
+	for _, k := range c.GetApiKeys().GetKeys() {
+		a.apiKeys[strings.ToLower(k.GetHash())] = &auth.APIKey{ID: k.GetId(), Scopes: k.GetScopes()}
+	}
+
+	if hc := c.GetHmac(); len(hc.GetClients()) > 0 {
+		if nonces == nil {
+			return nil, errors.New("auth hmac requires a redis for the nonces")
+		}
+		for _, client := range hc.GetClients() {
+			a.hmacClients[client.GetKeyId()] = client
+		}
+		if hc.GetMaxSkew().AsDuration() > 0 {
+			a.hmacMaxSkew = hc.GetMaxSkew().AsDuration()
+		}
+	}
+
+	jc := c.GetJwt()
+	if jc == nil {
+		return a, nil
+	}
+	keys, err := newKeySet(jc)
+	if err != nil {
+		return nil, err
+	}
+	a.keys = keys
+	a.audience = jc.GetAudience()
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+	}
+	if jc.GetIssuer() != "" {
+		opts = append(opts, jwt.WithIssuer(jc.GetIssuer()))
+	}
+	if jc.GetLeeway().AsDuration() > 0 {
+		opts = append(opts, jwt.WithLeeway(jc.GetLeeway().AsDuration()))
+	}
+	a.parser = jwt.NewParser(opts...)
+	return a, nil
+}
+
+// policy returns the policy of the operation, the longest prefix wins.
+func (a *Authenticator) policy(operation string) *conf.Auth_Policy {
+	if p, ok := a.exact[operation]; ok {
+		return p
+	}
+	var match *conf.Auth_Policy
+	for _, p := range a.prefixes {
+		if strings.HasPrefix(operation, p.GetOperation()) && len(p.GetOperation()) >= len(match.GetOperation()) {
+			match = p
+		}
+	}
+	if match != nil {
+		return match
+	}
+	return &conf.Auth_Policy{Operation: operation, Access: a.access}
+}
+
+// Verify parses and validates the access token.
+func (a *Authenticator) Verify(token string) (*auth.Claims, error) {
+	if a.parser == nil {
+		return nil, ErrInvalidToken
+	}
+	claims := &auth.Claims{}
+	if _, err := a.parser.ParseWithClaims(token, claims, a.keys.Keyfunc); err != nil {
+		return nil, ErrInvalidToken.WithCause(err)
+	}
+	if len(a.audience) > 0 {
+		ok := false
+		for _, aud := range a.audience {
+			for _, v := range claims.Audience {
+				ok = ok || aud == v
+			}
+		}
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+	}
+	return claims, nil
+}
+
+// VerifyAPIKey looks up the api key by its sha256 in config, then in the store.
+func (a *Authenticator) VerifyAPIKey(ctx context.Context, key string) (*auth.Claims, error) {
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])
+
+	k, ok := a.apiKeys[hash]
+	if !ok && a.apiKeyStore != nil {
+		var err error
+		if k, err = a.apiKeyStore.FindAPIKey(ctx, hash); err != nil {
+			return nil, ErrInvalidAPIKey.WithCause(err)
+		}
+		ok = k != nil
+	}
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	return &auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "apikey:" + k.ID},
+		Scp:              k.Scopes,
+	}, nil
+}
+
+// VerifySignature verifies the HMAC signature of the http request, and
+// rejects the replayed nonces.
+func (a *Authenticator) VerifySignature(ctx context.Context, header transport.Header) (*auth.Claims, error) {
+	keyID := header.Get(signature.HeaderKeyID)
+	client, ok := a.hmacClients[keyID]
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	timestamp := header.Get(signature.HeaderTimestamp)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if skew := time.Since(time.Unix(ts, 0)); skew > a.hmacMaxSkew || skew < -a.hmacMaxSkew {
+		return nil, ErrInvalidSignature
+	}
+
+	r, ok := http.RequestFromServerContext(ctx)
+	if !ok {
+		// only the http requests are signed
+		return nil, ErrInvalidSignature
+	}
+	body, _ := metadata.GetHttpRequestBody(ctx)
+	nonce := header.Get(signature.HeaderNonce)
+	if nonce == "" {
+		return nil, ErrInvalidSignature
+	}
+	s := signature.StringToSign(r.Method, r.URL.RequestURI(), timestamp, nonce, body)
+	if !signature.Verify(client.GetSecret(), s, header.Get(signature.HeaderSignature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	// the nonce outlives the accepted timestamps
+	fresh, err := a.nonces.Remember(ctx, keyID, nonce, 2*a.hmacMaxSkew)
+	if err != nil {
+		return nil, ErrInvalidSignature.WithCause(err)
+	}
+	if !fresh {
+		return nil, ErrReplayedRequest
+	}
+	return &auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "hmac:" + keyID},
+		Scp:              client.GetScopes(),
+	}, nil
+}
+
+// authenticate returns the claims of the credentials carried by the request,
+// nil when there is none.
+func (a *Authenticator) authenticate(ctx context.Context) (*auth.Claims, error) {
+	header, ok := metadata.GetHeader(ctx)
+	if !ok {
+		return nil, nil
+	}
+	switch {
+	case header.Get(metadata.KeyAuthorization) != "":
+		token, err := metadata.GetAccessToken(ctx)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		return a.Verify(token)
+	case header.Get(a.apiKeyHeader) != "":
+		return a.VerifyAPIKey(ctx, header.Get(a.apiKeyHeader))
+	case header.Get(signature.HeaderSignature) != "":
+		return a.VerifySignature(ctx, header)
+	}
+	return nil, nil
+}
+
+// Auth authenticates the requests, and puts the claims into context.
+func Auth(a *Authenticator) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		if a == nil {
+			return handler
+		}
+		return func(ctx context.Context, req any) (reply any, err error) {
+			var operation string
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				operation = tr.Operation()
+			}
+			p := a.policy(operation)
+
+			claims, err := a.authenticate(ctx)
+			if p.GetAccess() == conf.Auth_PUBLIC {
+				// the claims of valid credentials are still available to public operations
+				if err == nil && claims != nil {
+					ctx = auth.NewContext(ctx, claims)
+				}
+				return handler(ctx, req)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+			if claims == nil {
+				return nil, ErrMissingCredentials
+			}
+			for _, scope := range p.GetScopes() {
+				if !claims.HasScope(scope) {
+					return nil, ErrInsufficientScope
+				}
+			}
+			return handler(auth.NewContext(ctx, claims), req)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	etcdconfig "github.com/go-kratos/kratos/contrib/config/etcd/v2"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/file"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	etcdclient "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// authzKey is the root key of the policy documents, laid out like config.yaml.
+const authzKey = "authz"
+
+type (
+	// Authorizer maps the operations to the roles, permissions and request
+	// conditions they require, the policies are hot reloaded.
+	Authorizer struct {
+		table atomic.Pointer[policyTable]
+		log   *log.Helper
+	}
+
+	policyTable struct {
+		effect   conf.Authz_Effect
+		exact    map[string]*compiledPolicy
+		prefixes []*compiledPolicy
+	}
+
+	compiledPolicy struct {
+		operation   string
+		roles       []string
+		permissions []string
+		conditions  []*compiledCondition
+	}
+
+	compiledCondition struct {
+		field  string
+		path   []string
+		op     conf.Authz_Operator
+		values []string
+		claim  string
+		re     []*regexp.Regexp
+	}
+)
+
+func permissionDenied(operation, format string, args ...any) *errors.Error {
+	return errors.Forbidden(v1.ErrorReason_PERMISSION_DENIED.String(), fmt.Sprintf(format, args...)).
+		WithMetadata(map[string]string{"operation": operation})
+}
+
+// NewAuthorizer new an Authorizer, nil when authz is not configured.
+func NewAuthorizer(bc *conf.Bootstrap, logger log.Logger) (*Authorizer, func(), error) {
+	c := bc.GetAuthz()
+	if c == nil {
+		return nil, func() {}, nil
+	}
+	a := &Authorizer{log: log.NewHelper(logger, log.WithMessageKey("authz"))}
+
+	var source config.Source
+	var closeSource func()
+	switch {
+	case c.GetFile() != "":
+		source = file.NewSource(c.GetFile())
+	case c.GetEtcdKey() != "":
+		client, err := etcdclient.New(etcdclient.Config{Endpoints: bc.GetRegistry().GetEndpoint()})
+		if err != nil {
+			return nil, nil, err
+		}
+		closeSource = func() { _ = client.Close() }
+		if source, err = etcdconfig.New(client, etcdconfig.WithPath(c.GetEtcdKey())); err != nil {
+			closeSource()
+			return nil, nil, err
+		}
+	default:
+		table, err := compilePolicies(c)
+		if err != nil {
+			return nil, nil, err
+		}
+		a.table.Store(table)
+		return a, func() {}, nil
+	}
+
+	cfg := config.New(config.WithSource(source))
+	cleanup := func() {
+		_ = cfg.Close()
+		if closeSource != nil {
+			closeSource()
+		}
+	}
+	if err := cfg.Load(); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if err := a.reload(cfg.Value(authzKey)); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	// watch the whole document, so the keys absent at startup are reloaded too
+	err := cfg.Watch(authzKey, func(_ string, v config.Value) {
+		if err := a.reload(v); err != nil {
+			a.log.Errorf("[Authz] reload policies failed, keep the previous ones: %v", err)
+			return
+		}
+		a.log.Info("[Authz] policies reloaded")
+	})
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return a, cleanup, nil
+}
+
+func (a *Authorizer) reload(v config.Value) error {
+	var c conf.Authz
+	if err := v.Scan(&c); err != nil {
+		return fmt.Errorf("authz policies: %w", err)
+	}
+	table, err := compilePolicies(&c)
+	if err != nil {
+		return err
+	}
+	a.table.Store(table)
+	return nil
+}
+
+func compilePolicies(c *conf.Authz) (*policyTable, error) {
+	t := &policyTable{
+		effect: c.GetDefaultEffect(),
+		exact:  make(map[string]*compiledPolicy),
+	}
+	for _, p := range c.GetPolicies() {
+		cp := &compiledPolicy{
+			operation:   p.GetOperation(),
+			roles:       p.GetRoles(),
+			permissions: p.GetPermissions(),
+		}
+		for _, cond := range p.GetConditions() {
+			cc := &compiledCondition{
+				field:  cond.GetField(),
+				path:   strings.Split(cond.GetField(), "."),
+				op:     cond.GetOp(),
+				values: cond.GetValues(),
+				claim:  cond.GetClaim(),
+			}
+			if cc.op == conf.Authz_REGEX {
+				for _, v := range cc.values {
+					re, err := regexp.Compile(v)
+					if err != nil {
+						return nil, fmt.Errorf("authz policy %s: %w", p.GetOperation(), err)
+					}
+					cc.re = append(cc.re, re)
+				}
+			}
+			cp.conditions = append(cp.conditions, cc)
+		}
+		if prefix, ok := strings.CutSuffix(cp.operation, "*"); ok {
+			cp.operation = prefix
+			t.prefixes = append(t.prefixes, cp)
+			continue
+		}
+		t.exact[cp.operation] = cp
+	}
+	// longest prefix first
+	sort.Slice(t.prefixes, func(i, j int) bool {
+		return len(t.prefixes[i].operation) > len(t.prefixes[j].operation)
+	})
+	return t, nil
+}
+
+func (t *policyTable) lookup(operation string) *compiledPolicy {
+	if p, ok := t.exact[operation]; ok {
+		return p
+	}
+	for _, p := range t.prefixes {
+		if strings.HasPrefix(operation, p.operation) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Authorize returns a PERMISSION_DENIED error when the caller in ctx is not
+// allowed to call the operation with req.
+func (a *Authorizer) Authorize(ctx context.Context, operation string, req any) error {
+	t := a.table.Load()
+	p := t.lookup(operation)
+	if p == nil {
+		if t.effect == conf.Authz_DENY {
+			return permissionDenied(operation, "no policy")
+		}
+		return nil
+	}
+
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return permissionDenied(operation, "unauthenticated")
+	}
+	if len(p.roles) > 0 && !slices.ContainsFunc(p.roles, claims.HasRole) {
+		return permissionDenied(operation, "missing role")
+	}
+	for _, perm := range p.permissions {
+		if !claims.HasPermission(perm) {
+			return permissionDenied(operation, "missing permission: %s", perm)
+		}
+	}
+	if len(p.conditions) > 0 {
+		m, ok := req.(proto.Message)
+		if !ok {
+			return permissionDenied(operation, "unexpected request")
+		}
+		for _, cond := range p.conditions {
+			if !cond.match(m.ProtoReflect(), claims) {
+				return permissionDenied(operation, "condition not satisfied: %s", cond.field)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *compiledCondition) match(m protoreflect.Message, claims *auth.Claims) bool {
+	values, ok := fieldValues(m, c.path)
+	if !ok || len(values) == 0 {
+		return false
+	}
+	targets := c.values
+	if c.claim != "" {
+		v := claimValue(claims, c.claim)
+		if v == "" {
+			return false
+		}
+		targets = []string{v}
+	}
+	for _, v := range values {
+		if c.test(v, targets) == (c.op == conf.Authz_NE || c.op == conf.Authz_NOT_IN) {
+			return false
+		}
+	}
+	return true
+}
+
+// test reports whether v matches any target, NE and NOT_IN negate the result.
+func (c *compiledCondition) test(v string, targets []string) bool {
+	switch c.op {
+	case conf.Authz_PREFIX:
+		return slices.ContainsFunc(targets, func(t string) bool { return strings.HasPrefix(v, t) })
+	case conf.Authz_REGEX:
+		return slices.ContainsFunc(c.re, func(re *regexp.Regexp) bool { return re.MatchString(v) })
+	default:
+		return slices.Contains(targets, v)
+	}
+}
+
+func claimValue(claims *auth.Claims, name string) string {
+	switch name {
+	case "sub":
+		return claims.Subject
+	case "iss":
+		return claims.Issuer
+	case "jti":
+		return claims.ID
+	case "tenant_id":
+		return claims.TenantID
+	}
+	return ""
+}
+
+// fieldValues returns the string values of the field path, the repeated
+// fields return every element.
+func fieldValues(m protoreflect.Message, path []string) ([]string, bool) {
+	for i, name := range path {
+		fields := m.Descriptor().Fields()
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = fields.ByJSONName(name)
+		}
+		if fd == nil {
+			return nil, false
+		}
+		if i < len(path)-1 {
+			if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+				return nil, false
+			}
+			m = m.Get(fd).Message()
+			continue
+		}
+		if fd.IsMap() || fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+			return nil, false
+		}
+		v := m.Get(fd)
+		if !fd.IsList() {
+			return []string{scalarString(fd, v)}, true
+		}
+		list := v.List()
+		values := make([]string, 0, list.Len())
+		for j := 0; j < list.Len(); j++ {
+			values = append(values, scalarString(fd, list.Get(j)))
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+func scalarString(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	if fd.Kind() == protoreflect.EnumKind {
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// Authz authorizes the authenticated requests.
+func Authz(a *Authorizer) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		if a == nil {
+			return handler
+		}
+		return func(ctx context.Context, req any) (reply any, err error) {
+			var operation string
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				operation = tr.Operation()
+			}
+			if err = a.Authorize(ctx, operation, req); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+const sayHello = "/helloworld.v1.Greeter/SayHello"
+
+func TestAuthorizerReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authz.yaml")
+	// the policies are absent at startup
+	if err := os.WriteFile(path, []byte("authz:\n  default_effect: ALLOW\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a, cleanup, err := NewAuthorizer(&conf.Bootstrap{Authz: &conf.Authz{File: path}}, log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	ctx := auth.NewContext(context.Background(), &auth.Claims{Roles: []string{"user"}})
+	req := &v1.HelloRequest{Name: "kratos"}
+	if err := a.Authorize(ctx, sayHello, req); err != nil {
+		t.Fatalf("Authorize() before reload = %v", err)
+	}
+
+	doc := "authz:\n  default_effect: DENY\n  policies:\n    - operation: /helloworld.v1.Greeter/*\n      roles: [ admin ]\n"
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for a.Authorize(ctx, sayHello, req) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("policies not reloaded")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err := a.Authorize(ctx, "/helloworld.v1.Other/Call", req); err == nil {
+		t.Error("default_effect DENY not reloaded")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	table, err := compilePolicies(&conf.Authz{
+		DefaultEffect: conf.Authz_DENY,
+		Policies: []*conf.Authz_Policy{
+			{Operation: sayHello, Permissions: []string{"greeter:read"}, Conditions: []*conf.Authz_Condition{
+				{Field: "name", Op: conf.Authz_NOT_IN, Values: []string{"root"}},
+			}},
+			{Operation: "/helloworld.v1.Greeter/*", Roles: []string{"admin"}},
+			{Operation: "/helloworld.v1.Tenant/*", Conditions: []*conf.Authz_Condition{
+				{Field: "name", Op: conf.Authz_EQ, Claim: "sub"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &Authorizer{}
+	a.table.Store(table)
+
+	reader := &auth.Claims{Permissions: []string{"greeter:read"}}
+	reader.Subject = "kratos"
+	tests := []struct {
+		name      string
+		claims    *auth.Claims
+		operation string
+		req       string
+		allowed   bool
+	}{
+		{name: "permission", claims: reader, operation: sayHello, req: "kratos", allowed: true},
+		{name: "condition", claims: reader, operation: sayHello, req: "root"},
+		{name: "unauthenticated", operation: sayHello, req: "kratos"},
+		{name: "missing role", claims: reader, operation: "/helloworld.v1.Greeter/Other", req: "kratos"},
+		{name: "role", claims: &auth.Claims{Roles: []string{"admin"}}, operation: "/helloworld.v1.Greeter/Other", allowed: true},
+		{name: "claim", claims: reader, operation: "/helloworld.v1.Tenant/Get", req: "kratos", allowed: true},
+		{name: "claim mismatch", claims: reader, operation: "/helloworld.v1.Tenant/Get", req: "other"},
+		{name: "no policy", claims: reader, operation: "/helloworld.v1.Other/Call"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = auth.NewContext(ctx, tt.claims)
+			}
+			err := a.Authorize(ctx, tt.operation, &v1.HelloRequest{Name: tt.req})
+			if (err == nil) != tt.allowed {
+				t.Errorf("Authorize() = %v, want allowed %v", err, tt.allowed)
+			}
+		})
+	}
+}
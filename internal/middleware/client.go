@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+	"github.com/go-kratos/kratos/v2/middleware/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Client returns the middlewares of the outgoing gRPC/HTTP clients, so the
+// trace context and the request id follow the call to the next service.
+// eg: grpc.DialInsecure(ctx, grpc.WithEndpoint(endpoint), grpc.WithMiddleware(middleware.Client(tp)...))
+func Client(tp trace.TracerProvider) []middleware.Middleware {
+	return []middleware.Middleware{
+		recovery.Recovery(),
+		tracing.Client(tracing.WithTracerProvider(tp)),
+		RequestIDClient(),
+	}
+}
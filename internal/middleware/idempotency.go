@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	"github.com/go-kratos/kratos-layout/pkg/idempotency"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	defaultIdempotencyHeader  = "Idempotency-Key"
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyLockTTL = 30 * time.Second
+	maxIdempotencyKeyLength   = 255
+
+	// IdempotentReplayedHeader is set on the replies replayed from a previous request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+var (
+	// ErrIdempotencyKeyRequired is the operation requires an idempotency key.
+	ErrIdempotencyKeyRequired = kerrors.BadRequest(v1.ErrorReason_IDEMPOTENCY_KEY_INVALID.String(), "idempotency key required")
+	// ErrIdempotencyInProgress is a request with the same key is still in progress.
+	ErrIdempotencyInProgress = kerrors.Conflict(v1.ErrorReason_IDEMPOTENCY_IN_PROGRESS.String(), "a request with the same idempotency key is in progress")
+	// ErrIdempotencyKeyReused is the key has been used with another request.
+	ErrIdempotencyKeyReused = kerrors.New(http.StatusUnprocessableEntity, v1.ErrorReason_IDEMPOTENCY_KEY_REUSED.String(), "idempotency key reused with a different request")
+	// ErrIdempotencyUnavailable is the idempotency store can not be reached.
+	ErrIdempotencyUnavailable = kerrors.ServiceUnavailable(v1.ErrorReason_IDEMPOTENCY_UNAVAILABLE.String(), "idempotency store unavailable")
+	// ErrIdempotencyResultCorrupted is the stored result can not be replayed.
+	ErrIdempotencyResultCorrupted = kerrors.InternalServer(v1.ErrorReason_IDEMPOTENCY_RESULT_CORRUPTED.String(), "corrupted idempotent result")
+)
+
+// idempotentResult is the stored reply or error of a request.
+type idempotentResult struct {
+	// Fingerprint is the sha256 of the request, a key must not be reused with another request.
+	Fingerprint string            `json:"fingerprint"`
+	Reply       []byte            `json:"reply,omitempty"`
+	Code        int32             `json:"code,omitempty"`
+	Reason      string            `json:"reason,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Idempotency replays the result of the configured operations for the
+// requests carrying the same idempotency key. Only the replies and the client
+// errors are stored, the server errors release the key to allow a retry.
+func Idempotency(store idempotency.Store, c *conf.Idempotency) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		if store == nil || len(c.GetOperations()) == 0 {
+			return handler
+		}
+		operations := make(map[string]struct{}, len(c.GetOperations()))
+		for _, op := range c.GetOperations() {
+			operations[op] = struct{}{}
+		}
+		header := defaultIdempotencyHeader
+		if c.GetHeader() != "" {
+			header = c.GetHeader()
+		}
+		ttl := defaultIdempotencyTTL
+		if c.GetTtl().AsDuration() > 0 {
+			ttl = c.GetTtl().AsDuration()
+		}
+		lockTTL := defaultIdempotencyLockTTL
+		if c.GetLockTtl().AsDuration() > 0 {
+			lockTTL = c.GetLockTtl().AsDuration()
+		}
+
+		return func(ctx context.Context, req any) (reply any, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			if _, ok = operations[tr.Operation()]; !ok {
+				return handler(ctx, req)
+			}
+			key := tr.RequestHeader().Get(header)
+			if key == "" {
+				if c.GetRequired() {
+					return nil, ErrIdempotencyKeyRequired
+				}
+				return handler(ctx, req)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return nil, kerrors.BadRequest(v1.ErrorReason_IDEMPOTENCY_KEY_INVALID.String(), "idempotency key too long")
+			}
+
+			fingerprint, err := requestFingerprint(req)
+			if err != nil {
+				return handler(ctx, req)
+			}
+			// the keys are scoped to the tenant, the caller and the operation
+			var subject string
+			if claims, ok := auth.FromContext(ctx); ok {
+				subject = claims.Subject
+			}
+			tenant, _ := metadata.TenantFromContext(ctx)
+			storeKey := tr.Operation() + ":" + tenant + ":" + subject + ":" + key
+
+			token, stored, err := store.Acquire(ctx, storeKey, lockTTL)
+			if err != nil {
+				return nil, ErrIdempotencyUnavailable.WithCause(err)
+			}
+			if token == "" {
+				if stored == nil {
+					return nil, ErrIdempotencyInProgress
+				}
+				return replay(tr, stored, fingerprint)
+			}
+
+			reply, err = handler(ctx, req)
+			result, ok := newIdempotentResult(fingerprint, reply, err)
+			if !ok {
+				_ = store.Release(context.WithoutCancel(ctx), storeKey, token)
+				return reply, err
+			}
+			data, merr := json.Marshal(result)
+			if merr != nil {
+				_ = store.Release(context.WithoutCancel(ctx), storeKey, token)
+				return reply, err
+			}
+			// the reply is already made, a failed save only loses the replay, the
+			// lock expired and taken by another request is not overwritten
+			_ = store.Save(context.WithoutCancel(ctx), storeKey, token, data, ttl)
+			return reply, err
+		}
+	}
+}
+
+func requestFingerprint(req any) (string, error) {
+	m, ok := req.(proto.Message)
+	if !ok {
+		return "", errors.New("unexpected request")
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// newIdempotentResult returns false when the result must not be stored.
+func newIdempotentResult(fingerprint string, reply any, err error) (*idempotentResult, bool) {
+	r := &idempotentResult{Fingerprint: fingerprint}
+	if err != nil {
+		se := kerrors.FromError(err)
+		if se.Code >= http.StatusInternalServerError {
+			return nil, false
+		}
+		r.Code, r.Reason, r.Message, r.Metadata = se.Code, se.Reason, se.Message, se.Metadata
+		return r, true
+	}
+	m, ok := reply.(proto.Message)
+	if !ok {
+		return nil, false
+	}
+	a, aerr := anypb.New(m)
+	if aerr != nil {
+		return nil, false
+	}
+	b, aerr := proto.Marshal(a)
+	if aerr != nil {
+		return nil, false
+	}
+	r.Reply = b
+	return r, true
+}
+
+func replay(tr transport.Transporter, stored []byte, fingerprint string) (any, error) {
+	var r idempotentResult
+	if err := json.Unmarshal(stored, &r); err != nil {
+		return nil, ErrIdempotencyResultCorrupted.WithCause(err)
+	}
+	if r.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	tr.ReplyHeader().Set(IdempotentReplayedHeader, "true")
+	if r.Reply == nil {
+		return nil, kerrors.New(int(r.Code), r.Reason, r.Message).WithMetadata(r.Metadata)
+	}
+	var a anypb.Any
+	if err := proto.Unmarshal(r.Reply, &a); err != nil {
+		return nil, ErrIdempotencyResultCorrupted.WithCause(err)
+	}
+	reply, err := anypb.UnmarshalNew(&a, proto.UnmarshalOptions{})
+	if err != nil {
+		return nil, ErrIdempotencyResultCorrupted.WithCause(err)
+	}
+	return reply, nil
+}
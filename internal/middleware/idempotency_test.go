@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+type headerCarrier http.Header
+
+func (hc headerCarrier) Get(key string) string      { return http.Header(hc).Get(key) }
+func (hc headerCarrier) Set(key, value string)      { http.Header(hc).Set(key, value) }
+func (hc headerCarrier) Add(key, value string)      { http.Header(hc).Add(key, value) }
+func (hc headerCarrier) Values(key string) []string { return http.Header(hc).Values(key) }
+func (hc headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(hc))
+	for k := range hc {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type testTransport struct {
+	// kind defaults to http
+	kind      transport.Kind
+	operation string
+	request   headerCarrier
+	reply     headerCarrier
+}
+
+func (t *testTransport) Kind() transport.Kind {
+	if t.kind == "" {
+		return transport.KindHTTP
+	}
+	return t.kind
+}
+func (t *testTransport) Endpoint() string                { return "" }
+func (t *testTransport) Operation() string               { return t.operation }
+func (t *testTransport) RequestHeader() transport.Header { return t.request }
+func (t *testTransport) ReplyHeader() transport.Header   { return t.reply }
+
+// memoryIdempotencyStore is the redis store in memory, the locks are owned by
+// their token like the lua scripts.
+type memoryIdempotencyStore struct {
+	mu     sync.Mutex
+	values map[string]string
+	seq    int
+}
+
+func (s *memoryIdempotencyStore) Acquire(_ context.Context, key string, _ time.Duration) (string, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.values[key]; ok {
+		if v[0] == '{' {
+			return "", []byte(v), nil
+		}
+		return "", nil, nil
+	}
+	s.seq++
+	token := strconv.Itoa(s.seq)
+	s.values[key] = token
+	return token, nil, nil
+}
+
+func (s *memoryIdempotencyStore) Save(_ context.Context, key, token string, result []byte, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values[key] == token {
+		s.values[key] = string(result)
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values[key] == token {
+		delete(s.values, key)
+	}
+	return nil
+}
+
+// expire drops the lock as redis does after the lock ttl.
+func (s *memoryIdempotencyStore) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.values)
+}
+
+func TestIdempotency(t *testing.T) {
+	store := &memoryIdempotencyStore{values: make(map[string]string)}
+	m := Idempotency(store, &conf.Idempotency{Operations: []string{sayHello}})
+	call := func(key, name string, handler func() (any, error)) (any, *testTransport, error) {
+		tr := &testTransport{operation: sayHello, request: headerCarrier{}, reply: headerCarrier{}}
+		tr.request.Set(defaultIdempotencyHeader, key)
+		ctx := transport.NewServerContext(context.Background(), tr)
+		reply, err := m(func(context.Context, any) (any, error) { return handler() })(ctx, &v1.HelloRequest{Name: name})
+		return reply, tr, err
+	}
+	ok := func() (any, error) { return &v1.HelloReply{Message: "hello"}, nil }
+
+	// the reply is stored and replayed
+	if _, _, err := call("a", "kratos", ok); err != nil {
+		t.Fatal(err)
+	}
+	reply, tr, err := call("a", "kratos", func() (any, error) { return nil, kerrors.InternalServer("CALLED", "handler called on replay") })
+	if err != nil || reply.(*v1.HelloReply).GetMessage() != "hello" || tr.reply.Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("replay = %v, %v, header %q", reply, err, tr.reply.Get(IdempotentReplayedHeader))
+	}
+	if _, _, err = call("a", "root", ok); kerrors.Reason(err) != v1.ErrorReason_IDEMPOTENCY_KEY_REUSED.String() {
+		t.Errorf("reused key error = %v", err)
+	}
+
+	// a request outliving the lock ttl can not save over the next one
+	_, _, err = call("b", "kratos", func() (any, error) {
+		store.expire()
+		if _, _, err := call("b", "kratos", ok); err != nil {
+			t.Fatal(err)
+		}
+		return nil, kerrors.BadRequest("SLOW", "the lock expired")
+	})
+	if kerrors.Reason(err) != "SLOW" {
+		t.Fatalf("slow request error = %v", err)
+	}
+	if reply, _, err = call("b", "kratos", ok); err != nil || reply.(*v1.HelloReply).GetMessage() != "hello" {
+		t.Errorf("the slow request overwrote the result: %v, %v", reply, err)
+	}
+
+	// the server errors release the key
+	if _, _, err = call("c", "kratos", func() (any, error) { return nil, kerrors.InternalServer("FAILED", "retry") }); err == nil {
+		t.Fatal("want the handler error")
+	}
+	if _, tr, err = call("c", "kratos", ok); err != nil || tr.reply.Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("retry after a server error = %v, replayed %q", err, tr.reply.Get(IdempotentReplayedHeader))
+	}
+
+	// a corrupted result is reported with its own reason
+	store.values[sayHello+"::"+":d"] = "{corrupted"
+	if _, _, err = call("d", "kratos", ok); kerrors.Reason(err) != v1.ErrorReason_IDEMPOTENCY_RESULT_CORRUPTED.String() {
+		t.Errorf("corrupted result error = %v", err)
+	}
+}
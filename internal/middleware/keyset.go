@@ -0,0 +1,297 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultJWKSRefreshInterval = 5 * time.Minute
+	defaultJWKSTimeout         = 5 * time.Second
+	// minJWKSRefreshInterval 未知kid触发刷新的最小间隔，避免伪造kid打满JWKS
+	minJWKSRefreshInterval = 30 * time.Second
+)
+
+var (
+	errKeyNotFound = errors.New("signing key not found")
+	// errUnsupportedKey is a JWKS key of an unsupported kty or crv, eg: OKP
+	errUnsupportedKey = errors.New("unsupported key")
+)
+
+type (
+	// verificationKey is a key used to verify the token signature.
+	verificationKey struct {
+		kid string
+		alg string
+		key any
+	}
+
+	// keySet holds the static keys and the keys loaded from the JWKS, the
+	// JWKS is reloaded lazily after the refresh interval or on an unknown kid.
+	keySet struct {
+		static []*verificationKey
+
+		jwks     *conf.Auth_JWKS
+		interval time.Duration
+		client   *http.Client
+
+		mu          sync.RWMutex
+		keys        []*verificationKey
+		fetchedAt   time.Time
+		lastAttempt time.Time
+	}
+
+	// jsonWebKey is a RFC 7517 key.
+	jsonWebKey struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+		K   string `json:"k"`
+	}
+)
+
+func newKeySet(c *conf.Auth_JWT) (*keySet, error) {
+	ks := &keySet{
+		jwks:     c.GetJwks(),
+		interval: defaultJWKSRefreshInterval,
+		client:   &http.Client{Timeout: defaultJWKSTimeout},
+	}
+	for _, k := range c.GetKeys() {
+		key, err := parseStaticKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("auth key %q: %w", k.GetKid(), err)
+		}
+		ks.static = append(ks.static, key)
+	}
+	if ks.jwks.GetRefreshInterval().AsDuration() > 0 {
+		ks.interval = ks.jwks.GetRefreshInterval().AsDuration()
+	}
+	if ks.jwks.GetTimeout().AsDuration() > 0 {
+		ks.client.Timeout = ks.jwks.GetTimeout().AsDuration()
+	}
+	if ks.jwks.GetUrl() != "" || ks.jwks.GetFile() != "" {
+		// fail fast on a broken JWKS
+		if err := ks.refresh(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	if len(ks.static) == 0 && len(ks.keys) == 0 {
+		return nil, errors.New("auth jwt requires keys or jwks")
+	}
+	return ks, nil
+}
+
+func parseStaticKey(k *conf.Auth_Key) (*verificationKey, error) {
+	key := &verificationKey{kid: k.GetKid(), alg: k.GetAlg()}
+	var err error
+	switch {
+	case strings.HasPrefix(k.GetAlg(), "HS"):
+		if k.GetSecret() == "" {
+			return nil, errors.New("missing secret")
+		}
+		key.key = []byte(k.GetSecret())
+	case strings.HasPrefix(k.GetAlg(), "RS"), strings.HasPrefix(k.GetAlg(), "PS"):
+		key.key, err = jwt.ParseRSAPublicKeyFromPEM([]byte(k.GetPublicKey()))
+	case strings.HasPrefix(k.GetAlg(), "ES"):
+		key.key, err = jwt.ParseECPublicKeyFromPEM([]byte(k.GetPublicKey()))
+	default:
+		return nil, fmt.Errorf("unexpected alg: %s", k.GetAlg())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Keyfunc implements jwt.Keyfunc.
+func (ks *keySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	alg := token.Method.Alg()
+
+	if key, ok := lookupKey(ks.static, kid, alg); ok {
+		return key, nil
+	}
+	if ks.jwks.GetUrl() == "" && ks.jwks.GetFile() == "" {
+		return nil, errKeyNotFound
+	}
+
+	ks.mu.RLock()
+	key, ok := lookupKey(ks.keys, kid, alg)
+	stale := time.Since(ks.fetchedAt) > ks.interval
+	canRefresh := time.Since(ks.lastAttempt) > minJWKSRefreshInterval
+	ks.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	// the keys may be rotated, reload them
+	if canRefresh {
+		if err := ks.refresh(context.Background()); err != nil && !ok {
+			return nil, err
+		}
+		ks.mu.RLock()
+		key, ok = lookupKey(ks.keys, kid, alg)
+		ks.mu.RUnlock()
+	}
+	if !ok {
+		return nil, errKeyNotFound
+	}
+	return key, nil
+}
+
+func lookupKey(keys []*verificationKey, kid, alg string) (any, bool) {
+	for _, k := range keys {
+		if kid != "" && k.kid != "" && k.kid != kid {
+			continue
+		}
+		// the alg of the key must match the token, to prevent algorithm confusion
+		if k.alg != "" && k.alg != alg {
+			continue
+		}
+		return k.key, true
+	}
+	return nil, false
+}
+
+func (ks *keySet) refresh(ctx context.Context) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	// another goroutine refreshed the keys in the meantime
+	if !ks.lastAttempt.IsZero() && time.Since(ks.lastAttempt) < minJWKSRefreshInterval {
+		return nil
+	}
+	// on failure the old keys are kept and retried after minJWKSRefreshInterval
+	ks.lastAttempt = time.Now()
+
+	data, err := ks.load(ctx)
+	if err != nil {
+		return fmt.Errorf("load jwks: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("parse jwks: %w", err)
+	}
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	return nil
+}
+
+func (ks *keySet) load(ctx context.Context) ([]byte, error) {
+	if ks.jwks.GetFile() != "" {
+		return os.ReadFile(ks.jwks.GetFile())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.jwks.GetUrl(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func parseJWKS(data []byte) ([]*verificationKey, error) {
+	var set struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make([]*verificationKey, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		// the providers publish the keys of other algorithms too, skip them
+		if errors.Is(err, errUnsupportedKey) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		keys = append(keys, &verificationKey{kid: k.Kid, alg: k.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no usable signing key")
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: crv %s", errUnsupportedKey, k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+	default:
+		return nil, fmt.Errorf("%w: kty %s", errUnsupportedKey, k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
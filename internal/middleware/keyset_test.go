@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaJWK := fmt.Sprintf(`{"kty":"RSA","kid":"rsa","alg":"RS256","use":"sig","n":%q,"e":%q}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()))
+	ecJWK := fmt.Sprintf(`{"kty":"EC","kid":"ec","alg":"ES256","crv":"P-256","x":%q,"y":%q}`,
+		b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()))
+	okpJWK := `{"kty":"OKP","kid":"ed","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	secp256k1JWK := `{"kty":"EC","kid":"k1","alg":"ES256K","crv":"secp256k1","x":"AA","y":"AA"}`
+	encJWK := fmt.Sprintf(`{"kty":"RSA","kid":"enc","use":"enc","n":%q,"e":"AQAB"}`, b64(rsaKey.N.Bytes()))
+
+	tests := []struct {
+		name string
+		jwks string
+		kids []string
+		err  bool
+	}{
+		{name: "rsa and ec", jwks: `{"keys":[` + rsaJWK + `,` + ecJWK + `]}`, kids: []string{"rsa", "ec"}},
+		{name: "okp skipped", jwks: `{"keys":[` + okpJWK + `,` + rsaJWK + `]}`, kids: []string{"rsa"}},
+		{name: "unsupported crv skipped", jwks: `{"keys":[` + secp256k1JWK + `,` + ecJWK + `]}`, kids: []string{"ec"}},
+		{name: "enc skipped", jwks: `{"keys":[` + encJWK + `,` + ecJWK + `]}`, kids: []string{"ec"}},
+		{name: "only unsupported", jwks: `{"keys":[` + okpJWK + `]}`, err: true},
+		{name: "empty", jwks: `{"keys":[]}`, err: true},
+		{name: "point not on the curve", jwks: `{"keys":[{"kty":"EC","kid":"ec","crv":"P-256","x":"AQ","y":"AQ"}]}`, err: true},
+		{name: "invalid json", jwks: `{"keys":`, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseJWKS([]byte(tt.jwks))
+			if (err != nil) != tt.err {
+				t.Fatalf("parseJWKS() error = %v, want error %v", err, tt.err)
+			}
+			if len(keys) != len(tt.kids) {
+				t.Fatalf("parseJWKS() got %d keys, want %v", len(keys), tt.kids)
+			}
+			for i, k := range keys {
+				if k.kid != tt.kids[i] {
+					t.Errorf("key %d kid = %q, want %q", i, k.kid, tt.kids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLookupKey(t *testing.T) {
+	keys := []*verificationKey{
+		{kid: "a", alg: "RS256", key: "a"},
+		{kid: "b", alg: "ES256", key: "b"},
+		{kid: "", alg: "HS256", key: "any-kid"},
+	}
+	tests := []struct {
+		name string
+		kid  string
+		alg  string
+		key  any
+		ok   bool
+	}{
+		{name: "kid and alg", kid: "a", alg: "RS256", key: "a", ok: true},
+		{name: "alg mismatch falls back", kid: "a", alg: "HS256", key: "any-kid", ok: true},
+		{name: "alg confusion", kid: "b", alg: "RS256", ok: false},
+		{name: "no kid", kid: "", alg: "ES256", key: "b", ok: true},
+		{name: "keyless kid", kid: "c", alg: "HS256", key: "any-kid", ok: true},
+		{name: "unknown", kid: "c", alg: "PS256", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := lookupKey(keys, tt.kid, tt.alg)
+			if ok != tt.ok || key != tt.key {
+				t.Errorf("lookupKey(%q, %q) = %v, %v, want %v, %v", tt.kid, tt.alg, key, ok, tt.key, tt.ok)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"sync"
 	"time"
@@ -41,10 +42,15 @@ type (
 	// Option limiter middleware option
 	Option  func(*options)
 	options struct {
-		bbrConfig *conf.BBR
+		bbrConfig  *conf.BBR
+		rateLimits []*conf.RateLimit
 	}
 )
 
+// defaultRateLimitTimeout is the wait of the overridden operations without
+// the limit of a service.
+const defaultRateLimitTimeout = time.Second
+
 var (
 	// ErrLimitExceed is service unavailable due to rate limit exceeded.
 	ErrLimitExceed = errors.New(http.StatusTooManyRequests, "RATE_LIMIT", "service unavailable due to rate limit exceeded")
@@ -55,6 +61,10 @@ var (
 var (
 	// 业务limiter
 	limiters = sync.Map{}
+	// the limits of the services and the overridden operations of the config
+	limitsMu      sync.Mutex
+	serviceLimits = map[string]*LimiterConfig{}
+	overriddenOps = map[string]struct{}{}
 	// bbr
 	globalLimiter ratelimit.Limiter
 	windowSize    = time.Second * 10
@@ -68,6 +78,13 @@ func WithBBR(bbrConfig *conf.BBR) Option {
 	}
 }
 
+// WithRateLimits overrides the limits of the services with the config.
+func WithRateLimits(limits []*conf.RateLimit) Option {
+	return func(o *options) {
+		o.rateLimits = limits
+	}
+}
+
 func Limiter(ls []*LimiterConfig, opts ...Option) middleware.Middleware {
 	op := options{
 		bbrConfig: nil,
@@ -91,12 +108,12 @@ func Limiter(ls []*LimiterConfig, opts ...Option) middleware.Middleware {
 	}
 
 	// 业务limiter
+	limitsMu.Lock()
 	for _, l := range ls {
-		limiters.Store(l.O, &limiter{
-			limiter: rate.NewLimiter(l.R, l.B),
-			t:       l.T,
-		})
+		serviceLimits[l.O] = l
 	}
+	limitsMu.Unlock()
+	ReloadRateLimits(op.rateLimits)
 
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
@@ -141,3 +158,67 @@ func Limiter(ls []*LimiterConfig, opts ...Option) middleware.Middleware {
 		}
 	}
 }
+
+// ReloadRateLimits applies the limits of the config over the ones of the
+// services, the limiters keep their tokens. The unset burst and timeout
+// default to the ones of the service, or to a second of requests and
+// defaultRateLimitTimeout, a zero burst or timeout would reject every request.
+func ReloadRateLimits(limits []*conf.RateLimit) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+
+	overrides := make(map[string]*LimiterConfig, len(limits))
+	for _, l := range limits {
+		c := &LimiterConfig{O: l.GetOperation(), R: rate.Limit(l.GetRate()), B: int(l.GetBurst()), T: l.GetTimeout().AsDuration()}
+		base, ok := serviceLimits[c.O]
+		if !ok {
+			base = &LimiterConfig{B: max(1, int(math.Ceil(l.GetRate()))), T: defaultRateLimitTimeout}
+		}
+		if c.B <= 0 {
+			c.B = base.B
+		}
+		if c.T <= 0 {
+			c.T = base.T
+		}
+		overrides[c.O] = c
+	}
+
+	for o := range overriddenOps {
+		if _, ok := overrides[o]; ok {
+			continue
+		}
+		// back to the limit of the service, or unknown
+		if base, ok := serviceLimits[o]; ok {
+			storeLimiter(base)
+		} else {
+			limiters.Delete(o)
+		}
+	}
+	for o, base := range serviceLimits {
+		if _, ok := overrides[o]; !ok {
+			storeLimiter(base)
+		}
+	}
+	overriddenOps = make(map[string]struct{}, len(overrides))
+	for o, c := range overrides {
+		storeLimiter(c)
+		overriddenOps[o] = struct{}{}
+	}
+}
+
+func storeLimiter(c *LimiterConfig) {
+	if v, ok := limiters.Load(c.O); ok {
+		l := v.(*limiter)
+		l.limiter.SetLimit(c.R)
+		l.limiter.SetBurst(c.B)
+		if l.t == c.T {
+			return
+		}
+		limiters.Store(c.O, &limiter{limiter: l.limiter, t: c.T})
+		return
+	}
+	limiters.Store(c.O, &limiter{
+		limiter: rate.NewLimiter(c.R, c.B),
+		t:       c.T,
+	})
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/transport"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// resetLimiters clears the limits of the services and the config.
+func resetLimiters(t *testing.T) {
+	reset := func() {
+		limitsMu.Lock()
+		serviceLimits = map[string]*LimiterConfig{}
+		overriddenOps = map[string]struct{}{}
+		limitsMu.Unlock()
+		limiters.Range(func(k, _ any) bool {
+			limiters.Delete(k)
+			return true
+		})
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestReloadRateLimits(t *testing.T) {
+	resetLimiters(t)
+	const other = "/helloworld.v1.Greeter/SayGoodbye"
+	m := Limiter([]*LimiterConfig{{O: sayHello, R: 10, B: 10, T: 3 * time.Second}})
+
+	tests := []struct {
+		name   string
+		limits []*conf.RateLimit
+		want   map[string]*LimiterConfig
+	}{
+		{name: "service", want: map[string]*LimiterConfig{
+			sayHello: {R: 10, B: 10, T: 3 * time.Second},
+		}},
+		{name: "rate of the config", limits: []*conf.RateLimit{{Operation: sayHello, Rate: 5}}, want: map[string]*LimiterConfig{
+			sayHello: {R: 5, B: 10, T: 3 * time.Second},
+		}},
+		{name: "every field of the config", limits: []*conf.RateLimit{{Operation: sayHello, Rate: 5, Burst: 2, Timeout: durationpb.New(100 * time.Millisecond)}}, want: map[string]*LimiterConfig{
+			sayHello: {R: 5, B: 2, T: 100 * time.Millisecond},
+		}},
+		{name: "no service limit", limits: []*conf.RateLimit{{Operation: other, Rate: 2.5}}, want: map[string]*LimiterConfig{
+			sayHello: {R: 10, B: 10, T: 3 * time.Second},
+			other:    {R: 2.5, B: 3, T: defaultRateLimitTimeout},
+		}},
+		{name: "no service limit below one request", limits: []*conf.RateLimit{{Operation: other, Rate: 0.1, Timeout: durationpb.New(0)}}, want: map[string]*LimiterConfig{
+			sayHello: {R: 10, B: 10, T: 3 * time.Second},
+			other:    {R: 0.1, B: 1, T: defaultRateLimitTimeout},
+		}},
+		{name: "removed", want: map[string]*LimiterConfig{
+			sayHello: {R: 10, B: 10, T: 3 * time.Second},
+			other:    nil,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ReloadRateLimits(tt.limits)
+			for o, want := range tt.want {
+				v, ok := limiters.Load(o)
+				if want == nil {
+					if ok {
+						t.Errorf("%s limiter = %v, want none", o, v)
+					}
+					continue
+				}
+				if !ok {
+					t.Fatalf("%s limiter is missing", o)
+				}
+				l := v.(*limiter)
+				if l.limiter.Limit() != want.R || l.limiter.Burst() != want.B || l.t != want.T {
+					t.Errorf("%s limiter = %v/%d/%s, want %v/%d/%s", o, l.limiter.Limit(), l.limiter.Burst(), l.t, want.R, want.B, want.T)
+				}
+			}
+		})
+	}
+
+	// an operation limited only by the config accepts its requests
+	ReloadRateLimits([]*conf.RateLimit{{Operation: other, Rate: 1}})
+	tr := &testTransport{operation: other, request: headerCarrier{}, reply: headerCarrier{}}
+	ctx := transport.NewServerContext(context.Background(), tr)
+	if _, err := m(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil); err != nil {
+		t.Errorf("Limiter() = %v, want the request accepted", err)
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	metricOperationRequests = "server_operation_requests"
+	metricOperationErrors   = "server_operation_errors"
+	metricOperationDuration = "server_operation_duration"
+)
+
+// defaultDurationBuckets covers 5ms up to 10s, so the latency SLO thresholds
+// of most operations fall on a boundary.
+var defaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics records the requests, the errors and the duration of every operation,
+// labelled with the transport, the status code and the kratos error reason.
+//
+// The buckets of a histogram are fixed per instrument, the boundaries of the
+// operations are merged into the buckets of the one duration histogram.
+func Metrics(provider metric.MeterProvider, scope string, c *conf.Otel_Metric_Server) (middleware.Middleware, error) {
+	meter := provider.Meter(scope)
+	requests, err := meter.Int64Counter(metricOperationRequests,
+		metric.WithUnit("{request}"), metric.WithDescription("The total number of processed requests by operation."))
+	if err != nil {
+		return nil, err
+	}
+	failures, err := meter.Int64Counter(metricOperationErrors,
+		metric.WithUnit("{request}"), metric.WithDescription("The total number of failed requests by operation and reason."))
+	if err != nil {
+		return nil, err
+	}
+	seconds, err := meter.Float64Histogram(metricOperationDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("The duration of the requests by operation."),
+		metric.WithExplicitBucketBoundaries(durationBuckets(c)...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+
+			start := time.Now()
+			reply, err := handler(ctx, req)
+
+			operation := tr.Operation()
+			kind := tr.Kind()
+			var code, reason string
+			switch {
+			case kind != transport.KindGRPC:
+				code = strconv.Itoa(errors.Code(err))
+			case err == nil:
+				code = codes.OK.String()
+			default:
+				code = errors.FromError(err).GRPCStatus().Code().String()
+			}
+			if err != nil {
+				reason = errors.Reason(err)
+			}
+			attrs := metric.WithAttributeSet(attribute.NewSet(
+				attribute.String("operation", operation),
+				attribute.String("transport", kind.String()),
+				attribute.String("code", code),
+				attribute.String("reason", reason),
+			))
+
+			requests.Add(ctx, 1, attrs)
+			if err != nil {
+				failures.Add(ctx, 1, attrs)
+			}
+			seconds.Record(ctx, time.Since(start).Seconds(), attrs)
+			return reply, err
+		}
+	}, nil
+}
+
+// durationBuckets returns the sorted union of the buckets and the boundaries
+// of the operations.
+func durationBuckets(c *conf.Otel_Metric_Server) []float64 {
+	buckets := slices.Clone(defaultDurationBuckets)
+	if len(c.GetBuckets()) > 0 {
+		buckets = slices.Clone(c.GetBuckets())
+	}
+	for _, b := range c.GetOperations() {
+		buckets = append(buckets, b.GetBoundaries()...)
+	}
+	slices.Sort(buckets)
+	return slices.Compact(buckets)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/transport"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestDurationBuckets(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *conf.Otel_Metric_Server
+		want []float64
+	}{
+		{name: "default", want: defaultDurationBuckets},
+		{name: "buckets", c: &conf.Otel_Metric_Server{Buckets: []float64{1, 0.1}}, want: []float64{0.1, 1}},
+		{name: "union", c: &conf.Otel_Metric_Server{
+			Buckets: []float64{0.1, 1},
+			Operations: map[string]*conf.Otel_Metric_Buckets{
+				sayHello:            {Boundaries: []float64{0.2, 1}},
+				"/helloworld.v1.B/": {Boundaries: []float64{0.05}},
+				"/helloworld.v1.C/": {},
+			},
+		}, want: []float64{0.05, 0.1, 0.2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := durationBuckets(tt.c); !slices.Equal(got, tt.want) {
+				t.Errorf("durationBuckets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	m, err := Metrics(provider, "helloworld", &conf.Otel_Metric_Server{
+		Buckets:    []float64{0.1, 1},
+		Operations: map[string]*conf.Otel_Metric_Buckets{sayHello: {Boundaries: []float64{0.2}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range []string{sayHello, "/helloworld.v1.Greeter/Other"} {
+		ctx := transport.NewServerContext(context.Background(), &testTransport{operation: op, request: headerCarrier{}, reply: headerCarrier{}})
+		if _, err = m(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err = reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	if len(rm.ScopeMetrics) != 1 {
+		t.Fatalf("got %d meter scopes, want 1", len(rm.ScopeMetrics))
+	}
+	for _, metric := range rm.ScopeMetrics[0].Metrics {
+		if metric.Name != metricOperationDuration {
+			continue
+		}
+		points := metric.Data.(metricdata.Histogram[float64]).DataPoints
+		if len(points) != 2 {
+			t.Fatalf("got %d duration series, want 2", len(points))
+		}
+		for _, p := range points {
+			if !slices.Equal(p.Bounds, []float64{0.1, 0.2, 1}) {
+				t.Errorf("bounds = %v, want the union", p.Bounds)
+			}
+		}
+		return
+	}
+	t.Fatalf("%s not recorded", metricOperationDuration)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+const profilerOperationLabel = "operation"
+
+// ProfilerLabels tags the goroutine handling the request with the kratos
+// operation, so cpu profiles can be attributed to rpc.
+func ProfilerLabels() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+
+			pprof.Do(ctx, pprof.Labels(profilerOperationLabel, tr.Operation()), func(ctx context.Context) {
+				reply, err = handler(ctx, req)
+			})
+			return reply, err
+		}
+	}
+}
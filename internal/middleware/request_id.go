@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength 超过长度的请求ID会被重新生成
+const maxRequestIDLength = 128
+
+// RequestID accepts the incoming X-Request-Id or generates one, stores it in
+// context and echoes it in the reply header.
+func RequestID() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+
+			id := tr.RequestHeader().Get(metadata.KeyRequestID)
+			if !validRequestID(id) {
+				id = uuid.NewString()
+			}
+			tr.ReplyHeader().Set(metadata.KeyRequestID, id)
+
+			return handler(metadata.NewRequestIDContext(ctx, id), req)
+		}
+	}
+}
+
+// RequestIDClient propagates the request id in context to the outgoing
+// gRPC/HTTP request header.
+func RequestIDClient() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			if id, ok := metadata.RequestIDFromContext(ctx); ok {
+				if tr, ok := transport.FromClientContext(ctx); ok {
+					tr.RequestHeader().Set(metadata.KeyRequestID, id)
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		// 仅允许可见ASCII字符，避免日志注入
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
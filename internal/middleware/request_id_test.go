@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		generate bool
+	}{
+		{name: "accepted", header: "req-1"},
+		{name: "missing", generate: true},
+		{name: "too long", header: strings.Repeat("a", maxRequestIDLength+1), generate: true},
+		{name: "control characters", header: "req\n1", generate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &testTransport{request: headerCarrier{}, reply: headerCarrier{}}
+			tr.request.Set(metadata.KeyRequestID, tt.header)
+			ctx := transport.NewServerContext(context.Background(), tr)
+
+			var got string
+			_, err := RequestID()(func(ctx context.Context, _ any) (any, error) {
+				got, _ = metadata.RequestIDFromContext(ctx)
+				return nil, nil
+			})(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.generate {
+				if _, err = uuid.Parse(got); err != nil {
+					t.Errorf("request id %q is not generated: %v", got, err)
+				}
+			} else if got != tt.header {
+				t.Errorf("request id = %q, want %q", got, tt.header)
+			}
+			if reply := tr.reply.Get(metadata.KeyRequestID); reply != got {
+				t.Errorf("reply header = %q, want %q", reply, got)
+			}
+		})
+	}
+}
+
+func TestRequestIDClient(t *testing.T) {
+	call := func(ctx context.Context) string {
+		tr := &testTransport{request: headerCarrier{}, reply: headerCarrier{}}
+		ctx = transport.NewClientContext(ctx, tr)
+		_, _ = RequestIDClient()(func(context.Context, any) (any, error) { return nil, nil })(ctx, nil)
+		return tr.request.Get(metadata.KeyRequestID)
+	}
+
+	if got := call(metadata.NewRequestIDContext(context.Background(), "req-1")); got != "req-1" {
+		t.Errorf("outgoing request id = %q, want req-1", got)
+	}
+	if got := call(context.Background()); got != "" {
+		t.Errorf("outgoing request id = %q, want none", got)
+	}
+}
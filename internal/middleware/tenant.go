@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+
+	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/errors"
+	kmetadata "github.com/go-kratos/kratos/v2/metadata"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultTenantHeader = "X-Tenant-Id"
+	// maxTenantLength 租户ID会拼接进Redis key和SQL条件，限制长度和字符集
+	maxTenantLength = 64
+)
+
+var (
+	// ErrTenantRequired is the request is not bound to a valid tenant.
+	ErrTenantRequired = errors.BadRequest(v1.ErrorReason_TENANT_REQUIRED.String(), "tenant required")
+	// ErrTenantMismatch is the requested tenant differs from the tenant of the token.
+	ErrTenantMismatch = errors.Forbidden(v1.ErrorReason_TENANT_MISMATCH.String(), "tenant mismatch")
+	// ErrTenantForbidden is the caller is neither bound to a tenant nor allowed to pick one.
+	ErrTenantForbidden = errors.Forbidden(v1.ErrorReason_TENANT_FORBIDDEN.String(), "tenant not allowed")
+)
+
+// Tenant resolves the tenant of the request from the tenant_id claim, the
+// header, the propagated metadata or the subdomain, stores it in context and
+// kratos metadata, and tags the span with it. The header and the metadata are
+// trusted only from the callers with a switch scope, and the metadata from any
+// caller with trust_metadata, the authenticated callers without a tenant_id
+// claim, eg: the api keys, can't be bound to a tenant otherwise.
+func Tenant(c *conf.Tenant) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		if !c.GetEnable() {
+			return handler
+		}
+		header := defaultTenantHeader
+		if c.GetHeader() != "" {
+			header = c.GetHeader()
+		}
+		skip := make(map[string]struct{})
+		var skipPrefixes []string
+		for _, op := range c.GetSkipOperations() {
+			if prefix, ok := strings.CutSuffix(op, "*"); ok {
+				skipPrefixes = append(skipPrefixes, prefix)
+				continue
+			}
+			skip[op] = struct{}{}
+		}
+		skipped := func(operation string) bool {
+			if _, ok := skip[operation]; ok {
+				return true
+			}
+			for _, p := range skipPrefixes {
+				if strings.HasPrefix(operation, p) {
+					return true
+				}
+			}
+			return false
+		}
+
+		return func(ctx context.Context, req any) (reply any, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok || skipped(tr.Operation()) {
+				return handler(ctx, req)
+			}
+
+			picked := tr.RequestHeader().Get(header)
+			// the metadata propagated by the upstream service
+			var propagated string
+			if picked == "" {
+				if md, ok := kmetadata.FromServerContext(ctx); ok {
+					propagated = md.Get(metadata.KeyTenantID)
+					picked = propagated
+				}
+			}
+			requested := picked
+			if requested == "" && c.GetDomain() != "" {
+				requested = subdomainTenant(ctx, c.GetDomain())
+			}
+
+			var tenant string
+			claims, authenticated := auth.FromContext(ctx)
+			switch {
+			case authenticated && claims.TenantID != "":
+				// the tenant of the token can't be overridden by the request
+				if requested != "" && requested != claims.TenantID {
+					return nil, ErrTenantMismatch
+				}
+				tenant = claims.TenantID
+			case authenticated && slices.ContainsFunc(c.GetSwitchScopes(), claims.HasScope):
+				tenant = requested
+			case propagated != "" && c.GetTrustMetadata():
+				// an internal hop, the tenant was resolved by the edge service
+				tenant = propagated
+			case authenticated:
+				// not bound to a tenant, eg: the api keys and the hmac clients
+				if requested != "" || c.GetRequired() {
+					return nil, ErrTenantForbidden
+				}
+			case picked != "":
+				// the anonymous callers are bound to the subdomain only
+				return nil, ErrTenantForbidden
+			default:
+				tenant = requested
+			}
+			if tenant == "" {
+				if c.GetRequired() {
+					return nil, ErrTenantRequired
+				}
+				return handler(ctx, req)
+			}
+			if !validTenant(tenant) {
+				return nil, ErrTenantRequired
+			}
+
+			if md, ok := kmetadata.FromServerContext(ctx); ok {
+				md.Set(metadata.KeyTenantID, tenant)
+			} else {
+				ctx = kmetadata.NewServerContext(ctx, kmetadata.New(map[string][]string{metadata.KeyTenantID: {tenant}}))
+			}
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("tenant.id", tenant))
+			return handler(metadata.NewTenantContext(ctx, tenant), req)
+		}
+	}
+}
+
+// subdomainTenant returns acme of the host acme.example.com.
+func subdomainTenant(ctx context.Context, domain string) string {
+	r, ok := http.RequestFromServerContext(ctx)
+	if !ok {
+		return ""
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	sub, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(domain))
+	if !ok || sub == "" || strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
+
+func validTenant(tenant string) bool {
+	if len(tenant) > maxTenantLength {
+		return false
+	}
+	for i := 0; i < len(tenant); i++ {
+		c := tenant[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
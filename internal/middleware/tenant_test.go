@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/auth"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	kmetadata "github.com/go-kratos/kratos/v2/metadata"
+	"github.com/go-kratos/kratos/v2/middleware"
+	kmdmw "github.com/go-kratos/kratos/v2/middleware/metadata"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+func TestTenant(t *testing.T) {
+	bound := &auth.Claims{TenantID: "acme"}
+	switcher := &auth.Claims{Scp: []string{"tenant:switch"}}
+	apiKey := &auth.Claims{Scp: []string{"greeter:read"}}
+	tests := []struct {
+		name     string
+		required bool
+		trust    bool
+		claims   *auth.Claims
+		header   string
+		md       string
+		tenant   string
+		err      error
+	}{
+		{name: "claim", claims: bound, tenant: "acme"},
+		{name: "claim and same header", claims: bound, header: "acme", tenant: "acme"},
+		{name: "claim and other header", claims: bound, header: "other", err: ErrTenantMismatch},
+		{name: "claim and other metadata", claims: bound, md: "other", err: ErrTenantMismatch},
+		{name: "switch scope header", claims: switcher, header: "other", tenant: "other"},
+		{name: "switch scope metadata", claims: switcher, md: "other", tenant: "other"},
+		{name: "switch scope no tenant", claims: switcher},
+		{name: "switch scope required", required: true, claims: switcher, err: ErrTenantRequired},
+		{name: "api key header", claims: apiKey, header: "acme", err: ErrTenantForbidden},
+		{name: "api key metadata", claims: apiKey, md: "acme", err: ErrTenantForbidden},
+		{name: "api key no tenant", claims: apiKey},
+		{name: "api key required", required: true, claims: apiKey, err: ErrTenantForbidden},
+		{name: "anonymous header", header: "acme", err: ErrTenantForbidden},
+		{name: "anonymous metadata", md: "acme", err: ErrTenantForbidden},
+		{name: "anonymous no tenant"},
+		{name: "anonymous required", required: true, err: ErrTenantRequired},
+		{name: "invalid tenant", claims: switcher, header: "acme:*", err: ErrTenantRequired},
+		{name: "trusted anonymous metadata", trust: true, md: "acme", tenant: "acme"},
+		{name: "trusted api key metadata", trust: true, claims: apiKey, md: "acme", tenant: "acme"},
+		{name: "trusted claim and other metadata", trust: true, claims: bound, md: "other", err: ErrTenantMismatch},
+		{name: "trusted anonymous header", trust: true, header: "acme", err: ErrTenantForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Tenant(&conf.Tenant{Enable: true, Required: tt.required, SwitchScopes: []string{"tenant:switch"}, TrustMetadata: tt.trust})
+			tr := &testTransport{operation: sayHello, request: headerCarrier{}, reply: headerCarrier{}}
+			if tt.header != "" {
+				tr.request.Set(defaultTenantHeader, tt.header)
+			}
+			ctx := transport.NewServerContext(context.Background(), tr)
+			if tt.md != "" {
+				ctx = kmetadata.NewServerContext(ctx, kmetadata.New(map[string][]string{metadata.KeyTenantID: {tt.md}}))
+			}
+			if tt.claims != nil {
+				ctx = auth.NewContext(ctx, tt.claims)
+			}
+			var tenant string
+			_, err := m(func(ctx context.Context, _ any) (any, error) {
+				tenant, _ = metadata.TenantFromContext(ctx)
+				return nil, nil
+			})(ctx, nil)
+			if kerrors.Reason(err) != kerrors.Reason(tt.err) {
+				t.Fatalf("Tenant() error = %v, want %v", err, tt.err)
+			}
+			if tenant != tt.tenant {
+				t.Errorf("tenant = %q, want %q", tenant, tt.tenant)
+			}
+		})
+	}
+}
+
+func TestTenantPropagation(t *testing.T) {
+	tests := []struct {
+		name   string
+		trust  bool
+		tenant string
+		err    error
+	}{
+		{name: "trusted", trust: true, tenant: "acme"},
+		{name: "untrusted", err: ErrTenantForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the edge service binds the caller to the tenant of its token
+			edge := &testTransport{operation: sayHello, request: headerCarrier{}, reply: headerCarrier{}}
+			ctx := auth.NewContext(transport.NewServerContext(context.Background(), edge), &auth.Claims{TenantID: "acme"})
+			// and calls the downstream service, which sees the anonymous caller
+			outgoing := &testTransport{operation: sayHello, request: headerCarrier{}, reply: headerCarrier{}}
+			edgeHandler := middleware.Chain(kmdmw.Server(), Tenant(&conf.Tenant{Enable: true}))(func(ctx context.Context, req any) (any, error) {
+				return kmdmw.Client()(func(context.Context, any) (any, error) { return nil, nil })(transport.NewClientContext(ctx, outgoing), req)
+			})
+			if _, err := edgeHandler(ctx, nil); err != nil {
+				t.Fatal(err)
+			}
+			if outgoing.request.Get(metadata.KeyTenantID) != "acme" {
+				t.Fatalf("outgoing metadata = %v, want the tenant", outgoing.request)
+			}
+
+			downstream := &testTransport{operation: sayHello, request: outgoing.request, reply: headerCarrier{}}
+			var tenant string
+			_, err := middleware.Chain(kmdmw.Server(), Tenant(&conf.Tenant{Enable: true, TrustMetadata: tt.trust}))(func(ctx context.Context, _ any) (any, error) {
+				tenant, _ = metadata.TenantFromContext(ctx)
+				return nil, nil
+			})(transport.NewServerContext(context.Background(), downstream), nil)
+			if kerrors.Reason(err) != kerrors.Reason(tt.err) {
+				t.Fatalf("downstream error = %v, want %v", err, tt.err)
+			}
+			if tenant != tt.tenant {
+				t.Errorf("downstream tenant = %q, want %q", tenant, tt.tenant)
+			}
+		})
+	}
+}
@@ -2,30 +2,238 @@ package middleware
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/errors"
+	kmetadata "github.com/go-kratos/kratos/v2/metadata"
 	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	gmetadata "google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-const traceIdKey = "X-Trace-Id"
+const (
+	// TraceIDHeader is the default reply header carrying the trace id.
+	TraceIDHeader = "X-Trace-Id"
+	// TraceparentHeader is the default reply header carrying the W3C trace
+	// context of the server span.
+	TraceparentHeader = "traceparent"
+	// ServerTimingHeader is the default reply header carrying the handler duration.
+	ServerTimingHeader = "Server-Timing"
 
-func TraceMiddleware() middleware.Middleware {
+	defaultServerTimingMetric = "app"
+)
+
+// TraceHeaders returns the names of the reply headers set by TraceMiddleware.
+func TraceHeaders(c *conf.Otel_Trace_Response) (traceID, traceparent, serverTiming string) {
+	traceID, traceparent, serverTiming = TraceIDHeader, TraceparentHeader, ServerTimingHeader
+	if c.GetTraceIdHeader() != "" {
+		traceID = c.GetTraceIdHeader()
+	}
+	if c.GetTraceparentHeader() != "" {
+		traceparent = c.GetTraceparentHeader()
+	}
+	if c.GetServerTimingHeader() != "" {
+		serverTiming = c.GetServerTimingHeader()
+	}
+	return traceID, traceparent, serverTiming
+}
+
+// TraceMiddleware sets the trace id, and optionally the traceparent and the
+// Server-Timing of the handler, on the reply headers and the gRPC trailers.
+func TraceMiddleware(c *conf.Otel_Trace_Response) middleware.Middleware {
+	traceIDHeader, traceparentHeader, serverTimingHeader := TraceHeaders(c)
+	metric := defaultServerTimingMetric
+	if c.GetServerTimingMetric() != "" {
+		metric = c.GetServerTimingMetric()
+	}
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req any) (reply any, err error) {
+			start := time.Now()
 			reply, err = handler(ctx, req)
 
-			span := trace.SpanFromContext(ctx)
-			sc := span.SpanContext()
-			if !sc.IsValid() {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
 				return reply, err
 			}
+			var kv []string
+			if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+				kv = append(kv, traceIDHeader, sc.TraceID().String())
+				if c.GetTraceparent() {
+					kv = append(kv, traceparentHeader, fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags()))
+				}
+			}
+			if c.GetServerTiming() {
+				ms := float64(time.Since(start).Microseconds()) / 1000
+				kv = append(kv, serverTimingHeader, metric+";dur="+strconv.FormatFloat(ms, 'f', -1, 64))
+			}
+			for i := 0; i < len(kv); i += 2 {
+				tr.ReplyHeader().Set(kv[i], kv[i+1])
+			}
+			if c.GetGrpcTrailer() && tr.Kind() == transport.KindGRPC && len(kv) > 0 {
+				_ = grpc.SetTrailer(ctx, gmetadata.Pairs(kv...))
+			}
 
-			if tr, ok := transport.FromServerContext(ctx); ok {
-				tr.ReplyHeader().Set(traceIdKey, sc.TraceID().String())
+			return reply, err
+		}
+	}
+}
+
+type enrichField struct {
+	key       string
+	path      []string
+	attribute string
+	baggage   bool
+}
+
+func newEnrichFields(fields []*conf.Otel_Trace_Enrich_Field, request bool) []enrichField {
+	res := make([]enrichField, 0, len(fields))
+	for _, f := range fields {
+		ef := enrichField{key: f.GetKey(), attribute: f.GetAttribute(), baggage: f.GetBaggage()}
+		if request {
+			ef.path = strings.Split(f.GetKey(), ".")
+		}
+		if ef.attribute == "" {
+			if request {
+				ef.attribute = "request." + f.GetKey()
+			} else {
+				name := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(f.GetKey()), "x-md-global-"), "x-md-")
+				ef.attribute = strings.ReplaceAll(name, "-", ".")
 			}
+		}
+		res = append(res, ef)
+	}
+	return res
+}
 
+// TraceEnrich tags the server span with the configured metadata and request
+// fields, optionally propagating them in the baggage, and with the reason of
+// the error and the stack of a panic.
+func TraceEnrich(c *conf.Otel_Trace_Enrich) middleware.Middleware {
+	metadataFields := newEnrichFields(c.GetMetadata(), false)
+	requestFields := newEnrichFields(c.GetRequestFields(), true)
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (reply any, err error) {
+			span := trace.SpanFromContext(ctx)
+
+			var attrs []attribute.KeyValue
+			var members []baggage.Member
+			add := func(f enrichField, v attribute.Value) {
+				attrs = append(attrs, attribute.KeyValue{Key: attribute.Key(f.attribute), Value: v})
+				if !f.baggage {
+					return
+				}
+				if m, merr := baggage.NewMemberRaw(f.attribute, v.Emit()); merr == nil {
+					members = append(members, m)
+				}
+			}
+			for _, f := range metadataFields {
+				if v := metadataValue(ctx, f.key); v != "" {
+					add(f, attribute.StringValue(v))
+				}
+			}
+			if m, ok := req.(proto.Message); ok && len(requestFields) > 0 {
+				msg := m.ProtoReflect()
+				for _, f := range requestFields {
+					if v, ok := fieldValue(msg, f.path); ok {
+						add(f, v)
+					}
+				}
+			}
+			span.SetAttributes(attrs...)
+			if len(members) > 0 {
+				b := baggage.FromContext(ctx)
+				for _, m := range members {
+					b, _ = b.SetMember(m)
+				}
+				ctx = baggage.ContextWithBaggage(ctx, b)
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					// recovery is outside of the span, record the stack before it's lost
+					span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+						semconv.ExceptionType("panic"),
+						semconv.ExceptionMessage(fmt.Sprint(r)),
+						semconv.ExceptionStacktrace(string(debug.Stack())),
+					))
+					panic(r)
+				}
+			}()
+			reply, err = handler(ctx, req)
+			if err != nil {
+				e := errors.FromError(err)
+				span.SetAttributes(attribute.String("error.reason", e.Reason), attribute.String("error.message", e.Message))
+				if cause := e.Unwrap(); cause != nil {
+					span.SetAttributes(attribute.String("error.cause", cause.Error()))
+				}
+			}
 			return reply, err
 		}
 	}
 }
+
+// metadataValue gets the key from the kratos metadata, or the request header
+// before the metadata middleware ran.
+func metadataValue(ctx context.Context, key string) string {
+	if md, ok := kmetadata.FromServerContext(ctx); ok {
+		if v := md.Get(key); v != "" {
+			return v
+		}
+	}
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		return tr.RequestHeader().Get(key)
+	}
+	return ""
+}
+
+// fieldValue gets the scalar field at the path, the unset fields are skipped.
+func fieldValue(msg protoreflect.Message, path []string) (attribute.Value, bool) {
+	for i, name := range path {
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil || fd.IsList() || fd.IsMap() || !msg.Has(fd) {
+			return attribute.Value{}, false
+		}
+		if i < len(path)-1 {
+			if fd.Message() == nil {
+				return attribute.Value{}, false
+			}
+			msg = msg.Get(fd).Message()
+			continue
+		}
+
+		v := msg.Get(fd)
+		switch fd.Kind() {
+		case protoreflect.BoolKind:
+			return attribute.BoolValue(v.Bool()), true
+		case protoreflect.EnumKind:
+			if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+				return attribute.StringValue(string(ev.Name())), true
+			}
+			return attribute.Int64Value(int64(v.Enum())), true
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+			protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			return attribute.Int64Value(v.Int()), true
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+			return attribute.Int64Value(int64(v.Uint())), true
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			return attribute.StringValue(v.String()), true
+		case protoreflect.FloatKind, protoreflect.DoubleKind:
+			return attribute.Float64Value(v.Float()), true
+		case protoreflect.StringKind:
+			return attribute.StringValue(v.String()), true
+		}
+	}
+	return attribute.Value{}, false
+}
@@ -0,0 +1,309 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	kmetadata "github.com/go-kratos/kratos/v2/metadata"
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	gmetadata "google.golang.org/grpc/metadata"
+)
+
+// serverSpan runs the handler in a recorded server span and returns the ended span.
+func serverSpan(t *testing.T, ctx context.Context, run func(ctx context.Context)) sdktrace.ReadOnlySpan {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	ctx, span := tp.Tracer("test").Start(ctx, sayHello)
+	func() {
+		defer span.End()
+		run(ctx)
+	}()
+	ended := sr.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(ended))
+	}
+	return ended[0]
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTraceEnrich(t *testing.T) {
+	c := &conf.Otel_Trace_Enrich{
+		Metadata: []*conf.Otel_Trace_Enrich_Field{
+			{Key: "x-md-global-user-id", Baggage: true},
+			{Key: "x-md-client-version"},
+			{Key: "X-Device", Attribute: "device.kind"},
+			{Key: "x-md-global-missing"},
+		},
+		RequestFields: []*conf.Otel_Trace_Enrich_Field{
+			{Key: "http.addr", Baggage: true},
+			{Key: "http.envelope"},
+			{Key: "http.max_body_size"},
+			{Key: "http.cors.allow_credentials", Attribute: "cors.credentials"},
+			{Key: "http.cors.allow_origins"},
+			{Key: "pprof.watchdog.heap_threshold"},
+			{Key: "pprof.watchdog.cpu_threshold"},
+			{Key: "pprof.watchdog.max_files"},
+			{Key: "grpc.addr"},
+			{Key: "http.addr.port"},
+			{Key: "unknown"},
+		},
+	}
+	req := &conf.Server{
+		Http: &conf.Server_HTTP{
+			Addr:        "0.0.0.0:8000",
+			Envelope:    conf.Server_HTTP_ALL,
+			MaxBodySize: 1024,
+			Cors:        &conf.Server_HTTP_CORS{AllowCredentials: true, AllowOrigins: []string{"https://example.com"}},
+		},
+		Pprof: &conf.Server_Pprof{Watchdog: &conf.Server_Pprof_Watchdog{HeapThreshold: 1 << 30, CpuThreshold: 80.5, MaxFiles: 5}},
+	}
+
+	tr := &testTransport{operation: sayHello, request: headerCarrier{}, reply: headerCarrier{}}
+	tr.request.Set("X-Device", "ios")
+	ctx := transport.NewServerContext(context.Background(), tr)
+	ctx = kmetadata.NewServerContext(ctx, kmetadata.New(map[string][]string{
+		"x-md-global-user-id": {"42"},
+		"x-md-client-version": {"1.2.0"},
+	}))
+
+	var bag baggage.Baggage
+	span := serverSpan(t, ctx, func(ctx context.Context) {
+		_, _ = TraceEnrich(c)(func(ctx context.Context, _ any) (any, error) {
+			bag = baggage.FromContext(ctx)
+			return nil, nil
+		})(ctx, req)
+	})
+
+	want := map[attribute.Key]attribute.Value{
+		"user.id":                               attribute.StringValue("42"),
+		"client.version":                        attribute.StringValue("1.2.0"),
+		"device.kind":                           attribute.StringValue("ios"),
+		"request.http.addr":                     attribute.StringValue("0.0.0.0:8000"),
+		"request.http.envelope":                 attribute.StringValue("ALL"),
+		"request.http.max_body_size":            attribute.Int64Value(1024),
+		"cors.credentials":                      attribute.BoolValue(true),
+		"request.pprof.watchdog.heap_threshold": attribute.StringValue("1073741824"),
+		"request.pprof.watchdog.cpu_threshold":  attribute.Float64Value(80.5),
+		"request.pprof.watchdog.max_files":      attribute.Int64Value(5),
+	}
+	attrs := spanAttributes(span)
+	if len(attrs) != len(want) {
+		t.Errorf("attributes = %v, want %v", attrs, want)
+	}
+	for k, v := range want {
+		if attrs[k] != v {
+			t.Errorf("attribute %s = %v, want %v", k, attrs[k].Emit(), v.Emit())
+		}
+	}
+
+	if got := bag.Member("user.id").Value(); got != "42" {
+		t.Errorf("baggage user.id = %q, want 42", got)
+	}
+	if got := bag.Member("request.http.addr").Value(); got != "0.0.0.0:8000" {
+		t.Errorf("baggage request.http.addr = %q, want 0.0.0.0:8000", got)
+	}
+	if bag.Len() != 2 {
+		t.Errorf("baggage = %s, want 2 members", bag)
+	}
+}
+
+func TestTraceEnrichError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want map[attribute.Key]string
+	}{
+		{name: "no error"},
+		{name: "kratos error", err: kerrors.BadRequest("INVALID_NAME", "name is empty"), want: map[attribute.Key]string{
+			"error.reason":  "INVALID_NAME",
+			"error.message": "name is empty",
+		}},
+		{name: "cause", err: kerrors.InternalServer("DB", "query failed").WithCause(errors.New("connection refused")), want: map[attribute.Key]string{
+			"error.reason":  "DB",
+			"error.message": "query failed",
+			"error.cause":   "connection refused",
+		}},
+		{name: "plain error", err: errors.New("boom"), want: map[attribute.Key]string{
+			"error.reason":  "",
+			"error.message": "boom",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := serverSpan(t, context.Background(), func(ctx context.Context) {
+				_, _ = TraceEnrich(nil)(func(context.Context, any) (any, error) {
+					return nil, tt.err
+				})(ctx, nil)
+			})
+			attrs := spanAttributes(span)
+			if len(attrs) != len(tt.want) {
+				t.Errorf("attributes = %v, want %v", attrs, tt.want)
+			}
+			for k, v := range tt.want {
+				if got := attrs[k].AsString(); got != v {
+					t.Errorf("attribute %s = %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceEnrichPanic(t *testing.T) {
+	var recovered any
+	span := serverSpan(t, context.Background(), func(ctx context.Context) {
+		defer func() { recovered = recover() }()
+		_, _ = TraceEnrich(nil)(func(context.Context, any) (any, error) {
+			panic("boom")
+		})(ctx, nil)
+	})
+	if recovered != "boom" {
+		t.Fatalf("recovered = %v, want the panic to be rethrown", recovered)
+	}
+
+	events := span.Events()
+	if len(events) != 1 || events[0].Name != semconv.ExceptionEventName {
+		t.Fatalf("events = %v, want one exception event", events)
+	}
+	attrs := make(map[attribute.Key]string)
+	for _, kv := range events[0].Attributes {
+		attrs[kv.Key] = kv.Value.AsString()
+	}
+	if attrs[semconv.ExceptionTypeKey] != "panic" || attrs[semconv.ExceptionMessageKey] != "boom" {
+		t.Errorf("exception = %v, want the panic type and message", attrs)
+	}
+	if stack := attrs[semconv.ExceptionStacktraceKey]; !strings.Contains(stack, "TestTraceEnrichPanic") {
+		t.Errorf("stacktrace = %q, want the stack of the handler", stack)
+	}
+}
+
+// trailerStream records the gRPC trailers set by the handler.
+type trailerStream struct {
+	trailer gmetadata.MD
+}
+
+func (s *trailerStream) Method() string                { return sayHello }
+func (s *trailerStream) SetHeader(gmetadata.MD) error  { return nil }
+func (s *trailerStream) SendHeader(gmetadata.MD) error { return nil }
+func (s *trailerStream) SetTrailer(md gmetadata.MD) error {
+	s.trailer = gmetadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestTraceHeaders(t *testing.T) {
+	traceID, traceparent, serverTiming := TraceHeaders(nil)
+	if traceID != TraceIDHeader || traceparent != TraceparentHeader || serverTiming != ServerTimingHeader {
+		t.Errorf("TraceHeaders(nil) = %s, %s, %s, want the defaults", traceID, traceparent, serverTiming)
+	}
+	traceID, traceparent, serverTiming = TraceHeaders(&conf.Otel_Trace_Response{
+		TraceIdHeader:      "X-Request-Trace",
+		TraceparentHeader:  "X-Traceparent",
+		ServerTimingHeader: "X-Server-Timing",
+	})
+	if traceID != "X-Request-Trace" || traceparent != "X-Traceparent" || serverTiming != "X-Server-Timing" {
+		t.Errorf("TraceHeaders() = %s, %s, %s, want the configured names", traceID, traceparent, serverTiming)
+	}
+}
+
+func TestTraceMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *conf.Otel_Trace_Response
+		kind    transport.Kind
+		noSpan  bool
+		headers []string
+		trailer []string
+	}{
+		{name: "default", headers: []string{TraceIDHeader}},
+		{name: "no span", noSpan: true},
+		{name: "no span server timing", conf: &conf.Otel_Trace_Response{ServerTiming: true}, noSpan: true, headers: []string{ServerTimingHeader}},
+		{name: "all", conf: &conf.Otel_Trace_Response{Traceparent: true, ServerTiming: true}, headers: []string{TraceIDHeader, TraceparentHeader, ServerTimingHeader}},
+		{name: "custom names", conf: &conf.Otel_Trace_Response{
+			TraceIdHeader: "X-Request-Trace", Traceparent: true, TraceparentHeader: "X-Traceparent",
+			ServerTiming: true, ServerTimingHeader: "X-Server-Timing", ServerTimingMetric: "handler",
+		}, headers: []string{"X-Request-Trace", "X-Traceparent", "X-Server-Timing"}},
+		{name: "grpc trailer", conf: &conf.Otel_Trace_Response{Traceparent: true, ServerTiming: true, GrpcTrailer: true}, kind: transport.KindGRPC,
+			headers: []string{TraceIDHeader, TraceparentHeader, ServerTimingHeader},
+			trailer: []string{TraceIDHeader, TraceparentHeader, ServerTimingHeader}},
+		{name: "grpc without trailer", conf: &conf.Otel_Trace_Response{Traceparent: true}, kind: transport.KindGRPC, headers: []string{TraceIDHeader, TraceparentHeader}},
+		{name: "http trailer", conf: &conf.Otel_Trace_Response{GrpcTrailer: true}, headers: []string{TraceIDHeader}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &testTransport{kind: tt.kind, operation: sayHello, request: headerCarrier{}, reply: headerCarrier{}}
+			stream := &trailerStream{}
+			ctx := transport.NewServerContext(context.Background(), tr)
+			ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+			m := TraceMiddleware(tt.conf)
+			handler := func(context.Context, any) (any, error) { return "reply", nil }
+			var span sdktrace.ReadOnlySpan
+			if tt.noSpan {
+				_, _ = m(handler)(ctx, nil)
+			} else {
+				span = serverSpan(t, ctx, func(ctx context.Context) {
+					_, _ = m(handler)(ctx, nil)
+				})
+			}
+
+			keys := tr.reply.Keys()
+			if len(keys) != len(tt.headers) {
+				t.Errorf("reply headers = %v, want %v", keys, tt.headers)
+			}
+			traceIDHeader, traceparentHeader, serverTimingHeader := TraceHeaders(tt.conf)
+			for _, h := range tt.headers {
+				v := tr.reply.Get(h)
+				switch h {
+				case traceIDHeader:
+					if v != span.SpanContext().TraceID().String() {
+						t.Errorf("%s = %q, want the trace id %s", h, v, span.SpanContext().TraceID())
+					}
+				case traceparentHeader:
+					// the traceparent is the W3C trace context of the server span
+					sc := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{"traceparent": v}))
+					if !sc.Equal(span.SpanContext().WithRemote(true)) {
+						t.Errorf("%s = %q, want the server span %s", h, v, span.SpanContext().SpanID())
+					}
+				case serverTimingHeader:
+					metric := tt.conf.GetServerTimingMetric()
+					if metric == "" {
+						metric = defaultServerTimingMetric
+					}
+					dur, ok := strings.CutPrefix(v, metric+";dur=")
+					if _, err := strconv.ParseFloat(dur, 64); !ok || err != nil {
+						t.Errorf("%s = %q, want %s;dur=<ms>", h, v, metric)
+					}
+				}
+			}
+
+			if len(stream.trailer) != len(tt.trailer) {
+				t.Errorf("trailer = %v, want %v", stream.trailer, tt.trailer)
+			}
+			for _, h := range tt.trailer {
+				if got := stream.trailer.Get(h); len(got) != 1 || got[0] != tr.reply.Get(h) {
+					t.Errorf("trailer %s = %v, want the reply header %q", h, got, tr.reply.Get(h))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/encoding"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	envelopeContentType = "application/json"
+	envelopeMessageOK   = "OK"
+)
+
+// envelope is the json body shared by replies and errors.
+type envelope struct {
+	// Code is the http status code.
+	Code int32 `json:"code"`
+	// Reason is the kratos reason of the error, eg: USER_NOT_FOUND of the
+	// ErrorReason enum of the api, empty on success.
+	Reason string `json:"reason"`
+	// ReasonCode is the ErrorReason enum value of the reason, omitted when the
+	// reason is not declared in the api.
+	ReasonCode int32             `json:"reason_code,omitempty"`
+	Message    string            `json:"message"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Data       json.RawMessage   `json:"data,omitempty"`
+	TraceID    string            `json:"trace_id"`
+	RequestID  string            `json:"request_id"`
+}
+
+// errorReasonCodes maps the ErrorReason enums of the api to the reason code.
+var errorReasonCodes = map[string]int32{}
+
+func init() {
+	for name, value := range v1.ErrorReason_value {
+		errorReasonCodes[name] = value
+	}
+}
+
+// newEnvelopeErrorEncoder encodes the error into the envelope, keeping the
+// http status code of the kratos error.
+func newEnvelopeErrorEncoder(traceIDHeader string) http.EncodeErrorFunc {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		se := errors.FromError(err)
+		writeEnvelope(w, r, int(se.Code), traceIDHeader, &envelope{
+			Code:       se.Code,
+			Reason:     se.Reason,
+			ReasonCode: errorReasonCodes[se.Reason],
+			Message:    se.Message,
+			Metadata:   se.Metadata,
+		})
+	}
+}
+
+// newEnvelopeResponseEncoder encodes the reply as the data of the envelope.
+func newEnvelopeResponseEncoder(traceIDHeader string) http.EncodeResponseFunc {
+	return func(w http.ResponseWriter, r *http.Request, v any) error {
+		if rd, ok := v.(http.Redirector); ok {
+			url, code := rd.Redirect()
+			nethttp.Redirect(w, r, url, code)
+			return nil
+		}
+		e := &envelope{Code: nethttp.StatusOK, Message: envelopeMessageOK}
+		if v != nil {
+			data, err := encoding.GetCodec("json").Marshal(v)
+			if err != nil {
+				return err
+			}
+			e.Data = data
+		}
+		writeEnvelope(w, r, nethttp.StatusOK, traceIDHeader, e)
+		return nil
+	}
+}
+
+func writeEnvelope(w http.ResponseWriter, r *http.Request, status int, traceIDHeader string, e *envelope) {
+	// the ids are set on the reply header by the middlewares
+	e.RequestID = w.Header().Get(metadata.KeyRequestID)
+	if e.RequestID == "" {
+		// the errors of the filters are encoded before the middlewares
+		e.RequestID = uuid.NewString()
+		w.Header().Set(metadata.KeyRequestID, e.RequestID)
+	}
+	e.TraceID = envelopeTraceID(w, r, traceIDHeader)
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		w.WriteHeader(nethttp.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", envelopeContentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// envelopeTraceID returns the trace id of the reply header, else the one
+// propagated by the caller when the span is not recorded or tracing is off,
+// empty when there is no trace at all.
+func envelopeTraceID(w http.ResponseWriter, r *http.Request, traceIDHeader string) string {
+	if id := w.Header().Get(traceIDHeader); id != "" {
+		return id
+	}
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/go-kratos/kratos-layout/api/helloworld/v1"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+const testTraceIDHeader = "X-Trace-Id"
+
+func decodeEnvelope(t *testing.T, w *httptest.ResponseRecorder) *envelope {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != envelopeContentType {
+		t.Fatalf("content type = %q, want %q", ct, envelopeContentType)
+	}
+	var e envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	return &e
+}
+
+func TestEnvelopeResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set(metadata.KeyRequestID, "req-1")
+	w.Header().Set(testTraceIDHeader, "4bf92f3577b34da6a3ce929d0e0e4736")
+	r := httptest.NewRequest(http.MethodGet, "/helloworld/kratos", nil)
+
+	if err := newEnvelopeResponseEncoder(testTraceIDHeader)(w, r, &v1.HelloReply{Message: "Hello kratos"}); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	e := decodeEnvelope(t, w)
+	if e.Code != http.StatusOK || e.Reason != "" || e.ReasonCode != 0 || e.Message != envelopeMessageOK {
+		t.Errorf("envelope = %+v, want ok", e)
+	}
+	if string(e.Data) != `{"message":"Hello kratos"}` {
+		t.Errorf("data = %s", e.Data)
+	}
+	if e.RequestID != "req-1" || e.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("ids = %q, %q", e.RequestID, e.TraceID)
+	}
+}
+
+func TestEnvelopeError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		reason     string
+		reasonCode int32
+	}{
+		{name: "api reason", err: errors.NotFound(v1.ErrorReason_USER_NOT_FOUND.String(), "user not found"), status: http.StatusNotFound, reason: "USER_NOT_FOUND", reasonCode: int32(v1.ErrorReason_USER_NOT_FOUND)},
+		{name: "undeclared reason", err: errors.Forbidden("RATELIMIT", "forbidden"), status: http.StatusForbidden, reason: "RATELIMIT"},
+		{name: "plain error", err: errors.New(http.StatusInternalServerError, "", "boom"), status: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/helloworld/kratos", nil)
+			newEnvelopeErrorEncoder(testTraceIDHeader)(w, r, tt.err)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			e := decodeEnvelope(t, w)
+			if int(e.Code) != tt.status || e.Reason != tt.reason || e.ReasonCode != tt.reasonCode {
+				t.Errorf("envelope = %+v, want code %d reason %q(%d)", e, tt.status, tt.reason, tt.reasonCode)
+			}
+			// the filters errors are encoded before the request id middleware
+			if e.RequestID == "" || w.Header().Get(metadata.KeyRequestID) != e.RequestID {
+				t.Errorf("request id = %q, header %q", e.RequestID, w.Header().Get(metadata.KeyRequestID))
+			}
+			if e.TraceID != "" {
+				t.Errorf("trace id = %q, want empty without a span", e.TraceID)
+			}
+		})
+	}
+}
+
+func TestEnvelopeTraceID(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/helloworld/kratos", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	if got := envelopeTraceID(w, r, testTraceIDHeader); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %q, want the propagated one", got)
+	}
+}
+
+type redirect struct{}
+
+func (redirect) Redirect() (string, int) { return "/v2/greeter", http.StatusMovedPermanently }
+
+func TestEnvelopePassthrough(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/v1/greeter", nil)
+
+	if err := newEnvelopeResponseEncoder(testTraceIDHeader)(w, r, redirect{}); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/v2/greeter" {
+		t.Errorf("status = %d, location = %q, want the redirect", w.Code, w.Header().Get("Location"))
+	}
+	if w.Header().Get("Content-Type") == envelopeContentType {
+		t.Error("redirect is wrapped in the envelope")
+	}
+}
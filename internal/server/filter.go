@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+var (
+	defaultCORSMethods = []string{
+		nethttp.MethodGet,
+		nethttp.MethodPost,
+		nethttp.MethodPut,
+		nethttp.MethodPatch,
+		nethttp.MethodDelete,
+		nethttp.MethodHead,
+	}
+)
+
+// ErrRequestEntityTooLarge is the request body exceeds the configured limit.
+var ErrRequestEntityTooLarge = errors.New(nethttp.StatusRequestEntityTooLarge, "REQUEST_ENTITY_TOO_LARGE", "request body too large")
+
+// ErrCORSOriginNotAllowed is the preflight origin is not allowed.
+var ErrCORSOriginNotAllowed = errors.Forbidden("CORS", "origin not allowed")
+
+// corsFilter answers the preflight requests and sets the CORS headers of the
+// allowed origins, the trace and request id headers are exposed by default.
+func corsFilter(c *conf.Server_HTTP_CORS, traceHeaders []string, enc http.EncodeErrorFunc) http.FilterFunc {
+	methods := strings.Join(defaultCORSMethods, ", ")
+	if len(c.GetAllowMethods()) > 0 {
+		methods = strings.Join(c.GetAllowMethods(), ", ")
+	}
+	headers := strings.Join(c.GetAllowHeaders(), ", ")
+	expose := strings.Join(append(traceHeaders, metadata.KeyRequestID), ", ")
+	if len(c.GetExposeHeaders()) > 0 {
+		expose = strings.Join(c.GetExposeHeaders(), ", ")
+	}
+	var maxAge string
+	if c.GetMaxAge().AsDuration() > 0 {
+		maxAge = strconv.Itoa(int(c.GetMaxAge().AsDuration().Seconds()))
+	}
+	anyOrigin := slices.Contains(c.GetAllowOrigins(), "*")
+
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			preflight := r.Method == nethttp.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !allowOrigin(c.GetAllowOrigins(), origin) {
+				if preflight {
+					enc(w, r, ErrCORSOriginNotAllowed)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// "*" with credentials is rejected by the config validation
+			if anyOrigin {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if c.GetAllowCredentials() {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				h.Set("Access-Control-Expose-Headers", expose)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", methods)
+			if headers != "" {
+				h.Set("Access-Control-Allow-Headers", headers)
+			} else if reqHeaders := r.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
+				h.Set("Access-Control-Allow-Headers", reqHeaders)
+			}
+			if maxAge != "" {
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(nethttp.StatusNoContent)
+		})
+	}
+}
+
+func allowOrigin(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		switch {
+		case o == "*":
+			return true
+		case strings.EqualFold(o, origin):
+			return true
+		case strings.Contains(o, "://*."):
+			// https://*.example.com matches https://a.example.com
+			scheme, domain, _ := strings.Cut(o, "://*")
+			if strings.HasPrefix(strings.ToLower(origin), strings.ToLower(scheme)+"://") &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(domain)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// securityHeadersFilter sets the standard security headers on every reply.
+func securityHeadersFilter(c *conf.Server_HTTP_SecurityHeaders) http.FilterFunc {
+	frameOptions := "DENY"
+	if c.GetFrameOptions() != "" {
+		frameOptions = c.GetFrameOptions()
+	}
+	var hsts string
+	if c.GetHstsMaxAge().AsDuration() > 0 {
+		hsts = fmt.Sprintf("max-age=%d", int64(c.GetHstsMaxAge().AsDuration().Seconds()))
+		if c.GetHstsIncludeSubdomains() {
+			hsts += "; includeSubDomains"
+		}
+		if c.GetHstsPreload() {
+			hsts += "; preload"
+		}
+	}
+
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("X-Frame-Options", frameOptions)
+			if hsts != "" {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+			if c.GetReferrerPolicy() != "" {
+				h.Set("Referrer-Policy", c.GetReferrerPolicy())
+			}
+			if c.GetContentSecurityPolicy() != "" {
+				h.Set("Content-Security-Policy", c.GetContentSecurityPolicy())
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bodyLimitFilter rejects the requests whose body exceeds the limit of the
+// longest matching route prefix, or the default limit.
+func bodyLimitFilter(limit int64, routes map[string]int64, enc http.EncodeErrorFunc) http.FilterFunc {
+	prefixes := make([]string, 0, len(routes))
+	for p := range routes {
+		prefixes = append(prefixes, p)
+	}
+	// longest prefix first
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			max := limit
+			for _, p := range prefixes {
+				if matchRoute(r.URL.Path, p) {
+					max = routes[p]
+					break
+				}
+			}
+			if max <= 0 || r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > max {
+				enc(w, r, ErrRequestEntityTooLarge)
+				return
+			}
+			r.Body = nethttp.MaxBytesReader(w, r.Body, max)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchRoute reports whether the path is the prefix or below it, so that
+// /v1/greeter does not match /v1/greeterx.
+func matchRoute(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// bodyLimitDecoder reads the body before the default decoder, which reports
+// the limit of the chunked bodies cut by bodyLimitFilter as a CODEC error.
+func bodyLimitDecoder(r *http.Request, v any) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var mbe *nethttp.MaxBytesError
+		if errors.As(err, &mbe) {
+			return ErrRequestEntityTooLarge.WithCause(err)
+		}
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	return http.DefaultRequestDecoder(r, v)
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/errors"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		chunked bool
+		code    int
+	}{
+		{name: "under the limit", path: "/v1/greeter", body: `{"name":"kratos"}`, code: http.StatusOK},
+		{name: "content length over the limit", path: "/v1/greeter", body: `{"name":"` + strings.Repeat("a", 64) + `"}`, code: http.StatusRequestEntityTooLarge},
+		{name: "chunked over the limit", path: "/v1/greeter", body: `{"name":"` + strings.Repeat("a", 64) + `"}`, chunked: true, code: http.StatusRequestEntityTooLarge},
+		{name: "chunked over the route limit", path: "/helloworld/kratos", body: `{"name":"kratos"}`, chunked: true, code: http.StatusRequestEntityTooLarge},
+		{name: "route prefix on the segment boundary", path: "/helloworldx", body: `{"name":"kratos"}`, chunked: true, code: http.StatusOK},
+		{name: "bad json", path: "/v1/greeter", body: `{"name":`, chunked: true, code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var v map[string]any
+				if err := bodyLimitDecoder(r, &v); err != nil {
+					khttp.DefaultErrorEncoder(w, r, err)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+			filter := bodyLimitFilter(32, map[string]int64{"/helloworld": 8}, khttp.DefaultErrorEncoder)
+
+			r := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			if tt.chunked {
+				// unknown length, only the MaxBytesReader catches it
+				r.ContentLength = -1
+				r.Body = io.NopCloser(strings.NewReader(tt.body))
+			}
+			rec := httptest.NewRecorder()
+			filter(handler).ServeHTTP(rec, r)
+			if rec.Code != tt.code {
+				t.Errorf("code = %d, want %d: %s", rec.Code, tt.code, rec.Body.String())
+			}
+			if tt.code == http.StatusRequestEntityTooLarge && !strings.Contains(rec.Body.String(), errors.Reason(ErrRequestEntityTooLarge)) {
+				t.Errorf("body = %s, want reason %s", rec.Body.String(), errors.Reason(ErrRequestEntityTooLarge))
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *conf.Server_HTTP_CORS
+		method  string
+		origin  string
+		code    int
+		headers map[string]string
+	}{
+		{
+			name:    "no origin",
+			conf:    &conf.Server_HTTP_CORS{AllowOrigins: []string{"https://example.com"}},
+			method:  http.MethodGet,
+			code:    http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "allowed origin",
+			conf:   &conf.Server_HTTP_CORS{AllowOrigins: []string{"https://example.com"}, AllowCredentials: true},
+			method: http.MethodGet,
+			origin: "https://example.com",
+			code:   http.StatusOK,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":      "https://example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Trace-Id, " + metadata.KeyRequestID,
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:    "any origin",
+			conf:    &conf.Server_HTTP_CORS{AllowOrigins: []string{"*"}},
+			method:  http.MethodGet,
+			origin:  "https://example.com",
+			code:    http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+		{
+			name:    "wildcard subdomain",
+			conf:    &conf.Server_HTTP_CORS{AllowOrigins: []string{"https://*.example.com"}},
+			method:  http.MethodGet,
+			origin:  "https://a.example.com",
+			code:    http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "https://a.example.com"},
+		},
+		{
+			name:    "wildcard subdomain other scheme",
+			conf:    &conf.Server_HTTP_CORS{AllowOrigins: []string{"https://*.example.com"}},
+			method:  http.MethodGet,
+			origin:  "http://a.example.com",
+			code:    http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "preflight",
+			conf:   &conf.Server_HTTP_CORS{AllowOrigins: []string{"https://example.com"}, MaxAge: durationpb.New(10 * time.Minute)},
+			method: http.MethodOptions,
+			origin: "https://example.com",
+			code:   http.StatusNoContent,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":  "https://example.com",
+				"Access-Control-Allow-Methods": "GET, POST, PUT, PATCH, DELETE, HEAD",
+				"Access-Control-Allow-Headers": "Authorization",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:   "preflight configured",
+			conf:   &conf.Server_HTTP_CORS{AllowOrigins: []string{"https://example.com"}, AllowMethods: []string{"GET"}, AllowHeaders: []string{"X-Custom"}},
+			method: http.MethodOptions,
+			origin: "https://example.com",
+			code:   http.StatusNoContent,
+			headers: map[string]string{
+				"Access-Control-Allow-Methods": "GET",
+				"Access-Control-Allow-Headers": "X-Custom",
+			},
+		},
+		{
+			name:    "preflight origin not allowed",
+			conf:    &conf.Server_HTTP_CORS{AllowOrigins: []string{"https://example.com"}},
+			method:  http.MethodOptions,
+			origin:  "https://evil.com",
+			code:    http.StatusForbidden,
+			headers: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			filter := corsFilter(tt.conf, []string{"X-Trace-Id"}, khttp.DefaultErrorEncoder)
+
+			r := httptest.NewRequest(tt.method, "/helloworld/kratos", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				r.Header.Set("Access-Control-Request-Headers", "Authorization")
+			}
+			rec := httptest.NewRecorder()
+			filter(handler).ServeHTTP(rec, r)
+			if rec.Code != tt.code {
+				t.Errorf("code = %d, want %d: %s", rec.Code, tt.code, rec.Body.String())
+			}
+			for k, v := range tt.headers {
+				if got := rec.Header().Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/go-kratos/kratos-layout/internal/conf"
 	"github.com/go-kratos/kratos-layout/internal/middleware"
 	"github.com/go-kratos/kratos-layout/internal/service"
+	"github.com/go-kratos/kratos-layout/pkg/audit"
+	"github.com/go-kratos/kratos-layout/pkg/idempotency"
 	"github.com/go-kratos/kratos/contrib/middleware/validate/v2"
 	"github.com/go-kratos/kratos/v2/middleware/logging"
 	"github.com/go-kratos/kratos/v2/middleware/metadata"
@@ -18,7 +20,7 @@ import (
 )
 
 // NewGRPCServer new a gRPC server.
-func NewGRPCServer(bc *conf.Bootstrap, gs []GrpcService, logger log.Logger, meter metric.Meter, tp trace.TracerProvider) *grpc.Server {
+func NewGRPCServer(bc *conf.Bootstrap, gs []GrpcService, logger log.Logger, meter metric.Meter, mp metric.MeterProvider, tp trace.TracerProvider, authenticator *middleware.Authenticator, authorizer *middleware.Authorizer, idempotencyStore idempotency.Store, auditSink audit.Sink) *grpc.Server {
 	counter, err := metrics.DefaultRequestsCounter(meter, metrics.DefaultServerRequestsCounterName)
 	if err != nil {
 		panic(err)
@@ -27,6 +29,10 @@ func NewGRPCServer(bc *conf.Bootstrap, gs []GrpcService, logger log.Logger, mete
 	if err != nil {
 		panic(err)
 	}
+	operationMetrics, err := middleware.Metrics(mp, bc.GetMetadata().GetName(), bc.GetOtel().GetMetric().GetServer())
+	if err != nil {
+		panic(err)
+	}
 
 	ls := make([]*middleware.LimiterConfig, 0, len(gs))
 	for _, g := range gs {
@@ -36,13 +42,23 @@ func NewGRPCServer(bc *conf.Bootstrap, gs []GrpcService, logger log.Logger, mete
 	var opts = []grpc.ServerOption{
 		grpc.Middleware(
 			recovery.Recovery(),
+			middleware.RequestID(),
 			tracing.Server(tracing.WithTracerProvider(tp)),
+			middleware.TraceMiddleware(bc.GetOtel().GetTrace().GetResponse()),
+			middleware.TraceEnrich(bc.GetOtel().GetTrace().GetEnrich()),
 			validate.ProtoValidate(),
 			logging.Server(logger),
 			metrics.Server(metrics.WithRequests(counter), metrics.WithSeconds(seconds)),
+			operationMetrics,
 			metadata.Server(),
-			middleware.TraceMiddleware(),
-			middleware.Limiter(ls, middleware.WithBBR(bc.GetBbr())),
+			middleware.Audit(auditSink, bc.GetAudit(), logger),
+			middleware.Auth(authenticator),
+			middleware.Tenant(bc.GetTenant()),
+			middleware.AuditCaller(),
+			middleware.Authz(authorizer),
+			middleware.ProfilerLabels(),
+			middleware.Limiter(ls, middleware.WithBBR(bc.GetBbr()), middleware.WithRateLimits(bc.GetRateLimits())),
+			middleware.Idempotency(idempotencyStore, bc.GetIdempotency()),
 		),
 	}
 	s := bc.GetServer()
@@ -4,6 +4,8 @@ import (
 	"github.com/go-kratos/kratos-layout/internal/conf"
 	"github.com/go-kratos/kratos-layout/internal/middleware"
 	"github.com/go-kratos/kratos-layout/internal/service"
+	"github.com/go-kratos/kratos-layout/pkg/audit"
+	"github.com/go-kratos/kratos-layout/pkg/idempotency"
 	"github.com/go-kratos/kratos/contrib/middleware/validate/v2"
 	"github.com/go-kratos/kratos/v2/middleware/logging"
 	"github.com/go-kratos/kratos/v2/middleware/metadata"
@@ -20,7 +22,7 @@ import (
 )
 
 // NewHTTPServer new an HTTP server.
-func NewHTTPServer(bc *conf.Bootstrap, hs []HttpService, logger log.Logger, meter metric.Meter, tp trace.TracerProvider) *http.Server {
+func NewHTTPServer(bc *conf.Bootstrap, hs []HttpService, logger log.Logger, meter metric.Meter, mp metric.MeterProvider, tp trace.TracerProvider, authenticator *middleware.Authenticator, authorizer *middleware.Authorizer, idempotencyStore idempotency.Store, auditSink audit.Sink) *http.Server {
 	counter, err := metrics.DefaultRequestsCounter(meter, metrics.DefaultServerRequestsCounterName)
 	if err != nil {
 		panic(err)
@@ -29,6 +31,10 @@ func NewHTTPServer(bc *conf.Bootstrap, hs []HttpService, logger log.Logger, mete
 	if err != nil {
 		panic(err)
 	}
+	operationMetrics, err := middleware.Metrics(mp, bc.GetMetadata().GetName(), bc.GetOtel().GetMetric().GetServer())
+	if err != nil {
+		panic(err)
+	}
 
 	ls := make([]*middleware.LimiterConfig, 0, len(hs))
 	for _, h := range hs {
@@ -38,13 +44,23 @@ func NewHTTPServer(bc *conf.Bootstrap, hs []HttpService, logger log.Logger, mete
 	var opts = []http.ServerOption{
 		http.Middleware(
 			recovery.Recovery(),
-			validate.ProtoValidate(),
+			middleware.RequestID(),
 			tracing.Server(tracing.WithTracerProvider(tp)),
+			middleware.TraceMiddleware(bc.GetOtel().GetTrace().GetResponse()),
+			middleware.TraceEnrich(bc.GetOtel().GetTrace().GetEnrich()),
+			validate.ProtoValidate(),
 			logging.Server(logger),
 			metrics.Server(metrics.WithRequests(counter), metrics.WithSeconds(seconds)),
+			operationMetrics,
 			metadata.Server(),
-			middleware.TraceMiddleware(),
-			middleware.Limiter(ls, middleware.WithBBR(bc.GetBbr())),
+			middleware.Audit(auditSink, bc.GetAudit(), logger),
+			middleware.Auth(authenticator),
+			middleware.Tenant(bc.GetTenant()),
+			middleware.AuditCaller(),
+			middleware.Authz(authorizer),
+			middleware.ProfilerLabels(),
+			middleware.Limiter(ls, middleware.WithBBR(bc.GetBbr()), middleware.WithRateLimits(bc.GetRateLimits())),
+			middleware.Idempotency(idempotencyStore, bc.GetIdempotency()),
 		),
 	}
 	c := bc.GetServer()
@@ -57,6 +73,37 @@ func NewHTTPServer(bc *conf.Bootstrap, hs []HttpService, logger log.Logger, mete
 	if c.Http.GetTimeout() != nil {
 		opts = append(opts, http.Timeout(c.Http.GetTimeout().AsDuration()))
 	}
+	traceIDHeader, traceparentHeader, serverTimingHeader := middleware.TraceHeaders(bc.GetOtel().GetTrace().GetResponse())
+	errorEncoder := http.DefaultErrorEncoder
+	switch c.Http.GetEnvelope() {
+	case conf.Server_HTTP_ERROR:
+		errorEncoder = newEnvelopeErrorEncoder(traceIDHeader)
+		opts = append(opts, http.ErrorEncoder(errorEncoder))
+	case conf.Server_HTTP_ALL:
+		errorEncoder = newEnvelopeErrorEncoder(traceIDHeader)
+		opts = append(opts, http.ErrorEncoder(errorEncoder), http.ResponseEncoder(newEnvelopeResponseEncoder(traceIDHeader)))
+	}
+	var filters []http.FilterFunc
+	if c.Http.GetSecurityHeaders().GetEnable() {
+		filters = append(filters, securityHeadersFilter(c.Http.GetSecurityHeaders()))
+	}
+	if c.Http.GetCors().GetEnable() {
+		traceHeaders := []string{traceIDHeader}
+		if bc.GetOtel().GetTrace().GetResponse().GetTraceparent() {
+			traceHeaders = append(traceHeaders, traceparentHeader)
+		}
+		if bc.GetOtel().GetTrace().GetResponse().GetServerTiming() {
+			traceHeaders = append(traceHeaders, serverTimingHeader)
+		}
+		filters = append(filters, corsFilter(c.Http.GetCors(), traceHeaders, errorEncoder))
+	}
+	if c.Http.GetMaxBodySize() > 0 || len(c.Http.GetRouteMaxBodySize()) > 0 {
+		filters = append(filters, bodyLimitFilter(c.Http.GetMaxBodySize(), c.Http.GetRouteMaxBodySize(), errorEncoder))
+		opts = append(opts, http.RequestDecoder(bodyLimitDecoder))
+	}
+	if len(filters) > 0 {
+		opts = append(opts, http.Filter(filters...))
+	}
 	srv := http.NewServer(opts...)
 	srv.HandlePrefix("/metrics", promhttp.HandlerFor(
 		prometheus.DefaultGatherer,
@@ -10,6 +10,7 @@ import (
 	"github.com/go-kratos/kratos-layout/internal/conf"
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type PprofServer struct {
@@ -20,15 +21,28 @@ type PprofServer struct {
 	listener net.Listener
 	srv      *http.Server
 
+	watchdog *Watchdog
+	pusher   *Pusher
+
 	log *log.Helper
 }
 
-func NewPprof(bc *conf.Bootstrap, logger log.Logger) (*PprofServer, error) {
+func NewPprof(bc *conf.Bootstrap, logger log.Logger, tp trace.TracerProvider) (*PprofServer, error) {
 	s := bc.Server
 	pprof := &PprofServer{
 		conf: s.GetPprof(),
 		log:  log.NewHelper(logger, log.WithMessageKey("pprof")),
 	}
+	watchdog, err := newWatchdog(s.GetPprof().GetWatchdog(), tp, pprof.log)
+	if err != nil {
+		return nil, err
+	}
+	pprof.watchdog = watchdog
+	pusher, err := newPusher(bc, pprof.log)
+	if err != nil {
+		return nil, err
+	}
+	pprof.pusher = pusher
 	addr := s.GetPprof().GetAddr()
 	if addr != "" {
 		listener, err := net.Listen("tcp", addr)
@@ -36,12 +50,20 @@ func NewPprof(bc *conf.Bootstrap, logger log.Logger) (*PprofServer, error) {
 			return nil, err
 		}
 		pprof.listener = listener
-		pprof.srv = &http.Server{}
+		pprof.srv = &http.Server{Handler: pprofHandler(http.DefaultServeMux)}
 	}
 	return pprof, nil
 }
 
 func (s *PprofServer) Start(ctx context.Context) error {
+	if s.watchdog != nil {
+		s.watchdog.Start()
+		s.log.WithContext(ctx).Infof("[Pprof] watchdog started, profiles are written to %s", s.watchdog.dir)
+	}
+	if s.pusher != nil {
+		s.pusher.Start()
+		s.log.WithContext(ctx).Infof("[Pprof] pusher started, profiles are pushed to %s", s.pusher.endpoint.Redacted())
+	}
 	if s.listener == nil {
 		return nil
 	}
@@ -59,8 +81,32 @@ func (s *PprofServer) Start(ctx context.Context) error {
 
 func (s *PprofServer) Stop(ctx context.Context) error {
 	s.log.WithContext(ctx).Info("[Pprof] server stopped")
+	if s.watchdog != nil {
+		s.watchdog.Stop()
+	}
+	if s.pusher != nil {
+		s.pusher.Stop()
+	}
 	if atomic.LoadUint32(&s.started) == 1 {
 		return s.srv.Shutdown(ctx)
 	}
 	return nil
 }
+
+// pprofHandler rejects /debug/pprof/profile while the watchdog or the pusher
+// profiles the cpu, and keeps them from starting one meanwhile.
+func pprofHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/debug/pprof/profile" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !cpuProfileMu.TryLock() {
+			w.Header().Set("Retry-After", "10")
+			http.Error(w, errCPUProfileBusy.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer cpuProfileMu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
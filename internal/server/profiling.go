@@ -0,0 +1,274 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+const (
+	defaultPushInterval = 15 * time.Second
+	defaultPushTimeout  = 10 * time.Second
+)
+
+// cpuProfileMu serializes the cpu profiles taken by the watchdog, the
+// pusher and /debug/pprof/profile, the runtime only supports one cpu profile
+// at a time.
+var cpuProfileMu sync.Mutex
+
+// errCPUProfileBusy is returned when another cpu profile is in progress, the
+// profile is skipped rather than waiting for it.
+var errCPUProfileBusy = errors.New("cpu profile in progress")
+
+// writeCPUProfile profiles the cpu for d, or until ctx is done.
+func writeCPUProfile(ctx context.Context, w io.Writer, d time.Duration) error {
+	if !cpuProfileMu.TryLock() {
+		return errCPUProfileBusy
+	}
+	defer cpuProfileMu.Unlock()
+
+	if err := pprof.StartCPUProfile(w); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+	pprof.StopCPUProfile()
+	return nil
+}
+
+// Pusher periodically uploads cpu, heap and goroutine profiles to a
+// pyroscope compatible collector.
+type Pusher struct {
+	endpoint *url.URL
+	interval time.Duration
+	// the cpu is profiled for a part of the interval, so the profiler is free
+	// for the watchdog and /debug/pprof/profile the rest of it
+	cpuDuration time.Duration
+	profiles    []string
+	headers     map[string]string
+	name        string
+
+	client *http.Client
+
+	once   sync.Once
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	log *log.Helper
+}
+
+func newPusher(bc *conf.Bootstrap, logger *log.Helper) (*Pusher, error) {
+	c := bc.GetServer().GetPprof().GetPush()
+	if !c.GetEnable() {
+		return nil, nil
+	}
+	endpoint, err := url.Parse(c.GetEndpoint())
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid pprof push endpoint: %q", c.GetEndpoint())
+	}
+
+	p := &Pusher{
+		endpoint: endpoint,
+		interval: defaultPushInterval,
+		profiles: []string{profileCPU, profileHeap, profileGoroutine},
+		headers:  c.GetHeaders(),
+		client:   &http.Client{Timeout: defaultPushTimeout},
+		done:     make(chan struct{}),
+		log:      logger,
+	}
+	if c.GetInterval().AsDuration() > 0 {
+		p.interval = c.GetInterval().AsDuration()
+	}
+	if c.GetTimeout().AsDuration() > 0 {
+		p.client.Timeout = c.GetTimeout().AsDuration()
+	}
+	p.cpuDuration = p.interval / 2
+	if d := c.GetCpuDuration().AsDuration(); d > 0 && d < p.interval {
+		p.cpuDuration = d
+	}
+	if len(c.GetProfiles()) > 0 {
+		p.profiles = c.GetProfiles()
+	}
+	for _, name := range p.profiles {
+		switch name {
+		case profileCPU, profileHeap, profileGoroutine:
+		default:
+			return nil, fmt.Errorf("unexpected pprof push profile: %s", name)
+		}
+	}
+
+	md := bc.GetMetadata()
+	labels := map[string]string{
+		"service.name":    md.GetName(),
+		"service.version": md.GetVersion(),
+		"id":              md.GetId(),
+	}
+	for k, v := range c.GetLabels() {
+		labels[k] = v
+	}
+	p.name = appName(md.GetName(), labels)
+	return p, nil
+}
+
+// appName encodes the labels the way the pyroscope ingest api expects,
+// eg: helloworld{id=host-1,service.name=helloworld}
+func appName(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k, v := range labels {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (p *Pusher) Start() {
+	p.once.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		p.cancel = cancel
+		go p.run(ctx)
+	})
+}
+
+func (p *Pusher) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	<-p.done
+}
+
+func (p *Pusher) run(ctx context.Context) {
+	defer close(p.done)
+	for {
+		from := time.Now()
+		cpu, err := p.collectCPU(ctx)
+		switch {
+		case errors.Is(err, errCPUProfileBusy):
+			p.log.WithContext(ctx).Debugf("[Pprof] push skip cpu profile: %v", err)
+		case err != nil:
+			p.log.WithContext(ctx).Warnf("[Pprof] push collect cpu profile: %v", err)
+		}
+		cpuUntil := time.Now()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.interval - time.Since(from)):
+		}
+		until := time.Now()
+
+		if cpu != nil {
+			p.upload(ctx, profileCPU, cpu, from, cpuUntil)
+		}
+		for _, name := range p.profiles {
+			if name == profileCPU {
+				continue
+			}
+			var buf bytes.Buffer
+			if err = pprof.Lookup(name).WriteTo(&buf, 0); err != nil {
+				p.log.WithContext(ctx).Warnf("[Pprof] push collect %s profile: %v", name, err)
+				continue
+			}
+			p.upload(ctx, name, buf.Bytes(), from, until)
+		}
+	}
+}
+
+func (p *Pusher) collectCPU(ctx context.Context) ([]byte, error) {
+	for _, name := range p.profiles {
+		if name == profileCPU {
+			var buf bytes.Buffer
+			if err := writeCPUProfile(ctx, &buf, p.cpuDuration); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *Pusher) upload(ctx context.Context, name string, profile []byte, from, until time.Time) {
+	if err := p.post(ctx, name, profile, from, until); err != nil {
+		p.log.WithContext(ctx).Warnf("[Pprof] push %s profile to %s: %v", name, p.endpoint.Host, err)
+	}
+}
+
+func (p *Pusher) post(ctx context.Context, name string, profile []byte, from, until time.Time) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fw, err := writer.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return err
+	}
+	if _, err = fw.Write(profile); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	units, aggregation := "samples", "sum"
+	switch name {
+	case profileHeap:
+		units, aggregation = "bytes", "average"
+	case profileGoroutine:
+		units, aggregation = "goroutines", "average"
+	}
+
+	u := *p.endpoint
+	u.Path = path.Join(u.Path, "ingest")
+	q := u.Query()
+	q.Set("name", p.name)
+	q.Set("from", strconv.FormatInt(from.UnixNano(), 10))
+	q.Set("until", strconv.FormatInt(until.UnixNano(), 10))
+	q.Set("spyName", "gospy")
+	q.Set("sampleRate", "100")
+	q.Set("units", units)
+	q.Set("aggregationType", aggregation)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.New(resp.Status + ": " + string(msg))
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ingest is a stub of the pyroscope ingest api recording the pushes.
+type ingest struct {
+	mu     sync.Mutex
+	pushes map[string]*http.Request
+	sizes  map[string]int
+	got    chan string
+}
+
+func newIngest() *ingest {
+	return &ingest{pushes: make(map[string]*http.Request), sizes: make(map[string]int), got: make(chan string, 64)}
+}
+
+func (i *ingest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/ingest" {
+		http.NotFound(w, r)
+		return
+	}
+	f, _, err := r.FormFile("profile")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	profile, _ := io.ReadAll(f)
+	name := r.URL.Query().Get("units")
+	i.mu.Lock()
+	i.pushes[name] = r
+	i.sizes[name] = len(profile)
+	i.mu.Unlock()
+	i.got <- name
+}
+
+func TestPusher(t *testing.T) {
+	stub := newIngest()
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	bc := &conf.Bootstrap{
+		Metadata: &conf.MetaData{Name: "helloworld", Version: "v1.0.0", Id: "host-1"},
+		Server: &conf.Server{Pprof: &conf.Server_Pprof{Push: &conf.Server_Pprof_Push{
+			Enable:      true,
+			Endpoint:    srv.URL,
+			Interval:    durationpb.New(200 * time.Millisecond),
+			CpuDuration: durationpb.New(50 * time.Millisecond),
+			Labels:      map[string]string{"region": "eu"},
+			Headers:     map[string]string{"X-Scope-OrgID": "tenant-1"},
+		}}},
+	}
+	p, err := newPusher(bc, log.NewHelper(log.DefaultLogger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Start()
+	defer p.Stop()
+
+	// samples of the cpu, bytes of the heap, goroutines
+	want := map[string]bool{"samples": true, "bytes": true, "goroutines": true}
+	timeout := time.After(5 * time.Second)
+	for len(want) > 0 {
+		select {
+		case name := <-stub.got:
+			delete(want, name)
+		case <-timeout:
+			t.Fatalf("profiles not pushed: %v", want)
+		}
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	for units, r := range stub.pushes {
+		q := r.URL.Query()
+		if got, want := q.Get("name"), "helloworld{id=host-1,region=eu,service.name=helloworld,service.version=v1.0.0}"; got != want {
+			t.Errorf("%s name = %q, want %q", units, got, want)
+		}
+		if got := r.Header.Get("X-Scope-OrgID"); got != "tenant-1" {
+			t.Errorf("%s X-Scope-OrgID = %q", units, got)
+		}
+		if q.Get("from") == "" || q.Get("until") == "" {
+			t.Errorf("%s pushed without from and until", units)
+		}
+		if stub.sizes[units] == 0 {
+			t.Errorf("%s pushed an empty profile", units)
+		}
+	}
+}
+
+func TestNewPusher(t *testing.T) {
+	tests := []struct {
+		name string
+		push *conf.Server_Pprof_Push
+		err  bool
+	}{
+		{name: "disabled", push: &conf.Server_Pprof_Push{Endpoint: "http://pyroscope:4040"}},
+		{name: "no scheme", push: &conf.Server_Pprof_Push{Enable: true, Endpoint: "pyroscope:4040"}, err: true},
+		{name: "unknown profile", push: &conf.Server_Pprof_Push{Enable: true, Endpoint: "http://pyroscope:4040", Profiles: []string{"block"}}, err: true},
+		{name: "valid", push: &conf.Server_Pprof_Push{Enable: true, Endpoint: "http://pyroscope:4040", Profiles: []string{"heap"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := &conf.Bootstrap{Server: &conf.Server{Pprof: &conf.Server_Pprof{Push: tt.push}}}
+			_, err := newPusher(bc, log.NewHelper(log.DefaultLogger))
+			if (err != nil) != tt.err {
+				t.Fatalf("newPusher() error = %v, want error %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCPUProfileBusy(t *testing.T) {
+	cpuProfileMu.Lock()
+	err := writeCPUProfile(context.Background(), &bytes.Buffer{}, time.Millisecond)
+	if !errors.Is(err, errCPUProfileBusy) {
+		t.Errorf("writeCPUProfile() error = %v, want %v", err, errCPUProfileBusy)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	pprofHandler(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/profile?seconds=1", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("/debug/pprof/profile while busy = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	rec = httptest.NewRecorder()
+	pprofHandler(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/debug/pprof/heap while busy = %d, want %d", rec.Code, http.StatusOK)
+	}
+	cpuProfileMu.Unlock()
+
+	rec = httptest.NewRecorder()
+	pprofHandler(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/profile?seconds=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/debug/pprof/profile when free = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
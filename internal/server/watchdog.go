@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/shirou/gopsutil/v3/process"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultWatchdogInterval    = 10 * time.Second
+	defaultWatchdogCooldown    = 5 * time.Minute
+	defaultWatchdogCPUDuration = 10 * time.Second
+	defaultWatchdogMaxFiles    = 10
+
+	profileCPU       = "cpu"
+	profileHeap      = "heap"
+	profileGoroutine = "goroutine"
+	profileMutex     = "mutex"
+)
+
+// Watchdog samples cpu, heap and goroutine usage and captures profiles to
+// local files when one of the configured thresholds is crossed.
+type Watchdog struct {
+	conf *conf.Server_Pprof_Watchdog
+
+	interval    time.Duration
+	cooldown    time.Duration
+	cpuDuration time.Duration
+	maxFiles    int
+	dir         string
+
+	proc   *process.Process
+	tracer trace.Tracer
+
+	lastCapture time.Time
+	// now is the clock of the cooldown and the file names
+	now func() time.Time
+
+	once   sync.Once
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	log *log.Helper
+}
+
+func newWatchdog(c *conf.Server_Pprof_Watchdog, tp trace.TracerProvider, logger *log.Helper) (*Watchdog, error) {
+	if !c.GetEnable() {
+		return nil, nil
+	}
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+	w := &Watchdog{
+		conf:        c,
+		interval:    defaultWatchdogInterval,
+		cooldown:    defaultWatchdogCooldown,
+		cpuDuration: defaultWatchdogCPUDuration,
+		maxFiles:    defaultWatchdogMaxFiles,
+		dir:         filepath.Join(os.TempDir(), "pprof"),
+		proc:        proc,
+		tracer:      tp.Tracer("pprof.watchdog"),
+		now:         time.Now,
+		done:        make(chan struct{}),
+		log:         logger,
+	}
+	if c.GetInterval().AsDuration() > 0 {
+		w.interval = c.GetInterval().AsDuration()
+	}
+	if c.GetCooldown().AsDuration() > 0 {
+		w.cooldown = c.GetCooldown().AsDuration()
+	}
+	if c.GetCpuDuration().AsDuration() > 0 {
+		w.cpuDuration = c.GetCpuDuration().AsDuration()
+	}
+	if c.GetMaxFiles() > 0 {
+		w.maxFiles = int(c.GetMaxFiles())
+	}
+	if c.GetDir() != "" {
+		w.dir = c.GetDir()
+	}
+	if err = os.MkdirAll(w.dir, 0o755); err != nil {
+		return nil, err
+	}
+	if c.GetMutexProfileFraction() > 0 {
+		runtime.SetMutexProfileFraction(int(c.GetMutexProfileFraction()))
+	}
+	return w, nil
+}
+
+func (w *Watchdog) Start() {
+	w.once.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		w.cancel = cancel
+		go w.run(ctx)
+	})
+}
+
+func (w *Watchdog) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+func (w *Watchdog) run(ctx context.Context) {
+	defer close(w.done)
+
+	// the first call only records the cpu times used as baseline
+	_, _ = w.proc.PercentWithContext(ctx, 0)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if reason := w.check(ctx); reason != "" {
+				w.capture(ctx, reason)
+			}
+		}
+	}
+}
+
+// check returns the reason why the profiles should be captured, or an empty
+// string when every sample is below its threshold.
+func (w *Watchdog) check(ctx context.Context) string {
+	var reasons []string
+
+	if threshold := w.conf.GetCpuThreshold(); threshold > 0 {
+		percent, err := w.proc.PercentWithContext(ctx, 0)
+		if err != nil {
+			w.log.WithContext(ctx).Warnf("[Pprof] watchdog sample cpu: %v", err)
+		} else if usage := percent / float64(runtime.GOMAXPROCS(0)); usage >= threshold {
+			reasons = append(reasons, fmt.Sprintf("cpu %.2f%% >= %.2f%%", usage, threshold))
+		}
+	}
+
+	if threshold := w.conf.GetHeapThreshold(); threshold > 0 {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		if ms.HeapInuse >= threshold {
+			reasons = append(reasons, fmt.Sprintf("heap %d >= %d", ms.HeapInuse, threshold))
+		}
+	}
+
+	if threshold := w.conf.GetGoroutineThreshold(); threshold > 0 {
+		if n := int64(runtime.NumGoroutine()); n >= threshold {
+			reasons = append(reasons, fmt.Sprintf("goroutine %d >= %d", n, threshold))
+		}
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+func (w *Watchdog) capture(ctx context.Context, reason string) {
+	now := w.now()
+	if !w.lastCapture.IsZero() && now.Sub(w.lastCapture) < w.cooldown {
+		return
+	}
+	w.lastCapture = now
+
+	ctx, span := w.tracer.Start(ctx, "pprof.watchdog.capture", trace.WithAttributes(
+		attribute.String("pprof.reason", reason),
+	))
+	defer span.End()
+
+	w.log.WithContext(ctx).Warnf("[Pprof] watchdog threshold crossed: %s", reason)
+
+	suffix := now.Format("20060102T150405.000")
+	for _, name := range []string{profileHeap, profileGoroutine, profileMutex, profileCPU} {
+		path := filepath.Join(w.dir, fmt.Sprintf("%s-%s.pb.gz", name, suffix))
+		err := w.writeProfile(ctx, name, path)
+		if errors.Is(err, errCPUProfileBusy) {
+			w.log.WithContext(ctx).Warnf("[Pprof] watchdog skip %s profile: %v", name, err)
+			continue
+		}
+		if err != nil {
+			span.RecordError(err)
+			w.log.WithContext(ctx).Errorf("[Pprof] watchdog capture %s profile: %v", name, err)
+			continue
+		}
+		span.AddEvent("profile", trace.WithAttributes(attribute.String("pprof.path", path)))
+		w.log.WithContext(ctx).Infof("[Pprof] watchdog captured %s profile: %s", name, path)
+		w.rotate(ctx, name)
+	}
+}
+
+func (w *Watchdog) writeProfile(ctx context.Context, name, path string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := f.Close(); err == nil {
+			err = cErr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	if name != profileCPU {
+		return pprof.Lookup(name).WriteTo(f, 0)
+	}
+
+	return writeCPUProfile(ctx, f, w.cpuDuration)
+}
+
+// rotate removes the oldest profiles of the given type, keeping maxFiles.
+func (w *Watchdog) rotate(ctx context.Context, name string) {
+	files, err := filepath.Glob(filepath.Join(w.dir, name+"-*.pb.gz"))
+	if err != nil || len(files) <= w.maxFiles {
+		return
+	}
+	// the timestamp suffix sorts lexically
+	sort.Strings(files)
+	for _, f := range files[:len(files)-w.maxFiles] {
+		if err = os.Remove(f); err != nil {
+			w.log.WithContext(ctx).Warnf("[Pprof] watchdog rotate %s: %v", f, err)
+		}
+	}
+}
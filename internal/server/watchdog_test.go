@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newTestWatchdog(t *testing.T, c *conf.Server_Pprof_Watchdog) *Watchdog {
+	t.Helper()
+	c.Enable = true
+	c.Dir = t.TempDir()
+	w, err := newWatchdog(c, noop.NewTracerProvider(), log.NewHelper(log.DefaultLogger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestWatchdogCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *conf.Server_Pprof_Watchdog
+		reasons []string
+	}{
+		{name: "disabled checks", conf: &conf.Server_Pprof_Watchdog{}},
+		{name: "below thresholds", conf: &conf.Server_Pprof_Watchdog{HeapThreshold: 1 << 50, GoroutineThreshold: 1 << 30}},
+		{name: "heap", conf: &conf.Server_Pprof_Watchdog{HeapThreshold: 1}, reasons: []string{"heap"}},
+		{name: "goroutine", conf: &conf.Server_Pprof_Watchdog{GoroutineThreshold: 1}, reasons: []string{"goroutine"}},
+		{name: "both", conf: &conf.Server_Pprof_Watchdog{HeapThreshold: 1, GoroutineThreshold: 1}, reasons: []string{"heap", "goroutine"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := newTestWatchdog(t, tt.conf).check(context.Background())
+			if len(tt.reasons) == 0 && reason != "" {
+				t.Fatalf("check() = %q, want no reason", reason)
+			}
+			for _, r := range tt.reasons {
+				if !strings.Contains(reason, r) {
+					t.Errorf("check() = %q, want %s", reason, r)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchdogCaptureCooldown(t *testing.T) {
+	w := newTestWatchdog(t, &conf.Server_Pprof_Watchdog{
+		Cooldown:    durationpb.New(time.Minute),
+		CpuDuration: durationpb.New(10 * time.Millisecond),
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	count := func() int {
+		files, _ := filepath.Glob(filepath.Join(w.dir, "*.pb.gz"))
+		return len(files)
+	}
+	steps := []struct {
+		advance time.Duration
+		files   int
+	}{
+		// heap, goroutine, mutex and cpu
+		{files: 4},
+		{advance: 30 * time.Second, files: 4},
+		{advance: 31 * time.Second, files: 8},
+	}
+	for i, s := range steps {
+		now = now.Add(s.advance)
+		w.capture(context.Background(), "test")
+		if got := count(); got != s.files {
+			t.Fatalf("step %d: %d profiles, want %d", i, got, s.files)
+		}
+	}
+}
+
+func TestWatchdogRotate(t *testing.T) {
+	w := newTestWatchdog(t, &conf.Server_Pprof_Watchdog{MaxFiles: 2})
+	names := []string{
+		"heap-20260101T000000.000.pb.gz",
+		"heap-20260101T000100.000.pb.gz",
+		"heap-20260101T000200.000.pb.gz",
+		"goroutine-20260101T000000.000.pb.gz",
+	}
+	for _, n := range names {
+		if err := os.WriteFile(filepath.Join(w.dir, n), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.rotate(context.Background(), profileHeap)
+
+	for _, n := range names {
+		_, err := os.Stat(filepath.Join(w.dir, n))
+		// the oldest heap profile is removed, the other types are kept
+		if removed := n == names[0]; removed != os.IsNotExist(err) {
+			t.Errorf("%s removed = %v, want %v", n, os.IsNotExist(err), removed)
+		}
+	}
+}
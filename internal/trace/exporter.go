@@ -0,0 +1,72 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const compressionGzip = "gzip"
+
+// newSpanExporter creates the span exporter of the config, the cleanup
+// releases what the exporter shutdown does not.
+func newSpanExporter(ctx context.Context, c *conf.Otel_Trace) (tracesdk.SpanExporter, func(), error) {
+	switch c.GetExporter() {
+	case conf.Otel_Trace_OTLP_HTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.GetEndpoint())}
+		if c.GetInsecure() {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(c.GetHeaders()) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(c.GetHeaders()))
+		}
+		if c.GetCompression() == compressionGzip {
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+		}
+		if c.GetUrlPath() != "" {
+			opts = append(opts, otlptracehttp.WithURLPath(c.GetUrlPath()))
+		}
+		if c.GetTimeout().AsDuration() > 0 {
+			opts = append(opts, otlptracehttp.WithTimeout(c.GetTimeout().AsDuration()))
+		}
+		exp, err := otlptrace.New(ctx, otlptracehttp.NewClient(opts...))
+		return exp, func() {}, err
+	case conf.Otel_Trace_STDOUT:
+		exp, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exp, func() {}, err
+	case conf.Otel_Trace_FILE:
+		f, err := os.OpenFile(c.GetFile(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open trace file: %w", err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, err
+		}
+		return exp, func() { _ = f.Close() }, nil
+	default:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(c.GetEndpoint())}
+		if c.GetInsecure() {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(c.GetHeaders()) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(c.GetHeaders()))
+		}
+		if c.GetCompression() == compressionGzip {
+			opts = append(opts, otlptracegrpc.WithCompressor(compressionGzip))
+		}
+		if c.GetTimeout().AsDuration() > 0 {
+			opts = append(opts, otlptracegrpc.WithTimeout(c.GetTimeout().AsDuration()))
+		}
+		exp, err := otlptrace.New(ctx, otlptracegrpc.NewClient(opts...))
+		return exp, func() {}, err
+	}
+}
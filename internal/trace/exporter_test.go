@@ -0,0 +1,63 @@
+package trace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestNewSpanExporter(t *testing.T) {
+	tests := []struct {
+		name string
+		conf *conf.Otel_Trace
+	}{
+		{name: "otlp grpc", conf: &conf.Otel_Trace{Endpoint: "localhost:4317", Insecure: true, Compression: compressionGzip, Headers: map[string]string{"x-token": "t"}, Timeout: durationpb.New(time.Second)}},
+		{name: "otlp http", conf: &conf.Otel_Trace{Exporter: conf.Otel_Trace_OTLP_HTTP, Endpoint: "localhost:4318", Insecure: true, Compression: compressionGzip, UrlPath: "/v1/traces"}},
+		{name: "stdout", conf: &conf.Otel_Trace{Exporter: conf.Otel_Trace_STDOUT}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp, cleanup, err := newSpanExporter(context.Background(), tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cleanup()
+			if err = exp.Shutdown(context.Background()); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestNewSpanExporterFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	exp, cleanup, err := newSpanExporter(context.Background(), &conf.Otel_Trace{Exporter: conf.Otel_Trace_FILE, File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp := tracesdk.NewTracerProvider(tracesdk.WithSyncer(exp))
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	if err = tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	cleanup()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 {
+		t.Error("no span written to the file")
+	}
+
+	if _, _, err = newSpanExporter(context.Background(), &conf.Otel_Trace{Exporter: conf.Otel_Trace_FILE, File: filepath.Join(path, "dir", "traces.json")}); err == nil {
+		t.Error("want an error for an unwritable file")
+	}
+}
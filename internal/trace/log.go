@@ -0,0 +1,87 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// NewLoggerProvider creates the provider exporting the log records over OTLP,
+// nil when disabled. The records are batched, and dropped when the queue is
+// full while the collector is unreachable.
+func NewLoggerProvider(ctx context.Context, bc *conf.Bootstrap, res *resource.Resource) (*sdklog.LoggerProvider, func(), error) {
+	c := bc.GetLog().GetOtlp()
+	if !c.GetEnable() {
+		return nil, func() {}, nil
+	}
+
+	var exp sdklog.Exporter
+	var err error
+	switch c.GetProtocol() {
+	case conf.Log_OTLP_HTTP:
+		opts := []otlploghttp.Option{otlploghttp.WithEndpoint(c.GetEndpoint())}
+		if c.GetInsecure() {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(c.GetHeaders()) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(c.GetHeaders()))
+		}
+		if c.GetCompression() == compressionGzip {
+			opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+		}
+		if c.GetUrlPath() != "" {
+			opts = append(opts, otlploghttp.WithURLPath(c.GetUrlPath()))
+		}
+		if c.GetTimeout().AsDuration() > 0 {
+			opts = append(opts, otlploghttp.WithTimeout(c.GetTimeout().AsDuration()))
+		}
+		exp, err = otlploghttp.New(ctx, opts...)
+	default:
+		opts := []otlploggrpc.Option{otlploggrpc.WithEndpoint(c.GetEndpoint())}
+		if c.GetInsecure() {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if len(c.GetHeaders()) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(c.GetHeaders()))
+		}
+		if c.GetCompression() == compressionGzip {
+			opts = append(opts, otlploggrpc.WithCompressor(compressionGzip))
+		}
+		if c.GetTimeout().AsDuration() > 0 {
+			opts = append(opts, otlploggrpc.WithTimeout(c.GetTimeout().AsDuration()))
+		}
+		exp, err = otlploggrpc.New(ctx, opts...)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var batchOpts []sdklog.BatchProcessorOption
+	if c.GetMaxQueueSize() > 0 {
+		batchOpts = append(batchOpts, sdklog.WithMaxQueueSize(int(c.GetMaxQueueSize())))
+	}
+	if c.GetExportInterval().AsDuration() > 0 {
+		batchOpts = append(batchOpts, sdklog.WithExportInterval(c.GetExportInterval().AsDuration()))
+	}
+	if c.GetExportMaxBatchSize() > 0 {
+		batchOpts = append(batchOpts, sdklog.WithExportMaxBatchSize(int(c.GetExportMaxBatchSize())))
+	}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exp, batchOpts...)),
+	)
+	return provider, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		// the logger may be gone already, report on stderr
+		if err := provider.Shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "[Log] shutdown logger provider: %v\n", err)
+		}
+	}, nil
+}
@@ -0,0 +1,34 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestNewLoggerProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *conf.Log_OTLP
+		enabled bool
+	}{
+		{name: "disabled", conf: &conf.Log_OTLP{}},
+		{name: "grpc", conf: &conf.Log_OTLP{Enable: true, Endpoint: "localhost:4317", Insecure: true, Compression: compressionGzip, MaxQueueSize: 16}, enabled: true},
+		{name: "http", conf: &conf.Log_OTLP{Enable: true, Protocol: conf.Log_OTLP_HTTP, Endpoint: "localhost:4318", Insecure: true, UrlPath: "/v1/logs", ExportMaxBatchSize: 8}, enabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := &conf.Bootstrap{Log: &conf.Log{Otlp: tt.conf}}
+			provider, cleanup, err := NewLoggerProvider(context.Background(), bc, resource.Empty())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cleanup()
+			if (provider != nil) != tt.enabled {
+				t.Errorf("provider = %v, want enabled %v", provider, tt.enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package trace
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos-layout/pkg/metrics"
+	"github.com/shirou/gopsutil/v3/process"
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const defaultMetricInterval = 60 * time.Second
+
+// NewMetrics new the business metrics on the shared meter.
+func NewMetrics(bc *conf.Bootstrap, meter metric.Meter) *metrics.Metrics {
+	return metrics.New(meter, metrics.WithCardinalityLimit(int(bc.GetOtel().GetMetric().GetCardinalityLimit())))
+}
+
+// newOTLPMetricReader creates the periodic reader pushing the metrics over OTLP.
+func newOTLPMetricReader(ctx context.Context, c *conf.Otel_Metric_OTLP) (sdkmetric.Reader, error) {
+	var exp sdkmetric.Exporter
+	var err error
+	switch c.GetProtocol() {
+	case conf.Otel_Metric_HTTP:
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(c.GetEndpoint())}
+		if c.GetInsecure() {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(c.GetHeaders()) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(c.GetHeaders()))
+		}
+		if c.GetCompression() == compressionGzip {
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		}
+		if c.GetUrlPath() != "" {
+			opts = append(opts, otlpmetrichttp.WithURLPath(c.GetUrlPath()))
+		}
+		if c.GetTimeout().AsDuration() > 0 {
+			opts = append(opts, otlpmetrichttp.WithTimeout(c.GetTimeout().AsDuration()))
+		}
+		if c.GetTemporality() == conf.Otel_Metric_DELTA {
+			opts = append(opts, otlpmetrichttp.WithTemporalitySelector(deltaTemporality))
+		}
+		exp, err = otlpmetrichttp.New(ctx, opts...)
+	default:
+		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(c.GetEndpoint())}
+		if c.GetInsecure() {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		if len(c.GetHeaders()) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(c.GetHeaders()))
+		}
+		if c.GetCompression() == compressionGzip {
+			opts = append(opts, otlpmetricgrpc.WithCompressor(compressionGzip))
+		}
+		if c.GetTimeout().AsDuration() > 0 {
+			opts = append(opts, otlpmetricgrpc.WithTimeout(c.GetTimeout().AsDuration()))
+		}
+		if c.GetTemporality() == conf.Otel_Metric_DELTA {
+			opts = append(opts, otlpmetricgrpc.WithTemporalitySelector(deltaTemporality))
+		}
+		exp, err = otlpmetricgrpc.New(ctx, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	interval := defaultMetricInterval
+	if c.GetInterval().AsDuration() > 0 {
+		interval = c.GetInterval().AsDuration()
+	}
+	return sdkmetric.NewPeriodicReader(exp, sdkmetric.WithInterval(interval)), nil
+}
+
+// deltaTemporality reports the monotonic instruments as delta, the up down
+// counters stay cumulative.
+func deltaTemporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindCounter,
+		sdkmetric.InstrumentKindHistogram,
+		sdkmetric.InstrumentKindObservableCounter:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+// startRuntimeMetrics registers the Go runtime and the process metrics.
+func startRuntimeMetrics(provider metric.MeterProvider) error {
+	if err := runtime.Start(runtime.WithMeterProvider(provider)); err != nil {
+		return err
+	}
+
+	p, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return err
+	}
+	meter := provider.Meter("process")
+	cpuTime, err := meter.Float64ObservableCounter("process.cpu.time",
+		metric.WithUnit("s"), metric.WithDescription("Total CPU seconds of the process."))
+	if err != nil {
+		return err
+	}
+	memory, err := meter.Int64ObservableGauge("process.memory.usage",
+		metric.WithUnit("By"), metric.WithDescription("Resident memory of the process."))
+	if err != nil {
+		return err
+	}
+	virtual, err := meter.Int64ObservableGauge("process.memory.virtual",
+		metric.WithUnit("By"), metric.WithDescription("Virtual memory of the process."))
+	if err != nil {
+		return err
+	}
+	threads, err := meter.Int64ObservableGauge("process.thread.count",
+		metric.WithDescription("OS threads of the process."))
+	if err != nil {
+		return err
+	}
+	fds, err := meter.Int64ObservableGauge("process.open_file_descriptor.count",
+		metric.WithDescription("Open file descriptors of the process."))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		if times, err := p.TimesWithContext(ctx); err == nil {
+			o.ObserveFloat64(cpuTime, times.User+times.System)
+		}
+		if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+			o.ObserveInt64(memory, int64(mem.RSS))
+			o.ObserveInt64(virtual, int64(mem.VMS))
+		}
+		if n, err := p.NumThreadsWithContext(ctx); err == nil {
+			o.ObserveInt64(threads, int64(n))
+		}
+		// not supported on every platform
+		if n, err := p.NumFDsWithContext(ctx); err == nil {
+			o.ObserveInt64(fds, int64(n))
+		}
+		return nil
+	}, cpuTime, memory, virtual, threads, fds)
+	return err
+}
@@ -0,0 +1,76 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestNewOTLPMetricReader(t *testing.T) {
+	tests := []struct {
+		name string
+		conf *conf.Otel_Metric_OTLP
+	}{
+		{name: "grpc", conf: &conf.Otel_Metric_OTLP{Endpoint: "localhost:4317", Insecure: true, Compression: compressionGzip, Temporality: conf.Otel_Metric_DELTA}},
+		{name: "http", conf: &conf.Otel_Metric_OTLP{Protocol: conf.Otel_Metric_HTTP, Endpoint: "localhost:4318", Insecure: true, UrlPath: "/v1/metrics", Interval: durationpb.New(time.Second)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := newOTLPMetricReader(context.Background(), tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = reader.Shutdown(context.Background()); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestDeltaTemporality(t *testing.T) {
+	tests := []struct {
+		kind metric.InstrumentKind
+		want metricdata.Temporality
+	}{
+		{kind: metric.InstrumentKindCounter, want: metricdata.DeltaTemporality},
+		{kind: metric.InstrumentKindHistogram, want: metricdata.DeltaTemporality},
+		{kind: metric.InstrumentKindObservableCounter, want: metricdata.DeltaTemporality},
+		{kind: metric.InstrumentKindUpDownCounter, want: metricdata.CumulativeTemporality},
+		{kind: metric.InstrumentKindObservableGauge, want: metricdata.CumulativeTemporality},
+	}
+	for _, tt := range tests {
+		if got := deltaTemporality(tt.kind); got != tt.want {
+			t.Errorf("deltaTemporality(%s) = %s, want %s", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestNewMeterProvider(t *testing.T) {
+	bc := &conf.Bootstrap{
+		Metadata: &conf.MetaData{Name: "helloworld"},
+		Otel:     &conf.Otel{Metric: &conf.Otel_Metric{DisablePrometheus: true, Runtime: true}},
+	}
+	provider, cleanup, err := NewMeterProvider(context.Background(), bc, resource.Empty(), log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	meter, err := NewMeter(bc, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = meter.Int64Counter("test"); err != nil {
+		t.Error(err)
+	}
+	if m := NewMetrics(bc, meter); m == nil {
+		t.Error("NewMetrics() = nil")
+	}
+}
@@ -2,83 +2,116 @@ package trace
 
 import (
 	"context"
+	"time"
 
+	"github.com/go-kratos/kratos-layout/internal/bootstrap"
 	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/middleware/metrics"
 	"github.com/go-kratos/kratos/v2/middleware/tracing"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+const shutdownTimeout = 5 * time.Second
+
 func NewMeter(bc *conf.Bootstrap, provider metric.MeterProvider) (metric.Meter, error) {
 	return provider.Meter(bc.GetMetadata().GetName()), nil
 }
 
-func NewMeterProvider(bc *conf.Bootstrap) (metric.MeterProvider, error) {
-	metricConf := bc.GetOtel().Metric
-	exporter, err := prometheus.New()
-	if err != nil {
-		return nil, err
+func NewMeterProvider(ctx context.Context, bc *conf.Bootstrap, res *resource.Resource, logger log.Logger) (metric.MeterProvider, func(), error) {
+	metricConf := bc.GetOtel().GetMetric()
+	var opts []sdkmetric.Option
+	if !metricConf.GetDisablePrometheus() {
+		exporter, err := prometheus.New()
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, sdkmetric.WithReader(exporter))
 	}
-
-	if metricConf.EnableExemplar {
-		err = metrics.EnableOTELExemplar()
+	if metricConf.GetOtlp().GetEnable() {
+		reader, err := newOTLPMetricReader(ctx, metricConf.GetOtlp())
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		opts = append(opts, sdkmetric.WithReader(reader))
 	}
 
-	provider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithResource(
-			resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String(bc.GetMetadata().GetName()),
-				attribute.String("env", bc.GetEnv().String()),
-			),
-		),
-		sdkmetric.WithReader(exporter),
+	if metricConf.GetEnableExemplar() {
+		if err := metrics.EnableOTELExemplar(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	opts = append(opts,
+		sdkmetric.WithResource(res),
 		sdkmetric.WithView(
 			metrics.DefaultSecondsHistogramView(metrics.DefaultServerSecondsHistogramName),
 		),
 	)
+	provider := sdkmetric.NewMeterProvider(opts...)
 	otel.SetMeterProvider(provider)
-	return provider, nil
+
+	if metricConf.GetRuntime() {
+		if err := startRuntimeMetrics(provider); err != nil {
+			return nil, nil, err
+		}
+	}
+	return provider, func() {
+		// push the last metrics before exit
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			log.NewHelper(logger).Errorf("[Metric] shutdown meter provider: %v", err)
+		}
+	}, nil
 }
 
-func NewTracerProvider(ctx context.Context, bc *conf.Bootstrap, textMapPropagator propagation.TextMapPropagator) (trace.TracerProvider, error) {
-	traceConf := bc.Otel.Trace
-	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(traceConf.Endpoint)}
-	if traceConf.Insecure {
-		opts = append(opts, otlptracegrpc.WithInsecure())
+func NewTracerProvider(ctx context.Context, bc *conf.Bootstrap, res *resource.Resource, textMapPropagator propagation.TextMapPropagator, reloader *bootstrap.Reloader, logger log.Logger) (trace.TracerProvider, func(), error) {
+	traceConf := bc.GetOtel().GetTrace()
+	otel.SetTextMapPropagator(textMapPropagator)
+	if traceConf.GetExporter() == conf.Otel_Trace_NONE {
+		tp := noop.NewTracerProvider()
+		otel.SetTracerProvider(tp)
+		return tp, func() {}, nil
 	}
-	client := otlptracegrpc.NewClient(opts...)
-	exp, err := otlptrace.New(ctx, client)
+
+	exp, expClean, err := newSpanExporter(ctx, traceConf)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	tp := tracesdk.NewTracerProvider(
-		tracesdk.WithBatcher(exp),
-		tracesdk.WithResource(
-			resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String(bc.GetMetadata().GetName()),
-				attribute.String("env", bc.GetEnv().String()),
-			),
-		),
-	)
+	// the unsampled spans only reach the processor with always_sample_errors,
+	// so it's installed anyway for the sampler reloads
+	sampler := newReloadableSampler(newSampler(samplerConfig(bc)))
+	reloader.OnReload("otel.trace.sampler", func(bc *conf.Bootstrap) {
+		sampler.Store(newSampler(samplerConfig(bc)))
+	})
+	reloader.OnReload("otel.trace.environment_samplers", func(bc *conf.Bootstrap) {
+		sampler.Store(newSampler(samplerConfig(bc)))
+	})
+	tpOpts := []tracesdk.TracerProviderOption{
+		tracesdk.WithSpanProcessor(errorSpanProcessor{SpanProcessor: tracesdk.NewBatchSpanProcessor(exp)}),
+		tracesdk.WithResource(res),
+		tracesdk.WithSampler(sampler),
+	}
+	tp := tracesdk.NewTracerProvider(tpOpts...)
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(textMapPropagator)
-	return tp, nil
+	return tp, func() {
+		// flush the pending spans before exit
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			log.NewHelper(logger).Errorf("[Trace] shutdown tracer provider: %v", err)
+		}
+		expClean()
+	}, nil
 }
 
 func NewTracer(bc *conf.Bootstrap, tp trace.TracerProvider) (trace.Tracer, error) {
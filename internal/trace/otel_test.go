@@ -0,0 +1,53 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/bootstrap"
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter conf.Otel_Trace_Exporter
+		noop     bool
+	}{
+		{name: "none", exporter: conf.Otel_Trace_NONE, noop: true},
+		{name: "stdout", exporter: conf.Otel_Trace_STDOUT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := &conf.Bootstrap{
+				Metadata: &conf.MetaData{Name: "helloworld"},
+				Otel:     &conf.Otel{Trace: &conf.Otel_Trace{Exporter: tt.exporter}},
+			}
+			tp, cleanup, err := NewTracerProvider(context.Background(), bc, resource.Empty(), NewTextMapPropagator(), bootstrap.NewReloader(bc, log.DefaultLogger), log.DefaultLogger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cleanup()
+
+			switch tp.(type) {
+			case noop.TracerProvider:
+				if !tt.noop {
+					t.Errorf("provider = %T, want the sdk one", tp)
+				}
+			case *tracesdk.TracerProvider:
+				if tt.noop {
+					t.Errorf("provider = %T, want the noop one", tp)
+				}
+			default:
+				t.Errorf("provider = %T", tp)
+			}
+			if _, err = NewTracer(bc, tp); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
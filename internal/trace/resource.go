@@ -0,0 +1,54 @@
+package trace
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// NewResource builds the resource shared by the tracer, meter and logger
+// providers. The later sources win: the detected host, container and process
+// attributes, the service metadata, the config attributes, then
+// OTEL_RESOURCE_ATTRIBUTES.
+func NewResource(ctx context.Context, bc *conf.Bootstrap) (*resource.Resource, error) {
+	md := bc.GetMetadata()
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(md.GetName()),
+		semconv.ServiceInstanceID(md.GetId()),
+		semconv.DeploymentEnvironmentName(bc.GetEnv().String()),
+		// the legacy keys, still used by the dashboards and some backends
+		attribute.String("deployment.environment", bc.GetEnv().String()),
+		attribute.String("env", bc.GetEnv().String()),
+	}
+	if md.GetVersion() != "" {
+		attrs = append(attrs, semconv.ServiceVersion(md.GetVersion()))
+	}
+	extra := make([]attribute.KeyValue, 0, len(bc.GetOtel().GetResourceAttributes()))
+	for k, v := range bc.GetOtel().GetResourceAttributes() {
+		extra = append(extra, attribute.String(k, v))
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithOS(),
+		resource.WithContainer(),
+		resource.WithProcessPID(),
+		resource.WithProcessExecutableName(),
+		resource.WithProcessRuntimeName(),
+		resource.WithProcessRuntimeVersion(),
+		resource.WithAttributes(attrs...),
+		resource.WithAttributes(extra...),
+		resource.WithFromEnv(),
+	)
+	// a partial resource is still usable when some detectors fail
+	if errors.Is(err, resource.ErrPartialResource) {
+		return res, nil
+	}
+	return res, err
+}
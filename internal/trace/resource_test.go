@@ -0,0 +1,46 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestNewResource(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "team=env,region=eu")
+	bc := &conf.Bootstrap{
+		Env:      conf.Environment_PROD,
+		Metadata: &conf.MetaData{Name: "helloworld", Id: "host-1", Version: "v1.0.0"},
+		Otel:     &conf.Otel{ResourceAttributes: map[string]string{"team": "config", "cluster": "a"}},
+	}
+	res, err := NewResource(context.Background(), bc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[attribute.Key]string{
+		"service.name":                "helloworld",
+		"service.instance.id":         "host-1",
+		"service.version":             "v1.0.0",
+		"deployment.environment.name": "PROD",
+		"deployment.environment":      "PROD",
+		"env":                         "PROD",
+		"cluster":                     "a",
+		// OTEL_RESOURCE_ATTRIBUTES wins over the config
+		"team":   "env",
+		"region": "eu",
+	}
+	set := res.Set()
+	for k, v := range want {
+		if got, ok := set.Value(k); !ok || got.AsString() != v {
+			t.Errorf("%s = %q, want %q", k, got.AsString(), v)
+		}
+	}
+	for _, k := range []attribute.Key{"process.pid", "host.name", "telemetry.sdk.language"} {
+		if !set.HasValue(k) {
+			t.Errorf("%s is not detected", k)
+		}
+	}
+}
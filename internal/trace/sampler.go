@@ -0,0 +1,204 @@
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// samplerConfig returns the sampler of the environment, falling back to the default one.
+func samplerConfig(bc *conf.Bootstrap) *conf.Otel_Sampler {
+	tc := bc.GetOtel().GetTrace()
+	if s, ok := tc.GetEnvironmentSamplers()[bc.GetEnv().String()]; ok {
+		return s
+	}
+	return tc.GetSampler()
+}
+
+// newSampler builds the sampler of the config, nil config keeps the SDK
+// default ParentBased(AlwaysOn).
+func newSampler(c *conf.Otel_Sampler) tracesdk.Sampler {
+	var root tracesdk.Sampler
+	switch c.GetType() {
+	case conf.Otel_Sampler_ALWAYS_OFF:
+		root = tracesdk.NeverSample()
+	case conf.Otel_Sampler_RATIO:
+		root = tracesdk.TraceIDRatioBased(c.GetRatio())
+	case conf.Otel_Sampler_RATE_LIMITED:
+		root = newRateLimitedSampler(c.GetRateLimit())
+	default:
+		root = tracesdk.AlwaysSample()
+	}
+
+	var s tracesdk.Sampler
+	if c.GetIgnoreParent() {
+		s = root
+	} else {
+		s = tracesdk.ParentBased(root)
+	}
+	if len(c.GetOperations()) > 0 {
+		s = newOperationSampler(c.GetOperations(), s)
+	}
+	if c.GetAlwaysSampleErrors() {
+		s = recordingSampler{next: s}
+	}
+	return s
+}
+
+// reloadableSampler delegates to the sampler of the current config.
+type reloadableSampler struct {
+	current atomic.Pointer[tracesdk.Sampler]
+}
+
+func newReloadableSampler(s tracesdk.Sampler) *reloadableSampler {
+	r := &reloadableSampler{}
+	r.Store(s)
+	return r
+}
+
+func (s *reloadableSampler) Store(sampler tracesdk.Sampler) {
+	s.current.Store(&sampler)
+}
+
+func (s *reloadableSampler) ShouldSample(p tracesdk.SamplingParameters) tracesdk.SamplingResult {
+	return (*s.current.Load()).ShouldSample(p)
+}
+
+func (s *reloadableSampler) Description() string {
+	return (*s.current.Load()).Description()
+}
+
+// operationSampler overrides the sampling ratio of the span names, whatever
+// the parent decision is.
+type operationSampler struct {
+	exact    map[string]tracesdk.Sampler
+	prefixes []string
+	byPrefix map[string]tracesdk.Sampler
+	next     tracesdk.Sampler
+}
+
+func newOperationSampler(operations map[string]float64, next tracesdk.Sampler) *operationSampler {
+	s := &operationSampler{
+		exact:    make(map[string]tracesdk.Sampler),
+		byPrefix: make(map[string]tracesdk.Sampler),
+		next:     next,
+	}
+	for op, ratio := range operations {
+		if prefix, ok := strings.CutSuffix(op, "*"); ok {
+			s.prefixes = append(s.prefixes, prefix)
+			s.byPrefix[prefix] = tracesdk.TraceIDRatioBased(ratio)
+			continue
+		}
+		s.exact[op] = tracesdk.TraceIDRatioBased(ratio)
+	}
+	// longest prefix first
+	sort.Slice(s.prefixes, func(i, j int) bool {
+		return len(s.prefixes[i]) > len(s.prefixes[j])
+	})
+	return s
+}
+
+func (s *operationSampler) ShouldSample(p tracesdk.SamplingParameters) tracesdk.SamplingResult {
+	if sampler, ok := s.exact[p.Name]; ok {
+		return sampler.ShouldSample(p)
+	}
+	for _, prefix := range s.prefixes {
+		if strings.HasPrefix(p.Name, prefix) {
+			return s.byPrefix[prefix].ShouldSample(p)
+		}
+	}
+	return s.next.ShouldSample(p)
+}
+
+func (s *operationSampler) Description() string {
+	return fmt.Sprintf("OperationSampler{%s}", s.next.Description())
+}
+
+// rateLimitedSampler samples at most limit traces per second with a token bucket.
+type rateLimitedSampler struct {
+	limit float64
+	// burst holds one second of tokens, at least one so a limit below 1
+	// still samples, a limit of 0 samples nothing
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimitedSampler(limit float64) *rateLimitedSampler {
+	var burst float64
+	if limit > 0 {
+		burst = max(limit, 1)
+	}
+	return &rateLimitedSampler{limit: limit, burst: burst, tokens: burst, last: time.Now()}
+}
+
+func (s *rateLimitedSampler) ShouldSample(p tracesdk.SamplingParameters) tracesdk.SamplingResult {
+	psc := trace.SpanContextFromContext(p.ParentContext)
+	s.mu.Lock()
+	now := time.Now()
+	s.tokens = min(s.burst, s.tokens+now.Sub(s.last).Seconds()*s.limit)
+	s.last = now
+	decision := tracesdk.Drop
+	if s.tokens >= 1 {
+		s.tokens--
+		decision = tracesdk.RecordAndSample
+	}
+	s.mu.Unlock()
+	return tracesdk.SamplingResult{Decision: decision, Tracestate: psc.TraceState()}
+}
+
+func (s *rateLimitedSampler) Description() string {
+	return fmt.Sprintf("RateLimitedSampler{%g}", s.limit)
+}
+
+// recordingSampler records the dropped spans, so errorSpanProcessor can
+// export them when they end with an error.
+type recordingSampler struct {
+	next tracesdk.Sampler
+}
+
+func (s recordingSampler) ShouldSample(p tracesdk.SamplingParameters) tracesdk.SamplingResult {
+	r := s.next.ShouldSample(p)
+	if r.Decision == tracesdk.Drop {
+		r.Decision = tracesdk.RecordOnly
+	}
+	return r
+}
+
+func (s recordingSampler) Description() string {
+	return fmt.Sprintf("RecordingSampler{%s}", s.next.Description())
+}
+
+// errorSpanProcessor exports the recorded but unsampled spans that end with
+// an error, a tail-ish sampling of the errors. Only the error spans are
+// exported, not the rest of their traces.
+type errorSpanProcessor struct {
+	tracesdk.SpanProcessor
+}
+
+func (p errorSpanProcessor) OnEnd(s tracesdk.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() && s.Status().Code == codes.Error {
+		s = sampledSpan{ReadOnlySpan: s}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// sampledSpan flags the span sampled for the exporting processors.
+type sampledSpan struct {
+	tracesdk.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
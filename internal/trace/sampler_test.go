@@ -0,0 +1,177 @@
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const healthCheck = "/grpc.health.v1.Health/Check"
+
+func parentContext(sampled bool) context.Context {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		Remote:  true,
+	})
+	if sampled {
+		sc = sc.WithTraceFlags(trace.FlagsSampled)
+	}
+	return trace.ContextWithRemoteSpanContext(context.Background(), sc)
+}
+
+func TestSampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		conf     *conf.Otel_Sampler
+		parent   context.Context
+		span     string
+		decision tracesdk.SamplingDecision
+	}{
+		{name: "default", decision: tracesdk.RecordAndSample},
+		{name: "always off", conf: &conf.Otel_Sampler{Type: conf.Otel_Sampler_ALWAYS_OFF}, decision: tracesdk.Drop},
+		{name: "ratio zero", conf: &conf.Otel_Sampler{Type: conf.Otel_Sampler_RATIO, Ratio: 0}, decision: tracesdk.Drop},
+		{name: "ratio one", conf: &conf.Otel_Sampler{Type: conf.Otel_Sampler_RATIO, Ratio: 1}, decision: tracesdk.RecordAndSample},
+		{name: "rate limited", conf: &conf.Otel_Sampler{Type: conf.Otel_Sampler_RATE_LIMITED, RateLimit: 1}, decision: tracesdk.RecordAndSample},
+		{name: "sampled parent", conf: &conf.Otel_Sampler{Type: conf.Otel_Sampler_ALWAYS_OFF}, parent: parentContext(true), decision: tracesdk.RecordAndSample},
+		{name: "unsampled parent", parent: parentContext(false), decision: tracesdk.Drop},
+		{name: "ignored parent", conf: &conf.Otel_Sampler{Type: conf.Otel_Sampler_ALWAYS_OFF, IgnoreParent: true}, parent: parentContext(true), decision: tracesdk.Drop},
+		{
+			name:     "operation prefix over the parent",
+			conf:     &conf.Otel_Sampler{Operations: map[string]float64{"/grpc.health.v1.Health/*": 0}},
+			parent:   parentContext(true),
+			span:     healthCheck,
+			decision: tracesdk.Drop,
+		},
+		{
+			name:     "exact operation over the prefix",
+			conf:     &conf.Otel_Sampler{Type: conf.Otel_Sampler_ALWAYS_OFF, Operations: map[string]float64{"/grpc.health.v1.Health/*": 0, healthCheck: 1}},
+			span:     healthCheck,
+			decision: tracesdk.RecordAndSample,
+		},
+		{
+			name:     "other operation",
+			conf:     &conf.Otel_Sampler{Operations: map[string]float64{"/grpc.health.v1.Health/*": 0}},
+			decision: tracesdk.RecordAndSample,
+		},
+		{name: "errors recorded", conf: &conf.Otel_Sampler{Type: conf.Otel_Sampler_ALWAYS_OFF, AlwaysSampleErrors: true}, decision: tracesdk.RecordOnly},
+		{name: "errors sampled", conf: &conf.Otel_Sampler{AlwaysSampleErrors: true}, decision: tracesdk.RecordAndSample},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := tt.parent
+			if parent == nil {
+				parent = context.Background()
+			}
+			span := tt.span
+			if span == "" {
+				span = "/helloworld.v1.Greeter/SayHello"
+			}
+			r := newSampler(tt.conf).ShouldSample(tracesdk.SamplingParameters{
+				ParentContext: parent,
+				TraceID:       trace.SpanContextFromContext(parentContext(false)).TraceID(),
+				Name:          span,
+			})
+			if r.Decision != tt.decision {
+				t.Errorf("decision = %v, want %v", r.Decision, tt.decision)
+			}
+		})
+	}
+}
+
+func TestSamplerConfig(t *testing.T) {
+	def := &conf.Otel_Sampler{Type: conf.Otel_Sampler_ALWAYS_ON}
+	prod := &conf.Otel_Sampler{Type: conf.Otel_Sampler_RATIO, Ratio: 0.05}
+	bc := &conf.Bootstrap{Otel: &conf.Otel{Trace: &conf.Otel_Trace{
+		Sampler:             def,
+		EnvironmentSamplers: map[string]*conf.Otel_Sampler{conf.Environment_PROD.String(): prod},
+	}}}
+
+	if got := samplerConfig(bc); got != def {
+		t.Errorf("DEV sampler = %v, want the default one", got)
+	}
+	bc.Env = conf.Environment_PROD
+	if got := samplerConfig(bc); got != prod {
+		t.Errorf("PROD sampler = %v, want the environment one", got)
+	}
+}
+
+func TestReloadableSampler(t *testing.T) {
+	s := newReloadableSampler(tracesdk.AlwaysSample())
+	p := tracesdk.SamplingParameters{ParentContext: context.Background(), Name: "op"}
+	if r := s.ShouldSample(p); r.Decision != tracesdk.RecordAndSample {
+		t.Fatalf("decision = %v, want sampled", r.Decision)
+	}
+	s.Store(tracesdk.NeverSample())
+	if r := s.ShouldSample(p); r.Decision != tracesdk.Drop {
+		t.Errorf("decision = %v, want dropped after the reload", r.Decision)
+	}
+}
+
+func TestErrorSpanProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := tracesdk.NewTracerProvider(
+		tracesdk.WithSampler(newSampler(&conf.Otel_Sampler{Type: conf.Otel_Sampler_ALWAYS_OFF, AlwaysSampleErrors: true})),
+		tracesdk.WithSpanProcessor(errorSpanProcessor{SpanProcessor: recorder}),
+	)
+	tracer := tp.Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	ok.End()
+	_, failed := tracer.Start(context.Background(), "failed")
+	failed.SetStatus(codes.Error, "boom")
+	failed.End()
+
+	sampled := make(map[string]bool)
+	for _, s := range recorder.Ended() {
+		sampled[s.Name()] = s.SpanContext().IsSampled()
+	}
+	if len(sampled) != 2 || sampled["ok"] || !sampled["failed"] {
+		t.Errorf("sampled = %v, want only the failed span", sampled)
+	}
+}
+
+func TestRateLimitedSampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    float64
+		calls    int
+		interval time.Duration
+		sampled  int
+	}{
+		{name: "burst", limit: 10, calls: 20, sampled: 10},
+		{name: "refill", limit: 10, calls: 20, interval: 100 * time.Millisecond, sampled: 20},
+		{name: "half refill", limit: 10, calls: 20, interval: 50 * time.Millisecond, sampled: 19},
+		{name: "below one burst", limit: 0.5, calls: 3, sampled: 1},
+		{name: "below one refill", limit: 0.5, calls: 4, interval: time.Second, sampled: 2},
+		{name: "below one slow", limit: 0.1, calls: 3, interval: 10 * time.Second, sampled: 3},
+		{name: "zero", limit: 0, calls: 3, interval: time.Hour, sampled: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newRateLimitedSampler(tt.limit)
+			var sampled int
+			for i := 0; i < tt.calls; i++ {
+				if i > 0 {
+					// move the clock of the bucket instead of sleeping
+					s.mu.Lock()
+					s.last = s.last.Add(-tt.interval)
+					s.mu.Unlock()
+				}
+				r := s.ShouldSample(tracesdk.SamplingParameters{ParentContext: context.Background(), Name: "op"})
+				if r.Decision == tracesdk.RecordAndSample {
+					sampled++
+				}
+			}
+			if sampled != tt.sampled {
+				t.Errorf("sampled %d of %d, want %d", sampled, tt.calls, tt.sampled)
+			}
+		})
+	}
+}
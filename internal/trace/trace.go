@@ -2,4 +2,5 @@ package trace
 
 import "github.com/google/wire"
 
-var ProviderSet = wire.NewSet(NewMeter, NewMeterProvider, NewTracerProvider, NewTracer, NewTextMapPropagator)
+// ProviderSet is trace providers.
+var ProviderSet = wire.NewSet(NewResource, NewMeter, NewMetrics, NewMeterProvider, NewTracerProvider, NewTracer, NewTextMapPropagator)
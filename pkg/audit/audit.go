@@ -0,0 +1,31 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type (
+	// Record is an entry of the audit trail.
+	Record struct {
+		Time      time.Time       `json:"time"`
+		Operation string          `json:"operation"`
+		Transport string          `json:"transport"`
+		Subject   string          `json:"subject,omitempty"`
+		Tenant    string          `json:"tenant,omitempty"`
+		RequestID string          `json:"request_id,omitempty"`
+		TraceID   string          `json:"trace_id,omitempty"`
+		Request   json.RawMessage `json:"request,omitempty"`
+		Response  json.RawMessage `json:"response,omitempty"`
+		// Code is the http status, or the grpc code of the grpc calls.
+		Code      int32   `json:"code"`
+		Reason    string  `json:"reason,omitempty"`
+		LatencyMs float64 `json:"latency_ms"`
+	}
+
+	// Sink writes the audit records.
+	Sink interface {
+		Write(ctx context.Context, r *Record) error
+	}
+)
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims is the typed claims of the access token.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	// Scope is the space separated scopes of OAuth2.
+	Scope string `json:"scope,omitempty"`
+	// Scp is the scopes as a list, used by some providers instead of scope.
+	Scp []string `json:"scp,omitempty"`
+	// Roles is the roles granted to the subject.
+	Roles []string `json:"roles,omitempty"`
+	// Permissions is the permissions granted to the subject.
+	Permissions []string `json:"permissions,omitempty"`
+	// TenantID is the tenant the subject belongs to.
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// Scopes returns the scopes granted to the token.
+func (c *Claims) Scopes() []string {
+	scopes := strings.Fields(c.Scope)
+	for _, s := range c.Scp {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// HasScope reports whether the token is granted the scope.
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes(), scope)
+}
+
+// HasRole reports whether the subject is granted the role.
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+// HasPermission reports whether the subject is granted the permission.
+func (c *Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
+
+type claimsKey struct{}
+
+// NewContext returns a new context that carries the claims.
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims stored in ctx.
+func FromContext(ctx context.Context) (*Claims, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
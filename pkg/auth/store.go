@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// APIKey is an api key found by its hash.
+	APIKey struct {
+		ID     string
+		Scopes []string
+	}
+
+	// APIKeyStore finds the api keys not declared in config, eg: in a database table.
+	APIKeyStore interface {
+		// FindAPIKey returns nil when no valid key has the hash.
+		FindAPIKey(ctx context.Context, hash string) (*APIKey, error)
+	}
+
+	// NonceStore remembers the nonces of the signed requests.
+	NonceStore interface {
+		// Remember returns false when the nonce of the key has been seen within ttl.
+		Remember(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error)
+	}
+)
@@ -0,0 +1,28 @@
+// Package feature is the feature flags of the config, reloaded on change:
+//
+//	if feature.Enabled("new_checkout") {
+//		...
+//	}
+package feature
+
+import "sync/atomic"
+
+var flags atomic.Pointer[map[string]bool]
+
+// Set replaces the feature flags.
+func Set(features map[string]bool) {
+	m := make(map[string]bool, len(features))
+	for k, v := range features {
+		m[k] = v
+	}
+	flags.Store(&m)
+}
+
+// Enabled reports whether the feature is on, unknown features are off.
+func Enabled(name string) bool {
+	m := flags.Load()
+	if m == nil {
+		return false
+	}
+	return (*m)[name]
+}
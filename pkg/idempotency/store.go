@@ -0,0 +1,19 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Store locks the idempotency keys and stores the results.
+type Store interface {
+	// Acquire locks the key for ttl and returns the token of the lock. When the
+	// key is already taken the token is empty, with the stored result or nil
+	// while the first request is in progress.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (token string, result []byte, err error)
+	// Save stores the result for ttl, only while the key is still locked with token.
+	Save(ctx context.Context, key, token string, result []byte, ttl time.Duration) error
+	// Release unlocks the key locked with token without a result, so the
+	// request can be retried.
+	Release(ctx context.Context, key, token string) error
+}
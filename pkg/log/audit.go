@@ -0,0 +1,41 @@
+package log
+
+import (
+	"time"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// NewAuditLogger new a zap logger writing json lines into a dedicated rotated
+// file, apart from the application logs.
+func NewAuditLogger(c *conf.Log) (*zap.Logger, func()) {
+	fileRotate := &lumberjack.Logger{
+		Filename:   c.GetFilepath(),
+		MaxSize:    int(c.GetMaxSize()),
+		MaxAge:     int(c.GetMaxAge()),
+		MaxBackups: int(c.GetMaxBackups()),
+		LocalTime:  true,
+	}
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+			TimeKey:    "ts",
+			MessageKey: "message",
+			LineEnding: zapcore.DefaultLineEnding,
+			EncodeTime: func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+				enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
+			},
+			EncodeDuration: zapcore.StringDurationEncoder,
+		}),
+		zapcore.AddSync(fileRotate),
+		// the audit records are never filtered by level
+		zap.NewAtomicLevelAt(zapcore.DebugLevel),
+	)
+	logger := zap.New(core)
+	return logger, func() {
+		_ = logger.Sync()
+		_ = fileRotate.Close()
+	}
+}
@@ -1,25 +1,70 @@
 package log
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/go-kratos/kratos-layout/internal/conf"
 	"github.com/go-kratos/kratos/v2/log"
+	"go.opentelemetry.io/contrib/bridges/otelzap"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+const (
+	// the keys of the trace and span ids in the kratos logger
+	traceIDKey = "trace.id"
+	spanIDKey  = "span.id"
+)
+
 type zapWrapper func(level log.Level, keyvals ...interface{}) error
 
 func (f zapWrapper) Log(level log.Level, keyvals ...interface{}) error {
 	return f(level, keyvals...)
 }
 
-func NewZapLogger(env conf.Environment, logPath string, maxSize, maxAge, level, maxBackups int32) log.Logger {
-	logLevel := zapcore.Level(level)
+type options struct {
+	loggerProvider otellog.LoggerProvider
+	level          *zap.AtomicLevel
+}
+
+// Option is a zap logger option.
+type Option func(*options)
+
+// WithLoggerProvider emits every record to the OTel logger provider too.
+func WithLoggerProvider(provider otellog.LoggerProvider) Option {
+	return func(o *options) {
+		o.loggerProvider = provider
+	}
+}
+
+// WithAtomicLevel sets the level of the logger on level, so it can be
+// changed at runtime.
+func WithAtomicLevel(level zap.AtomicLevel) Option {
+	return func(o *options) {
+		o.level = &level
+	}
+}
+
+// startupLevel returns the level of the config in the env, debug out of PROD.
+func startupLevel(env conf.Environment, level int32) zapcore.Level {
+	if env != conf.Environment_PROD {
+		return zapcore.DebugLevel
+	}
+	return zapcore.Level(level)
+}
+
+func NewZapLogger(env conf.Environment, logPath string, maxSize, maxAge, level, maxBackups int32, opts ...Option) log.Logger {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	logLevel := startupLevel(env, level)
 
 	fileRotate := &lumberjack.Logger{
 		Filename:   logPath,
@@ -41,11 +86,16 @@ func NewZapLogger(env conf.Environment, logPath string, maxSize, maxAge, level,
 	)
 
 	if env != conf.Environment_PROD {
-		logLevel = zapcore.DebugLevel
 		WriterSyncer = append(WriterSyncer, zapcore.AddSync(os.Stdout))
 		zapOpts = append(zapOpts, zap.Development())
 	}
 
+	atomicLevel := zap.NewAtomicLevelAt(logLevel)
+	if o.level != nil {
+		atomicLevel = *o.level
+		atomicLevel.SetLevel(logLevel)
+	}
+
 	coreInfo := zapcore.NewCore(
 		zapcore.NewJSONEncoder(zapcore.EncoderConfig{
 			TimeKey:       "ts",
@@ -63,31 +113,73 @@ func NewZapLogger(env conf.Environment, logPath string, maxSize, maxAge, level,
 			EncodeCaller:   zapcore.ShortCallerEncoder,
 		}),
 		zapcore.NewMultiWriteSyncer(WriterSyncer...),
-		zap.NewAtomicLevelAt(logLevel),
+		atomicLevel,
 	)
 
-	core := zapcore.NewTee(coreInfo)
+	cores := []zapcore.Core{coreInfo}
+	if o.loggerProvider != nil {
+		otelCore, err := zapcore.NewIncreaseLevelCore(
+			otelzap.NewCore("github.com/go-kratos/kratos-layout/pkg/log", otelzap.WithLoggerProvider(o.loggerProvider)),
+			atomicLevel,
+		)
+		if err != nil {
+			panic(err)
+		}
+		cores = append(cores, otelCore)
+	}
+	core := zapcore.NewTee(cores...)
 
 	zapLogger := zap.New(core, zapOpts...)
 
 	return zapWrapper(func(level log.Level, keyvals ...interface{}) error {
+		// local to the call, the wrapper is shared by the goroutines
+		recordLevel := zap.InfoLevel
 		switch level {
 		case log.LevelDebug:
-			logLevel = zap.DebugLevel
+			recordLevel = zap.DebugLevel
 		case log.LevelInfo:
-			logLevel = zap.InfoLevel
+			recordLevel = zap.InfoLevel
 		case log.LevelWarn:
-			logLevel = zap.WarnLevel
+			recordLevel = zap.WarnLevel
 		case log.LevelError:
-			logLevel = zap.ErrorLevel
+			recordLevel = zap.ErrorLevel
 		case log.LevelFatal:
-			logLevel = zap.FatalLevel
+			recordLevel = zap.FatalLevel
 		}
 		var fields []zap.Field
+		var traceID, spanID string
 		for i := 0; i < len(keyvals); i += 2 {
-			fields = append(fields, zap.String(fmt.Sprintf("%v", keyvals[i]), fmt.Sprintf("%v", keyvals[i+1])))
+			key, value := fmt.Sprintf("%v", keyvals[i]), fmt.Sprintf("%v", keyvals[i+1])
+			switch key {
+			case traceIDKey:
+				traceID = value
+			case spanIDKey:
+				spanID = value
+			}
+			fields = append(fields, zap.String(key, value))
+		}
+		if o.loggerProvider != nil {
+			if ctx, ok := spanContext(traceID, spanID); ok {
+				// the OTel core reads the context field, the json encoder skips it
+				fields = append(fields, zap.Field{Key: "ctx", Type: zapcore.SkipType, Interface: ctx})
+			}
 		}
-		zapLogger.Log(logLevel, "log", fields...)
+		zapLogger.Log(recordLevel, "log", fields...)
 		return nil
 	})
 }
+
+// spanContext returns a context carrying the span of the logged ids, so the
+// OTel records are correlated with the traces.
+func spanContext(traceID, spanID string) (context.Context, bool) {
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return nil, false
+	}
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return nil, false
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: tid, SpanID: sid, Remote: true})
+	return trace.ContextWithSpanContext(context.Background(), sc), true
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/internal/conf"
+	"github.com/go-kratos/kratos/v2/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID  = "00f067aa0ba902b7"
+)
+
+// memoryExporter keeps the exported log records.
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(context.Context) error   { return nil }
+func (e *memoryExporter) ForceFlush(context.Context) error { return nil }
+
+func readLines(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]any
+		if err = json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("%s: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestStartupLevel(t *testing.T) {
+	tests := []struct {
+		env   conf.Environment
+		level int32
+		want  zapcore.Level
+	}{
+		{env: conf.Environment_DEV, level: int32(zapcore.ErrorLevel), want: zapcore.DebugLevel},
+		{env: conf.Environment_PRE, level: int32(zapcore.ErrorLevel), want: zapcore.DebugLevel},
+		{env: conf.Environment_PROD, level: int32(zapcore.WarnLevel), want: zapcore.WarnLevel},
+		{env: conf.Environment_PROD, want: zapcore.InfoLevel},
+	}
+	for _, tt := range tests {
+		if got := startupLevel(tt.env, tt.level); got != tt.want {
+			t.Errorf("startupLevel(%s, %d) = %s, want %s", tt.env, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestZapLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	exporter := &memoryExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	level := zap.NewAtomicLevel()
+	logger := NewZapLogger(conf.Environment_PROD, path, 1, 1, int32(zapcore.InfoLevel), 1,
+		WithAtomicLevel(level), WithLoggerProvider(provider))
+
+	_ = logger.Log(log.LevelDebug, "msg", "filtered")
+	_ = logger.Log(log.LevelInfo, "msg", "hello", traceIDKey, testTraceID, spanIDKey, testSpanID)
+	// the level is changed at runtime
+	level.SetLevel(zapcore.DebugLevel)
+	_ = logger.Log(log.LevelDebug, "msg", "debug")
+
+	lines := readLines(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %v", len(lines), lines)
+	}
+	if lines[0]["level"] != "INFO" || lines[0]["msg"] != "hello" || lines[0][traceIDKey] != testTraceID {
+		t.Errorf("line = %v, want the info record", lines[0])
+	}
+	if _, ok := lines[0]["ctx"]; ok {
+		t.Errorf("line = %v, the span context is encoded", lines[0])
+	}
+	if lines[1]["level"] != "DEBUG" || lines[1]["msg"] != "debug" {
+		t.Errorf("line = %v, want the debug record", lines[1])
+	}
+
+	if len(exporter.records) != 2 {
+		t.Fatalf("got %d otel records, want 2", len(exporter.records))
+	}
+	if r := exporter.records[0]; r.TraceID().String() != testTraceID || r.SpanID().String() != testSpanID {
+		t.Errorf("otel record ids = %s/%s, want %s/%s", r.TraceID(), r.SpanID(), testTraceID, testSpanID)
+	}
+	if r := exporter.records[1]; r.TraceID().IsValid() {
+		t.Errorf("otel record trace id = %s, want none", r.TraceID())
+	}
+}
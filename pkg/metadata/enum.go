@@ -1,7 +1,12 @@
 package metadata
 
-const KeyAuthorization = "Authorization"
+const (
+	KeyAuthorization = "Authorization"
+	KeyRequestID     = "X-Request-Id"
+)
 
 var Keys = []string{
 	KeyAuthorization,
+	KeyRequestID,
+	KeyTenantID,
 }
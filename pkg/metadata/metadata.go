@@ -72,11 +72,13 @@ func GetHttpRequestBody(ctx context.Context) ([]byte, bool) {
 	return bodyByte, true
 }
 
+// GetAccessToken returns the bearer token of the Authorization request header.
 func GetAccessToken(ctx context.Context) (string, error) {
-	auth, err := Get(ctx, KeyAuthorization)
-	if err != nil {
-		return "", err
+	header, ok := GetHeader(ctx)
+	if !ok {
+		return "", errors.New("no transport in context")
 	}
+	auth := header.Get(KeyAuthorization)
 	if len(auth) > 0 && strings.HasPrefix(auth, "Bearer ") {
 		return strings.TrimPrefix(auth, "Bearer "), nil
 	}
@@ -0,0 +1,31 @@
+package metadata
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+type requestIDKey struct{}
+
+// NewRequestIDContext returns a new context that carries the request id.
+func NewRequestIDContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id stored in ctx.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RequestID returns a log valuer of the request id.
+func RequestID() log.Valuer {
+	return func(ctx context.Context) any {
+		id, _ := RequestIDFromContext(ctx)
+		return id
+	}
+}
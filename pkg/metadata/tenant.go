@@ -0,0 +1,35 @@
+package metadata
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// KeyTenantID is the kratos global metadata key of the tenant, it is
+// propagated to the downstream services by the metadata client middleware.
+const KeyTenantID = "x-md-global-tenant-id"
+
+type tenantKey struct{}
+
+// NewTenantContext returns a new context that carries the tenant id.
+func NewTenantContext(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant id stored in ctx.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// Tenant returns a log valuer of the tenant id.
+func Tenant() log.Valuer {
+	return func(ctx context.Context) any {
+		tenant, _ := TenantFromContext(ctx)
+		return tenant
+	}
+}
@@ -0,0 +1,298 @@
+// Package metrics declares the business instruments on the shared meter.
+//
+// The labels of an instrument are a struct type, each string field is a
+// label named by its `label` tag or its snake cased name:
+//
+//	type orderLabels struct {
+//		Channel string `label:"channel"`
+//	}
+//	orders, err := metrics.NewCounter[orderLabels](m, "biz_orders_total")
+//	orders.Add(ctx, 1, orderLabels{Channel: "web"}, err)
+//
+// The operation, tenant and error reason labels are added from the context
+// and the error.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// DefaultCardinalityLimit is the default number of label value
+	// combinations per instrument.
+	DefaultCardinalityLimit = 1000
+	// OverflowValue replaces the label values beyond the cardinality limit.
+	OverflowValue = "__overflow__"
+
+	LabelOperation = "operation"
+	LabelTenant    = "tenant"
+	LabelReason    = "reason"
+)
+
+// Metrics creates the instruments on the meter.
+type Metrics struct {
+	meter metric.Meter
+	limit int
+}
+
+// Option is a Metrics option.
+type Option func(*Metrics)
+
+// WithCardinalityLimit limits the label value combinations of every instrument.
+func WithCardinalityLimit(limit int) Option {
+	return func(m *Metrics) {
+		if limit > 0 {
+			m.limit = limit
+		}
+	}
+}
+
+// New new a Metrics on the meter.
+func New(meter metric.Meter, opts ...Option) *Metrics {
+	m := &Metrics{meter: meter, limit: DefaultCardinalityLimit}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+type instrumentOptions struct {
+	description string
+	unit        string
+	buckets     []float64
+}
+
+// InstrumentOption is an instrument option.
+type InstrumentOption func(*instrumentOptions)
+
+// WithDescription sets the description of the instrument.
+func WithDescription(description string) InstrumentOption {
+	return func(o *instrumentOptions) {
+		o.description = description
+	}
+}
+
+// WithUnit sets the UCUM unit of the instrument, eg: s, By, {order}.
+func WithUnit(unit string) InstrumentOption {
+	return func(o *instrumentOptions) {
+		o.unit = unit
+	}
+}
+
+// WithBuckets sets the explicit bucket boundaries of the histogram.
+func WithBuckets(buckets ...float64) InstrumentOption {
+	return func(o *instrumentOptions) {
+		o.buckets = buckets
+	}
+}
+
+func newInstrumentOptions(opts []InstrumentOption) *instrumentOptions {
+	o := &instrumentOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Counter is a monotonic counter with the labels L.
+type Counter[L any] struct {
+	counter metric.Float64Counter
+	labels  *labelSet[L]
+}
+
+// NewCounter declares a counter.
+func NewCounter[L any](m *Metrics, name string, opts ...InstrumentOption) (*Counter[L], error) {
+	labels, err := newLabelSet[L](m.limit)
+	if err != nil {
+		return nil, fmt.Errorf("metric %s: %w", name, err)
+	}
+	o := newInstrumentOptions(opts)
+	counter, err := m.meter.Float64Counter(name, metric.WithDescription(o.description), metric.WithUnit(o.unit))
+	if err != nil {
+		return nil, err
+	}
+	return &Counter[L]{counter: counter, labels: labels}, nil
+}
+
+// Add adds v, err sets the reason label.
+func (c *Counter[L]) Add(ctx context.Context, v float64, labels L, err error) {
+	c.counter.Add(ctx, v, metric.WithAttributeSet(c.labels.attributes(ctx, labels, err)))
+}
+
+// Histogram is a distribution with the labels L.
+type Histogram[L any] struct {
+	histogram metric.Float64Histogram
+	labels    *labelSet[L]
+}
+
+// NewHistogram declares a histogram.
+func NewHistogram[L any](m *Metrics, name string, opts ...InstrumentOption) (*Histogram[L], error) {
+	labels, err := newLabelSet[L](m.limit)
+	if err != nil {
+		return nil, fmt.Errorf("metric %s: %w", name, err)
+	}
+	o := newInstrumentOptions(opts)
+	hopts := []metric.Float64HistogramOption{metric.WithDescription(o.description), metric.WithUnit(o.unit)}
+	if len(o.buckets) > 0 {
+		hopts = append(hopts, metric.WithExplicitBucketBoundaries(o.buckets...))
+	}
+	histogram, err := m.meter.Float64Histogram(name, hopts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Histogram[L]{histogram: histogram, labels: labels}, nil
+}
+
+// Record records v, err sets the reason label.
+func (h *Histogram[L]) Record(ctx context.Context, v float64, labels L, err error) {
+	h.histogram.Record(ctx, v, metric.WithAttributeSet(h.labels.attributes(ctx, labels, err)))
+}
+
+// Gauge is the last value with the labels L.
+type Gauge[L any] struct {
+	gauge  metric.Float64Gauge
+	labels *labelSet[L]
+}
+
+// NewGauge declares a gauge.
+func NewGauge[L any](m *Metrics, name string, opts ...InstrumentOption) (*Gauge[L], error) {
+	labels, err := newLabelSet[L](m.limit)
+	if err != nil {
+		return nil, fmt.Errorf("metric %s: %w", name, err)
+	}
+	o := newInstrumentOptions(opts)
+	gauge, err := m.meter.Float64Gauge(name, metric.WithDescription(o.description), metric.WithUnit(o.unit))
+	if err != nil {
+		return nil, err
+	}
+	return &Gauge[L]{gauge: gauge, labels: labels}, nil
+}
+
+// Record sets the value.
+func (g *Gauge[L]) Record(ctx context.Context, v float64, labels L) {
+	g.gauge.Record(ctx, v, metric.WithAttributeSet(g.labels.attributes(ctx, labels, nil)))
+}
+
+// labelSet maps the fields of L to the labels, and limits the combinations
+// of their values.
+type labelSet[L any] struct {
+	keys   []string
+	fields []int
+	limit  int
+
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+func newLabelSet[L any](limit int) (*labelSet[L], error) {
+	t := reflect.TypeFor[L]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("labels must be a struct: %s", t)
+	}
+	s := &labelSet[L]{limit: limit, seen: make(map[string]struct{})}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Type.Kind() != reflect.String {
+			return nil, fmt.Errorf("label %s must be a string", f.Name)
+		}
+		key := f.Tag.Get("label")
+		if key == "" {
+			key = snakeCase(f.Name)
+		}
+		switch key {
+		case LabelOperation, LabelTenant, LabelReason:
+			return nil, fmt.Errorf("label %s is reserved", key)
+		}
+		s.keys = append(s.keys, key)
+		s.fields = append(s.fields, i)
+	}
+	return s, nil
+}
+
+func (s *labelSet[L]) attributes(ctx context.Context, labels L, err error) attribute.Set {
+	v := reflect.ValueOf(labels)
+	values := make([]string, len(s.fields), len(s.fields)+3)
+	for i, f := range s.fields {
+		values[i] = v.Field(f).String()
+	}
+	var operation, reason string
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		operation = tr.Operation()
+	}
+	tenant, _ := metadata.TenantFromContext(ctx)
+	if err != nil {
+		reason = errors.Reason(err)
+		if reason == "" {
+			reason = "UNKNOWN"
+		}
+	}
+	// the tenant and the reason are set by the callers, they count in the limit too
+	values = append(values, operation, tenant, reason)
+	if !s.allow(strings.Join(values, "\x00")) {
+		for i := range values {
+			values[i] = OverflowValue
+		}
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(values))
+	for i, key := range s.keys {
+		attrs = append(attrs, attribute.String(key, values[i]))
+	}
+	n := len(s.keys)
+	attrs = append(attrs,
+		attribute.String(LabelOperation, values[n]),
+		attribute.String(LabelTenant, values[n+1]),
+		attribute.String(LabelReason, values[n+2]),
+	)
+	return attribute.NewSet(attrs...)
+}
+
+// allow reports whether the combination is within the cardinality limit.
+func (s *labelSet[L]) allow(combination string) bool {
+	s.mu.RLock()
+	_, ok := s.seen[combination]
+	n := len(s.seen)
+	s.mu.RUnlock()
+	if ok {
+		return true
+	}
+	if n >= s.limit {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.seen) >= s.limit {
+		_, ok = s.seen[combination]
+		return ok
+	}
+	s.seen[combination] = struct{}{}
+	return true
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+	"github.com/go-kratos/kratos/v2/errors"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type orderLabels struct {
+	Channel string `label:"channel"`
+	PayType string
+	ignored string
+}
+
+func TestLabelSetAllow(t *testing.T) {
+	s, err := newLabelSet[orderLabels](2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		combination string
+		allowed     bool
+	}{
+		{combination: "a", allowed: true},
+		{combination: "a", allowed: true},
+		{combination: "b", allowed: true},
+		{combination: "c", allowed: false},
+		{combination: "a", allowed: true},
+		{combination: "b", allowed: true},
+		{combination: "d", allowed: false},
+	}
+	for i, tt := range tests {
+		if got := s.allow(tt.combination); got != tt.allowed {
+			t.Errorf("%d: allow(%q) = %v, want %v", i, tt.combination, got, tt.allowed)
+		}
+	}
+}
+
+func TestLabelSetAttributes(t *testing.T) {
+	s, err := newLabelSet[orderLabels](2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := orderLabels{Channel: "web", PayType: "card"}
+	tests := []struct {
+		name   string
+		tenant string
+		labels orderLabels
+		err    error
+		want   map[string]string
+	}{
+		{name: "first", tenant: "acme", labels: web,
+			want: map[string]string{"channel": "web", "pay_type": "card", LabelTenant: "acme", LabelReason: ""}},
+		{name: "reason", tenant: "acme", labels: web, err: errors.BadRequest("OUT_OF_STOCK", ""),
+			want: map[string]string{"channel": "web", "pay_type": "card", LabelTenant: "acme", LabelReason: "OUT_OF_STOCK"}},
+		{name: "tenant overflow", tenant: "other", labels: web,
+			want: map[string]string{"channel": OverflowValue, "pay_type": OverflowValue, LabelTenant: OverflowValue, LabelReason: OverflowValue}},
+		{name: "reason overflow", tenant: "acme", labels: web, err: errors.BadRequest("INVALID", ""),
+			want: map[string]string{"channel": OverflowValue, LabelTenant: OverflowValue, LabelReason: OverflowValue}},
+		{name: "seen", tenant: "acme", labels: web,
+			want: map[string]string{"channel": "web", LabelTenant: "acme"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := s.attributes(metadata.NewTenantContext(context.Background(), tt.tenant), tt.labels, tt.err)
+			for key, want := range tt.want {
+				if got, _ := set.Value(attribute.Key(key)); got.AsString() != want {
+					t.Errorf("%s = %q, want %q", key, got.AsString(), want)
+				}
+			}
+			if set.HasValue("ignored") {
+				t.Error("unexported field labeled")
+			}
+		})
+	}
+}
+
+func TestNewLabelSet(t *testing.T) {
+	tests := []struct {
+		name string
+		new  func() error
+		err  bool
+	}{
+		{name: "struct", new: func() error { _, err := newLabelSet[orderLabels](1); return err }},
+		{name: "not a struct", new: func() error { _, err := newLabelSet[string](1); return err }, err: true},
+		{name: "not a string", new: func() error { _, err := newLabelSet[struct{ Count int }](1); return err }, err: true},
+		{name: "reserved", new: func() error {
+			_, err := newLabelSet[struct {
+				Tenant string `label:"tenant"`
+			}](1)
+			return err
+		}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.new(); (err != nil) != tt.err {
+				t.Errorf("newLabelSet() error = %v, want error %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Package signature implements the HMAC-SHA256 request signing shared by the
+// server authenticator and the Go callers.
+//
+// The signature is the hex encoded HMAC-SHA256 of:
+//
+//	METHOD \n REQUEST_URI \n TIMESTAMP \n NONCE \n hex(sha256(BODY))
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderKeyID     = "X-Key-Id"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// StringToSign returns the canonical string of the request.
+func StringToSign(method, requestURI, timestamp, nonce string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		timestamp,
+		nonce,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the string to sign.
+func Sign(secret, stringToSign string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(stringToSign))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature matches, in constant time.
+func Verify(secret, stringToSign, signature string) bool {
+	expected, err := hex.DecodeString(Sign(secret, stringToSign))
+	if err != nil {
+		return false
+	}
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, actual)
+}
+
+// Signer signs the outgoing http requests.
+type Signer struct {
+	KeyID  string
+	Secret string
+}
+
+// NewSigner new a Signer.
+func NewSigner(keyID, secret string) *Signer {
+	return &Signer{KeyID: keyID, Secret: secret}
+}
+
+// Sign sets the signature headers of r, the body is read and restored.
+func (s *Signer) Sign(r *http.Request) error {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return err
+		}
+		_ = r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	r.Header.Set(HeaderKeyID, s.KeyID)
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, Sign(s.Secret, StringToSign(r.Method, r.URL.RequestURI(), timestamp, nonce, body)))
+	return nil
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Transport signs every request before sending it with the base transport,
+// eg: http.NewClient(ctx, http.WithTransport(signature.NewTransport(nil, keyID, secret)))
+type Transport struct {
+	Base   http.RoundTripper
+	Signer *Signer
+}
+
+// NewTransport new a signing Transport, base defaults to http.DefaultTransport.
+func NewTransport(base http.RoundTripper, keyID, secret string) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, Signer: NewSigner(keyID, secret)}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request
+	r = r.Clone(r.Context())
+	if err := t.Signer.Sign(r); err != nil {
+		return nil, err
+	}
+	return t.Base.RoundTrip(r)
+}
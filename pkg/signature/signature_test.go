@@ -0,0 +1,75 @@
+package signature
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	sts := StringToSign("post", "/v1/greeter?name=kratos", "1700000000", "nonce", []byte(`{"name":"kratos"}`))
+	sig := Sign("secret", sts)
+	tests := []struct {
+		name      string
+		secret    string
+		sts       string
+		signature string
+		ok        bool
+	}{
+		{name: "valid", secret: "secret", sts: sts, signature: sig, ok: true},
+		{name: "upper hex", secret: "secret", sts: sts, signature: strings.ToUpper(sig), ok: true},
+		{name: "wrong secret", secret: "other", sts: sts, signature: sig},
+		{name: "method", secret: "secret", sts: StringToSign("GET", "/v1/greeter?name=kratos", "1700000000", "nonce", []byte(`{"name":"kratos"}`)), signature: sig},
+		{name: "query", secret: "secret", sts: StringToSign("POST", "/v1/greeter?name=root", "1700000000", "nonce", []byte(`{"name":"kratos"}`)), signature: sig},
+		{name: "timestamp", secret: "secret", sts: StringToSign("POST", "/v1/greeter?name=kratos", "1700000001", "nonce", []byte(`{"name":"kratos"}`)), signature: sig},
+		{name: "nonce", secret: "secret", sts: StringToSign("POST", "/v1/greeter?name=kratos", "1700000000", "other", []byte(`{"name":"kratos"}`)), signature: sig},
+		{name: "body", secret: "secret", sts: StringToSign("POST", "/v1/greeter?name=kratos", "1700000000", "nonce", []byte(`{"name":"root"}`)), signature: sig},
+		{name: "truncated", secret: "secret", sts: sts, signature: sig[:32]},
+		{name: "not hex", secret: "secret", sts: sts, signature: "zz" + sig[2:]},
+		{name: "empty", secret: "secret", sts: sts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.sts, tt.signature); got != tt.ok {
+				t.Errorf("Verify() = %v, want %v", got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, "batch", "secret")}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/greeter?name=kratos", strings.NewReader(`{"name":"kratos"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if req.Header.Get(HeaderSignature) != "" {
+		t.Error("the transport modified the request")
+	}
+	if got.Header.Get(HeaderKeyID) != "batch" {
+		t.Errorf("%s = %q, want batch", HeaderKeyID, got.Header.Get(HeaderKeyID))
+	}
+	if string(body) != `{"name":"kratos"}` {
+		t.Errorf("body = %q, not restored", body)
+	}
+	sts := StringToSign(got.Method, got.URL.RequestURI(), got.Header.Get(HeaderTimestamp), got.Header.Get(HeaderNonce), body)
+	if !Verify("secret", sts, got.Header.Get(HeaderSignature)) {
+		t.Error("the signature does not verify on the server")
+	}
+}
@@ -0,0 +1,81 @@
+// Package trace tags the current span from the biz code, without importing
+// OpenTelemetry:
+//
+//	trace.SetAttributes(ctx, "order.id", order.ID, "order.amount", order.Amount)
+package trace
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SetAttributes sets the key value pairs on the span of the context.
+func SetAttributes(ctx context.Context, keyvals ...any) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(attributes(keyvals)...)
+}
+
+// AddEvent adds the event with the key value pairs to the span of the context.
+func AddEvent(ctx context.Context, name string, keyvals ...any) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent(name, trace.WithAttributes(attributes(keyvals)...))
+}
+
+// TraceID returns the trace id of the context, empty without a span.
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+func attributes(keyvals []any) []attribute.KeyValue {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, "KEYVALS UNPAIRED")
+	}
+	attrs := make([]attribute.KeyValue, 0, len(keyvals)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		attrs = append(attrs, attributeOf(fmt.Sprint(keyvals[i]), keyvals[i+1]))
+	}
+	return attrs
+}
+
+func attributeOf(key string, v any) attribute.KeyValue {
+	switch v := v.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
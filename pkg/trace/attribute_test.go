@@ -0,0 +1,88 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetAttributes(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "order")
+
+	SetAttributes(ctx,
+		"string", "a",
+		"bool", true,
+		"int", 1,
+		"int32", int32(2),
+		"int64", int64(3),
+		"uint32", uint32(4),
+		"float32", float32(0.5),
+		"float64", 1.5,
+		"strings", []string{"a", "b"},
+		"int64s", []int64{1, 2},
+		"error", errors.New("boom"),
+		"stringer", net.IPv4(127, 0, 0, 1),
+		"default", struct{ ID int }{ID: 7},
+		"unpaired",
+	)
+	AddEvent(ctx, "paid", "amount", 9.9)
+	span.End()
+
+	ended := sr.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(ended))
+	}
+	want := []attribute.KeyValue{
+		attribute.String("string", "a"),
+		attribute.Bool("bool", true),
+		attribute.Int("int", 1),
+		attribute.Int64("int32", 2),
+		attribute.Int64("int64", 3),
+		attribute.Int64("uint32", 4),
+		attribute.Float64("float32", 0.5),
+		attribute.Float64("float64", 1.5),
+		attribute.StringSlice("strings", []string{"a", "b"}),
+		attribute.Int64Slice("int64s", []int64{1, 2}),
+		attribute.String("error", "boom"),
+		attribute.String("stringer", "127.0.0.1"),
+		attribute.String("default", "{7}"),
+		attribute.String("unpaired", "KEYVALS UNPAIRED"),
+	}
+	got := ended[0].Attributes()
+	if len(got) != len(want) {
+		t.Fatalf("attributes = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Key != want[i].Key || got[i].Value.Emit() != want[i].Value.Emit() || got[i].Value.Type() != want[i].Value.Type() {
+			t.Errorf("attribute[%d] = %s=%s, want %s=%s", i, got[i].Key, got[i].Value.Emit(), want[i].Key, want[i].Value.Emit())
+		}
+	}
+
+	events := ended[0].Events()
+	if len(events) != 1 || events[0].Name != "paid" {
+		t.Fatalf("events = %v, want paid", events)
+	}
+	if attrs := events[0].Attributes; len(attrs) != 1 || attrs[0] != attribute.Float64("amount", 9.9) {
+		t.Errorf("event attributes = %v, want amount=9.9", attrs)
+	}
+	if id := TraceID(ctx); id != ended[0].SpanContext().TraceID().String() {
+		t.Errorf("TraceID() = %q, want the span trace id", id)
+	}
+}
+
+func TestNoSpan(t *testing.T) {
+	ctx := context.Background()
+	// no-ops without a recording span
+	SetAttributes(ctx, "key", "value")
+	AddEvent(ctx, "event")
+	if id := TraceID(ctx); id != "" {
+		t.Errorf("TraceID() = %q, want empty", id)
+	}
+}
@@ -33,5 +33,5 @@ func WrapTrace(ctx context.Context, tracer trace.Tracer, message *sarama.Consume
 	}
 	newCtx, span := tracer.Start(parentSpanContext, fmt.Sprintf("%s handle", message.Topic), opts...)
 	defer span.End()
-	return fn(newCtx, message)
+	return fn(ExtractRequestID(newCtx, message), message)
 }
@@ -0,0 +1,37 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+)
+
+// InjectRequestID adds the request id in ctx to the message headers.
+func InjectRequestID(ctx context.Context, message *sarama.ProducerMessage) {
+	id, ok := metadata.RequestIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	for i, h := range message.Headers {
+		if string(h.Key) == metadata.KeyRequestID {
+			message.Headers[i].Value = []byte(id)
+			return
+		}
+	}
+	message.Headers = append(message.Headers, sarama.RecordHeader{
+		Key:   []byte(metadata.KeyRequestID),
+		Value: []byte(id),
+	})
+}
+
+// ExtractRequestID returns a new context that carries the request id found in
+// the message headers.
+func ExtractRequestID(ctx context.Context, message *sarama.ConsumerMessage) context.Context {
+	for _, h := range message.Headers {
+		if h != nil && string(h.Key) == metadata.KeyRequestID && len(h.Value) > 0 {
+			return metadata.NewRequestIDContext(ctx, string(h.Value))
+		}
+	}
+	return ctx
+}
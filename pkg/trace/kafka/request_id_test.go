@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/go-kratos/kratos-layout/pkg/metadata"
+)
+
+func TestRequestIDPropagation(t *testing.T) {
+	ctx := metadata.NewRequestIDContext(context.Background(), "req-1")
+	message := &sarama.ProducerMessage{Headers: []sarama.RecordHeader{
+		{Key: []byte(metadata.KeyRequestID), Value: []byte("stale")},
+	}}
+	InjectRequestID(ctx, message)
+	if len(message.Headers) != 1 || string(message.Headers[0].Value) != "req-1" {
+		t.Fatalf("headers = %v, want the request id replaced", message.Headers)
+	}
+
+	consumed := &sarama.ConsumerMessage{Headers: []*sarama.RecordHeader{&message.Headers[0]}}
+	if got, _ := metadata.RequestIDFromContext(ExtractRequestID(context.Background(), consumed)); got != "req-1" {
+		t.Errorf("extracted request id = %q, want req-1", got)
+	}
+}
+
+func TestRequestIDMissing(t *testing.T) {
+	message := &sarama.ProducerMessage{}
+	InjectRequestID(context.Background(), message)
+	if len(message.Headers) != 0 {
+		t.Errorf("headers = %v, want none", message.Headers)
+	}
+	if _, ok := metadata.RequestIDFromContext(ExtractRequestID(context.Background(), &sarama.ConsumerMessage{})); ok {
+		t.Error("extracted a request id from a message without one")
+	}
+}